@@ -0,0 +1,117 @@
+package mantau
+
+import "testing"
+
+type allocAddress struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+	Code   string `json:"code"`
+}
+
+type allocUser struct {
+	ID      int           `json:"id"`
+	Name    string        `json:"name"`
+	Email   string        `json:"email"`
+	Active  bool          `json:"active"`
+	Score   float64       `json:"score"`
+	Address *allocAddress `json:"address"`
+}
+
+var (
+	allocFlatSchema = Schema{
+		"id":     Field{Key: "id"},
+		"name":   Field{Key: "name"},
+		"email":  Field{Key: "email"},
+		"active": Field{Key: "active"},
+		"score":  Field{Key: "score"},
+	}
+
+	allocNestedSchema = Schema{
+		"id":   Field{Key: "id"},
+		"name": Field{Key: "name"},
+		"address": Field{Key: "address", Value: Schema{
+			"street": Field{Key: "street"},
+			"city":   Field{Key: "city"},
+			"code":   Field{Key: "code"},
+		}},
+	}
+)
+
+func allocFixture() allocUser {
+	return allocUser{
+		ID:      1,
+		Name:    "John Doe",
+		Email:   "john@example.com",
+		Active:  true,
+		Score:   9.5,
+		Address: &allocAddress{Street: "Main street", City: "Springfield", Code: "12345"},
+	}
+}
+
+func allocSlice() []allocUser {
+	users := make([]allocUser, 1000)
+
+	for i := range users {
+		users[i] = allocFixture()
+	}
+
+	return users
+}
+
+// allocBudgets are the maximum allocations of a transformation with every feature off, raise them only with a reason.
+// The result object takes 2 of them, the state of the call 1 and the values of every object 1
+var allocBudgets = []struct {
+	name   string
+	src    func() interface{}
+	schema Schema
+	max    float64
+}{
+	{"flat struct", func() interface{} { return allocFixture() }, allocFlatSchema, 4},
+	{"nested struct", func() interface{} { return allocFixture() }, allocNestedSchema, 12},
+	{"1k-element slice", func() interface{} { return allocSlice() }, allocFlatSchema, 4020},
+}
+
+func TestAllocBudgets(t *testing.T) {
+	m := New()
+
+	for _, budget := range allocBudgets {
+		src := budget.src()
+
+		allocs := testing.AllocsPerRun(10, func() {
+			m.Transform(src, budget.schema)
+		})
+
+		if allocs > budget.max {
+			t.Errorf("%s: %v allocations exceed the budget of %v", budget.name, allocs, budget.max)
+		}
+	}
+}
+
+func benchmarkTransform(b *testing.B, src interface{}, schema Schema, opts ...Option) {
+	m := New().With(opts...)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := m.Transform(src, schema); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTransformFlatStruct(b *testing.B) {
+	benchmarkTransform(b, allocFixture(), allocFlatSchema)
+}
+
+func BenchmarkTransformNestedStruct(b *testing.B) {
+	benchmarkTransform(b, allocFixture(), allocNestedSchema)
+}
+
+func BenchmarkTransformSlice(b *testing.B) {
+	benchmarkTransform(b, allocSlice(), allocFlatSchema)
+}
+
+func BenchmarkTransformSharedSchemaCache(b *testing.B) {
+	benchmarkTransform(b, allocFixture(), allocFlatSchema, WithSharedSchemaCache(true))
+}
@@ -0,0 +1,34 @@
+package mantau
+
+// TransformAny works like Transform but accepts any value produced by json.Unmarshal into an interface{},
+// including top level collections and primitives. Primitives of a []interface{} are kept as they are
+// and nested collections are transformed element by element
+func (m *mantau) TransformAny(src interface{}, schema Schema) (interface{}, error) {
+	c := m.withState()
+
+	return c.finish(c.transformValue(src, schema))
+}
+
+// transformInterfaces will transform every element of a []interface{} with the given schema.
+// Unlike transformCollections, elements that are not objects are kept
+func (m *mantau) transformInterfaces(src []interface{}, schema Schema) ([]interface{}, error) {
+	result := make([]interface{}, 0, len(src))
+
+	for i, element := range src {
+		if m.isTruncated() {
+			break
+		}
+
+		m.enterIndex(i)
+		v, err := m.transformValue(element, schema)
+		m.leave()
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, v)
+	}
+
+	return result, nil
+}
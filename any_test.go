@@ -0,0 +1,69 @@
+package mantau
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformAny(t *testing.T) {
+	var src interface{}
+
+	err := json.Unmarshal([]byte(`[
+		{
+			"name": "John doe",
+			"age": 30,
+			"tags": ["admin", 1, true, null],
+			"matrix": [[1, 2], [3]],
+			"permissions": [{"code": 1, "name": "Admin"}, {"code": 2, "name": "Customer"}]
+		}
+	]`), &src)
+
+	assert.NoError(t, err, "Should not return any error")
+
+	result, err := New().TransformAny(src, Schema{
+		"username": Field{Key: "name"},
+		"age":      Field{Key: "age"},
+		"tags":     Field{Key: "tags"},
+		"matrix":   Field{Key: "matrix"},
+		"permissions": Field{
+			Key:   "permissions",
+			Value: Schema{"code": Field{Key: "code"}},
+		},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, []interface{}{
+		Result{
+			"username": "John doe",
+			"age":      float64(30),
+			"tags":     []interface{}{"admin", float64(1), true, nil},
+			"matrix":   []interface{}{[]interface{}{float64(1), float64(2)}, []interface{}{float64(3)}},
+			"permissions": []interface{}{
+				Result{"code": float64(1)},
+				Result{"code": float64(2)},
+			},
+		},
+	}, result, "The result do not match")
+
+	result, err = New().TransformAny("hello", nil)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, "hello", result, "Primitives should be returned as they are")
+}
+
+func TestTransformAnyNumber(t *testing.T) {
+	var src interface{}
+
+	decoder := json.NewDecoder(strings.NewReader(`{"id": 9007199254740993}`))
+	decoder.UseNumber()
+
+	assert.NoError(t, decoder.Decode(&src), "Should not return any error")
+
+	result, err := New().TransformAny(src, Schema{"id": Field{Key: "id"}})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"id": json.Number("9007199254740993")}, result, "json.Number should be kept")
+}
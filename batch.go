@@ -0,0 +1,79 @@
+package mantau
+
+import "sync"
+
+// BatchFunc looks up the values of several keys at once e.g. the names of a list of user ids.
+// Keys missing from the returned map are omitted
+type BatchFunc func(keys []interface{}) (map[interface{}]interface{}, error)
+
+// Batcher collects the keys of lazy values and looks them up with a single call, so enriching
+// every element of a collection doesn't make a call per element. A batcher caches the looked up values,
+// create one per transformation
+type Batcher struct {
+	mu      sync.Mutex
+	fn      BatchFunc
+	pending []interface{}
+	queued  map[interface{}]bool
+	values  map[interface{}]interface{}
+	errs    map[interface{}]error
+}
+
+// NewBatcher will create a batcher looking up keys with the given function
+func NewBatcher(fn BatchFunc) *Batcher {
+	return &Batcher{
+		fn:     fn,
+		queued: map[interface{}]bool{},
+		values: map[interface{}]interface{}{},
+		errs:   map[interface{}]error{},
+	}
+}
+
+// Load will queue the key and return a lazy value resolved by the batch lookup. Every key queued
+// before the first value is resolved is looked up with the same call
+func (b *Batcher) Load(key interface{}) *LazyValue {
+	b.mu.Lock()
+
+	if !b.queued[key] {
+		b.queued[key] = true
+		b.pending = append(b.pending, key)
+	}
+
+	b.mu.Unlock()
+
+	return Lazy(func() (interface{}, error) {
+		return b.get(key)
+	})
+}
+
+// get will return the value of the key, looking up the pending keys when it's not loaded yet
+func (b *Batcher) get(key interface{}) (interface{}, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err, ok := b.errs[key]; ok {
+		return nil, err
+	}
+
+	if value, ok := b.values[key]; ok || len(b.pending) == 0 {
+		return value, nil
+	}
+
+	keys := b.pending
+	b.pending = nil
+	values, err := b.fn(keys)
+
+	for _, k := range keys {
+		if err != nil {
+			b.errs[k] = err
+			continue
+		}
+
+		b.values[k] = values[k]
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return b.values[key], nil
+}
@@ -0,0 +1,47 @@
+package mantau
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatcher(t *testing.T) {
+	calls := [][]interface{}{}
+	names := map[interface{}]interface{}{1: "John", 2: "Jane"}
+
+	authors := NewBatcher(func(keys []interface{}) (map[interface{}]interface{}, error) {
+		calls = append(calls, keys)
+		return names, nil
+	})
+
+	posts := []map[string]interface{}{}
+
+	for _, id := range []int{1, 2, 1, 3} {
+		posts = append(posts, map[string]interface{}{"author_id": id, "author": authors.Load(id)})
+	}
+
+	result, err := New().Transform(posts, Schema{
+		"author": Field{Key: "author"},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, []Result{{"author": "John"}, {"author": "Jane"}, {"author": "John"}, {}}, result, "The result do not match")
+	assert.Equal(t, [][]interface{}{{1, 2, 3}}, calls, "Every key should be looked up with a single call")
+}
+
+func TestBatcherError(t *testing.T) {
+	errLookup := errors.New("Lookup failed")
+
+	authors := NewBatcher(func(keys []interface{}) (map[interface{}]interface{}, error) {
+		return nil, errLookup
+	})
+
+	_, err := New().Transform([]map[string]interface{}{
+		{"author": authors.Load(1)},
+		{"author": authors.Load(2)},
+	}, Schema{"author": Field{Key: "author"}})
+
+	assert.True(t, errors.Is(err, errLookup), "Should return the lookup error")
+}
@@ -0,0 +1,144 @@
+package mantau
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+)
+
+// Cache stores transformed objects, see Options.Cache. It must be safe for concurrent use
+// when the instance is used concurrently
+type Cache interface {
+	Get(key string) (Result, bool)
+	Set(key string, result Result)
+}
+
+// CacheKeyer is implemented by source values that can be cached, the key identifies the value
+// and it's version e.g. "user:42:1700000000"
+type CacheKeyer interface {
+	CacheKey() string
+}
+
+// cacheOptions are the options changing the transformed objects, so instances sharing a cache
+// with e.g. different clearances don't read each other's objects
+type cacheOptions struct {
+	Hook               string
+	NamespaceSeparator string
+	LargeInts          LargeIntPolicy
+	EmptyCollections   EmptyPolicy
+	NonFinite          NonFinitePolicy
+	NilNested          NilPolicy
+	RawLeafTypes       bool
+	MaxResultBytes     int
+	ResultLimit        LimitPolicy
+	Mismatch           MismatchPolicy
+	Clearance          Classification
+	Enforcement        EnforcementPolicy
+	DefaultCountryCode string
+	TimeKeyLayout      string
+	KeepNil            bool
+	KeepZero           bool
+	Unsupported        UnsupportedPolicy
+	Locale             string
+	ContextLocale      string
+	Collisions         CollisionPolicy
+}
+
+// cached will look up the transformed value in the cache, when the source value implements CacheKeyer.
+// The cache key is the key of the value followed by the hash of the schema and of the options changing the output.
+// Schemas calling callbacks or injecting values are not cached, the hash cannot tell them apart, neither are
+// the pages of TransformPage. Instances with a SecretScanner don't use the cache, their objects must be scanned.
+// A cached object is counted against Options.MaxResultBytes, it's transformed again when it doesn't fit
+func (m *mantau) cached(src interface{}, schema Schema, fn func() (interface{}, error)) (interface{}, error) {
+	keyer, ok := src.(CacheKeyer)
+
+	if !ok || m.opt.Cache == nil || m.opt.SecretScanner != nil || schema.paged() || schema.callsFuncs() {
+		return fn()
+	}
+
+	key := keyer.CacheKey() + ":" + m.schemaHash(schema) + m.optionsHash()
+
+	if result, ok := m.opt.Cache.Get(key); ok && m.reuseSize(result) {
+		return result, nil
+	}
+
+	value, err := fn()
+
+	if err != nil {
+		return nil, err
+	}
+
+	// A truncated object is missing fields, it's not cached
+	if result, ok := value.(Result); ok && !m.isTruncated() {
+		m.opt.Cache.Set(key, result)
+	}
+
+	return value, nil
+}
+
+// schemaHash will return the hash of the schema, it's computed once per transformation
+func (m *mantau) schemaHash(schema Schema) string {
+	if m.state == nil {
+		return schema.Hash()
+	}
+
+	pointer := reflect.ValueOf(schema).Pointer()
+
+	if hash, ok := m.state.hashes[pointer]; ok {
+		return hash
+	}
+
+	if m.state.hashes == nil {
+		m.state.hashes = map[uintptr]string{}
+	}
+
+	hash := schema.Hash()
+	m.state.hashes[pointer] = hash
+
+	return hash
+}
+
+// optionsHash will return the hash of the options changing the output prefixed by ":", or nothing
+// with the default options. It's computed once per transformation
+func (m *mantau) optionsHash() string {
+	if m.state != nil && m.state.optionsHash != nil {
+		return *m.state.optionsHash
+	}
+
+	options := cacheOptions{
+		Hook:               m.opt.Hook,
+		NamespaceSeparator: m.opt.NamespaceSeparator,
+		LargeInts:          m.opt.LargeInts,
+		EmptyCollections:   m.opt.EmptyCollections,
+		NonFinite:          m.opt.NonFinite,
+		NilNested:          m.opt.NilNested,
+		RawLeafTypes:       m.opt.RawLeafTypes,
+		MaxResultBytes:     m.opt.MaxResultBytes,
+		ResultLimit:        m.opt.ResultLimit,
+		Mismatch:           m.opt.Mismatch,
+		Clearance:          m.opt.Clearance,
+		Enforcement:        m.opt.Enforcement,
+		DefaultCountryCode: m.opt.DefaultCountryCode,
+		TimeKeyLayout:      m.opt.TimeKeyLayout,
+		KeepNil:            m.opt.KeepNil,
+		KeepZero:           m.opt.KeepZero,
+		Unsupported:        m.opt.Unsupported,
+		Locale:             m.opt.Locale,
+		ContextLocale:      m.contextLocale(),
+		Collisions:         m.opt.Collisions,
+	}
+
+	hash := ""
+
+	if options != (cacheOptions{Hook: "json"}) {
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%+v", options)))
+		hash = ":" + hex.EncodeToString(sum[:8])
+	}
+
+	if m.state != nil {
+		m.state.optionsHash = &hash
+	}
+
+	return hash
+}
@@ -0,0 +1,50 @@
+package mantau
+
+import "fmt"
+
+// Redacted is emitted instead of the value of a field classified above the clearance with EnforceRedact
+const Redacted = "[REDACTED]"
+
+// clearanceLevels are the ranks of the classifications, from the least to the most sensitive
+var clearanceLevels = map[Classification]int{
+	ClassPublic: 0,
+	ClassPII:    1,
+	ClassSecret: 2,
+}
+
+// checkClearance will check if a field classified above the clearance can be emitted.
+// It returns true when the value should be redacted
+func (m *mantau) checkClearance(key string, field Field) (bool, error) {
+	if m.opt.Clearance == "" || field.Classification == "" {
+		return false, nil
+	}
+
+	if field.Classification == m.opt.Clearance || classificationLevel(field.Classification) <= clearanceLevel(m.opt.Clearance) {
+		return false, nil
+	}
+
+	if m.opt.Enforcement == EnforceRedact {
+		return true, nil
+	}
+
+	return false, fmt.Errorf("%w: field %q is %s but the clearance is %s", ErrClassified, key, field.Classification, m.opt.Clearance)
+}
+
+// classificationLevel will rank the classification of a field, an unknown classification e.g. a typo
+// is ranked as the most sensitive so it's never emitted by mistake
+func classificationLevel(class Classification) int {
+	if level, ok := clearanceLevels[class]; ok {
+		return level
+	}
+
+	return len(clearanceLevels)
+}
+
+// clearanceLevel will rank the clearance of the caller, an unknown clearance is ranked below public
+func clearanceLevel(clearance Classification) int {
+	if level, ok := clearanceLevels[clearance]; ok {
+		return level
+	}
+
+	return -1
+}
@@ -0,0 +1,59 @@
+package mantau
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ClassifiedUser struct {
+	Name     string `json:"name"`
+	Email    string `json:"email,class=pii"`
+	Password string `json:"password,class=secret"`
+}
+
+func TestClassification(t *testing.T) {
+	src := ClassifiedUser{Name: "John doe", Email: "john@doe.com", Password: "secret"}
+	schema := Schema{
+		"name":  Field{Key: "name", Classification: ClassPublic},
+		"email": Field{Key: "email"},
+	}
+
+	result, err := New().Transform(src, schema)
+
+	assert.NoError(t, err, "Classifications should not be enforced without a clearance")
+	assert.Equal(t, Result{"name": "John doe", "email": "john@doe.com"}, result, "The result do not match")
+
+	_, err = New().With(WithClearance(ClassPublic, EnforceError)).Transform(src, schema)
+
+	assert.True(t, errors.Is(err, ErrClassified), "Should return a classified error")
+
+	result, err = New().With(WithClearance(ClassPublic, EnforceRedact)).Transform(src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John doe", "email": Redacted}, result, "The field should be redacted")
+
+	schema["password"] = Field{Key: "password"}
+	result, err = New().With(WithClearance(ClassPII, EnforceRedact)).Transform(src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John doe", "email": "john@doe.com", "password": Redacted}, result, "The result do not match")
+
+	schema = Schema{
+		"name":  Field{Key: "name", Classification: "secret "},
+		"email": Field{Key: "email", Classification: "phi"},
+	}
+	result, err = New().With(WithClearance(ClassSecret, EnforceRedact)).Transform(src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": Redacted, "email": Redacted}, result, "Unknown classifications should be the most sensitive")
+
+	result, err = New().With(WithClearance("publc", EnforceRedact)).Transform(src, Schema{
+		"name":  Field{Key: "name", Classification: ClassPublic},
+		"email": Field{Key: "email"},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": Redacted, "email": Redacted}, result, "An unknown clearance should be the least privileged")
+}
@@ -0,0 +1,88 @@
+package mantau
+
+import (
+	"fmt"
+	"sort"
+)
+
+// BreakingChanges will compare the schema with the next version of it and describe every output key
+// that is removed or retyped, ordered by their dotted path. Added keys are not breaking changes
+func (s Schema) BreakingChanges(next Schema) []string {
+	changes := []string{}
+	s.breakingChanges("", next, &changes)
+	sort.Strings(changes)
+
+	return changes
+}
+
+// breakingChanges will add the breaking changes of the nested schema under the given path
+func (s Schema) breakingChanges(path string, next Schema, changes *[]string) {
+	s, next = s.outputSchema(), next.outputSchema()
+
+	for key, field := range s {
+		if field.isReserved() || field.drop {
+			continue
+		}
+
+		keyPath := joinPath(path, key)
+		nextField, ok := next[key]
+
+		if !ok || nextField.isReserved() || nextField.drop {
+			*changes = append(*changes, fmt.Sprintf("%s: removed", keyPath))
+			continue
+		}
+
+		if shape, nextShape := field.shape(), nextField.shape(); shape != nextShape {
+			*changes = append(*changes, fmt.Sprintf("%s: retyped from %s to %s", keyPath, shape, nextShape))
+			continue
+		}
+
+		nested, ok := field.Value.(Schema)
+		nextNested, nextOk := nextField.Value.(Schema)
+
+		if ok && nextOk {
+			nested.breakingChanges(keyPath, nextNested, changes)
+		}
+	}
+}
+
+// shape will describe the type of the value emitted by the field
+func (f Field) shape() string {
+	shape := f.baseShape()
+
+	// integers above 2^53 - 1 are emitted as strings
+	if f.LargeInts == LargeIntString && (shape == "value" || shape == "int") {
+		return shape + " or string"
+	}
+
+	return shape
+}
+
+// baseShape will describe the type of the value emitted by the field, regardless of how large integers are emitted
+func (f Field) baseShape() string {
+	switch {
+	case f.Flatten:
+		return "flattened object"
+	case f.money != "":
+		return "money"
+	case f.Pluck != "":
+		return "list"
+	case f.IndexBy != "" || f.Pick != "" || f.MapAs == MapInvert:
+		return "object"
+	case f.MapAs == MapEntries || f.MapAs == MapTimeSeries:
+		return "list"
+	case f.As != "":
+		return f.As
+	case f.Date != "":
+		return "string"
+	}
+
+	switch f.Value.(type) {
+	case nil:
+		return "value"
+	case map[string]Schema:
+		return "keyed object"
+	}
+
+	return "nested"
+}
@@ -0,0 +1,148 @@
+package mantau
+
+import (
+	"container/list"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// compiledKey identifies the compiled schema of a struct type, read with a hook and transformed with
+// a schema identified by it's hash, so equal schemas e.g. built for every tenant share the compiled schema
+type compiledKey struct {
+	t      reflect.Type
+	hook   string
+	schema string
+}
+
+// compiledSchema is the schema keys matching every field of a struct type
+type compiledSchema struct {
+	fields [][]string
+}
+
+// compiledEntry is a compiled schema stored in the compiledCache
+type compiledEntry struct {
+	key      compiledKey
+	compiled *compiledSchema
+}
+
+// DefaultCompiledCacheSize is the number of compiled schemas kept by the cache shared with Options.SharedSchemaCache
+const DefaultCompiledCacheSize = 1000
+
+// compiledCache stores the compiled schemas by compiledKey, it's shared by every instance using
+// Options.SharedSchemaCache. The least recently used schemas are removed once it's full
+var compiledCache = &compiledLRU{entries: map[compiledKey]*list.Element{}, order: list.New(), size: DefaultCompiledCacheSize}
+
+// compiledLRU is a bounded cache of compiled schemas
+type compiledLRU struct {
+	mu      sync.Mutex
+	entries map[compiledKey]*list.Element
+
+	// order lists the entries from the least to the most recently used
+	order *list.List
+	size  int
+}
+
+// Load will return the compiled schema stored under the key
+func (c *compiledLRU) Load(key compiledKey) (*compiledSchema, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+
+	if !ok {
+		return nil, false
+	}
+
+	c.order.MoveToBack(element)
+
+	return element.Value.(*compiledEntry).compiled, true
+}
+
+// Store will add the compiled schema under the key, removing the least recently used schema when it's full
+func (c *compiledLRU) Store(key compiledKey, compiled *compiledSchema) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*compiledEntry).compiled = compiled
+		c.order.MoveToBack(element)
+
+		return
+	}
+
+	c.entries[key] = c.order.PushBack(&compiledEntry{key: key, compiled: compiled})
+
+	if c.order.Len() > c.size {
+		oldest := c.order.Front()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*compiledEntry).key)
+	}
+}
+
+// compiled will return the compiled schema of the struct type, or nil when the shared schema cache is disabled.
+// The names are the keys of the struct fields, empty for the fields not transformed
+func (m *mantau) compiled(t reflect.Type, names []string, schema Schema) *compiledSchema {
+	// a naming strategy changes the keys of the fields, it's not part of the cache key
+	if !m.opt.SharedSchemaCache || m.opt.Naming != nil {
+		return nil
+	}
+
+	key := compiledKey{t: t, hook: m.opt.Hook, schema: m.schemaHash(schema)}
+
+	if c, ok := compiledCache.Load(key); ok {
+		return c
+	}
+
+	c := compileSchema(names, schema)
+	compiledCache.Store(key, c)
+
+	return c
+}
+
+// compileSchema will match every field name with the schema keys reading it, in the order of the keys.
+// Gated fields are matched too, the feature flags are checked when the fields are mapped
+func compileSchema(names []string, schema Schema) *compiledSchema {
+	keys := make([]string, 0, len(schema))
+
+	for key := range schema {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	c := &compiledSchema{fields: make([][]string, len(names))}
+
+	for i, name := range names {
+		if name == "" {
+			continue
+		}
+
+		for _, key := range keys {
+			field := schema[key]
+
+			if field.Key == name && field.readsKey() {
+				c.fields[i] = append(c.fields[i], key)
+			}
+		}
+	}
+
+	return c
+}
+
+// mapCompiled works like mapInto but maps the source field with the schema keys of the compiled schema
+func (m *mantau) mapCompiled(result Result, flat *[]flattened, keys []string, tag Field, value, parent interface{}, schema Schema) error {
+	for _, key := range keys {
+		field := schema[key]
+
+		if !m.readsKey(field) {
+			continue
+		}
+
+		if err := m.mapOne(result, flat, key, field.Key, field.withTag(tag), value, parent, schema); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
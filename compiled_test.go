@@ -0,0 +1,95 @@
+package mantau
+
+import (
+	"container/list"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSharedSchemaCache(t *testing.T) {
+	schema := Schema{
+		"username":  Field{Key: "name"},
+		"alias":     Field{Key: "name", As: "string"},
+		"useremail": FlagGate("show_email", Field{Key: "email"}),
+		"address": Field{Key: "user_address", Value: Schema{
+			"code": Field{Key: "postal_code"},
+		}},
+	}
+
+	src := User{Name: "John doe", Email: "john@doe.com", Address: UserAddress{PostalCode: "123"}}
+
+	flags := FlagFunc(func(_ context.Context, flag string) bool {
+		return flag == "show_email"
+	})
+
+	tenant := New().With(WithSharedSchemaCache(true))
+	flagged := New().With(WithSharedSchemaCache(true), WithFlags(flags))
+
+	result, err := tenant.Transform(src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"username": "John doe", "alias": "John doe", "address": Result{"code": "123"}}, result)
+
+	key := compiledKey{t: reflect.TypeOf(src), hook: "json", schema: schema.Hash()}
+	first, ok := compiledCache.Load(key)
+
+	assert.True(t, ok, "Should cache the compiled schema")
+
+	result, err = flagged.Transform(src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"username": "John doe", "alias": "John doe", "useremail": "john@doe.com", "address": Result{"code": "123"}}, result,
+		"The feature flags should be checked by every instance")
+
+	second, _ := compiledCache.Load(key)
+
+	assert.Same(t, first, second, "Should reuse the compiled schema across instances")
+
+	copied := Schema{}
+
+	for k, field := range schema {
+		copied[k] = field
+	}
+
+	_, err = tenant.Transform(src, copied)
+	assert.NoError(t, err, "Should not return any error")
+
+	third, _ := compiledCache.Load(key)
+
+	assert.Same(t, first, third, "Should reuse the compiled schema of an equal schema")
+
+	plain, err := New().Transform(src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+
+	result, err = tenant.Transform(src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, plain, result, "Should transform like an instance without the cache")
+}
+
+func TestCompiledCacheBounded(t *testing.T) {
+	cache := &compiledLRU{entries: map[compiledKey]*list.Element{}, order: list.New(), size: 2}
+	keys := []compiledKey{{hook: "a"}, {hook: "b"}, {hook: "c"}}
+
+	cache.Store(keys[0], &compiledSchema{})
+	cache.Store(keys[1], &compiledSchema{})
+
+	_, ok := cache.Load(keys[0])
+
+	assert.True(t, ok, "Should load the stored schema")
+
+	cache.Store(keys[2], &compiledSchema{})
+
+	_, ok = cache.Load(keys[1])
+
+	assert.False(t, ok, "The least recently used schema should be removed")
+	assert.Equal(t, 2, cache.order.Len(), "The cache should not grow beyond it's size")
+
+	_, ok = cache.Load(keys[0])
+
+	assert.True(t, ok, "A recently used schema should be kept")
+}
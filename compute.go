@@ -0,0 +1,34 @@
+package mantau
+
+// computed will check if the field is computed from the whole source object rather than read from a key
+func (f Field) computed() bool {
+	return f.Compute != nil && f.Key == ""
+}
+
+// computeFields will add the computed fields of the schema to the result. The computed value is emitted
+// like the value of a source key, so the field behavior e.g. As or Mask applies to it
+func (m *mantau) computeFields(result Result, flat *[]flattened, src interface{}, schema Schema) error {
+	for key, field := range schema {
+		if !field.computed() || field.isReserved() || m.gated(field) {
+			continue
+		}
+
+		value, err := field.Compute(src)
+
+		if err != nil {
+			err = m.fieldError(key, err)
+
+			if m.collect(err) {
+				continue
+			}
+
+			return err
+		}
+
+		if err := m.mapOne(result, flat, key, "", field, value, src, schema); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
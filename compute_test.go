@@ -0,0 +1,50 @@
+package mantau
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ComputedUser struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Age       int    `json:"age"`
+}
+
+func TestCompute(t *testing.T) {
+	schema := Schema{
+		"first_name": Field{Key: "first_name"},
+		"full_name": Field{Compute: func(src interface{}) (interface{}, error) {
+			user := src.(ComputedUser)
+
+			return user.FirstName + " " + user.LastName, nil
+		}},
+		"adult": Field{As: "string", Compute: func(src interface{}) (interface{}, error) {
+			return src.(ComputedUser).Age >= 18, nil
+		}},
+	}
+
+	result, err := New().Transform(ComputedUser{FirstName: "John", LastName: "Doe", Age: 30}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"first_name": "John", "full_name": "John Doe", "adult": "true"}, result)
+
+	result, err = New().Transform(map[string]interface{}{"first_name": "John", "": "empty"}, Schema{
+		"initial": Field{Compute: func(src interface{}) (interface{}, error) {
+			return src.(map[string]interface{})["first_name"].(string)[:1], nil
+		}},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"initial": "J"}, result, "Should compute from a map without reading the empty key")
+
+	_, err = New().Transform(ComputedUser{}, Schema{
+		"full_name": Field{Compute: func(src interface{}) (interface{}, error) {
+			return nil, errors.New("Missing name")
+		}},
+	})
+
+	assert.EqualError(t, err, "full_name: Missing name")
+}
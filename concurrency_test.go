@@ -0,0 +1,63 @@
+package mantau
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Run with -race to detect data races between concurrent transformations
+func TestConcurrentTransform(t *testing.T) {
+	m := New()
+
+	schema := Schema{
+		"username": Field{Key: "name"},
+		"address": Field{
+			Key:   "user_address",
+			Value: Schema{"code": Field{Key: "postal_code"}},
+		},
+	}
+
+	user := User{Name: "John doe", Address: UserAddress{PostalCode: "809120"}}
+	want := Result{"username": "John doe", "address": Result{"code": "809120"}}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			result, err := m.Transform(user, schema)
+
+			assert.NoError(t, err, "Should not return any error")
+			assert.Equal(t, want, result, "The result do not match")
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			_, coverage, err := m.TransformVerbose([]User{user, user}, schema)
+
+			require.NoError(t, err, "Should not return any error")
+			assert.NotEmpty(t, coverage.IgnoredKeys, "Coverage should not be shared between calls")
+		}()
+	}
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			// the address of an element of []User is 2 levels deep
+			m.SetOpt(&Options{Hook: "json", MaxDepth: i + 2})
+			m.With(WithMaxDepth(i))
+		}(i)
+	}
+
+	wg.Wait()
+}
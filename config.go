@@ -0,0 +1,150 @@
+package mantau
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// pathEntries will add the entries of the dotted schema keys that are not source keys, e.g. "database.host"
+// is resolved from the "host" key of the nested "database" map or struct
+func (m *mantau) pathEntries(entries []entry, schema Schema) []entry {
+	var index map[string]interface{}
+
+	for _, field := range schema {
+		if !strings.Contains(field.Key, ".") || !m.readsKey(field) {
+			continue
+		}
+
+		if index == nil {
+			index = make(map[string]interface{}, len(entries))
+
+			for _, e := range entries {
+				index[e.key] = e.value
+			}
+		}
+
+		if _, ok := index[field.Key]; ok {
+			continue
+		}
+
+		segments := strings.Split(field.Key, ".")
+		value, ok := index[segments[0]]
+
+		for _, segment := range segments[1:] {
+			// A nil object in the middle of the path makes the value nil, see Options.NilNested
+			if !ok || isNilValue(value) {
+				value = nil
+				break
+			}
+
+			value, ok = m.index(value, segment)
+		}
+
+		if ok {
+			entries = append(entries, entry{key: field.Key, value: value})
+			index[field.Key] = value
+		}
+	}
+
+	return entries
+}
+
+// index will return the value of a map key or a struct field under the given key
+func (m *mantau) index(src interface{}, key string) (interface{}, bool) {
+	if isNilValue(src) {
+		return nil, false
+	}
+
+	value := m.getValue(src)
+
+	switch value.Kind() {
+	case reflect.Map:
+		for _, k := range value.MapKeys() {
+			if formatMapKey(k, m.opt.TimeKeyLayout) == key {
+				return value.MapIndex(k).Interface(), true
+			}
+		}
+	case reflect.Struct:
+		t := value.Type()
+
+		for i := 0; i < value.NumField(); i++ {
+			if t.Field(i).PkgPath != "" {
+				continue
+			}
+
+			tag, err := m.structFieldName(t, i)
+
+			if err != nil {
+				continue
+			}
+
+			if name, _ := parseTag(tag); name == key {
+				return value.Field(i).Interface(), true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// TransformConfig works like TransformAny but for configuration trees loaded by libraries like viper or koanf.
+// Maps with interface{} keys are converted into map[string]interface{} and the wildcard field passes
+// nested objects through as they are, so a config dump only needs to declare the keys it changes
+func (m *mantau) TransformConfig(tree interface{}, schema Schema) (interface{}, error) {
+	return m.With(WithMismatch(MismatchRaw)).TransformAny(DumpConfig(tree), schema)
+}
+
+// DumpConfig will copy a configuration tree, converting maps with interface{} keys into map[string]interface{}
+// so it can be encoded as JSON. The values of keys matching one of the secret patterns, at any depth,
+// are replaced by Redacted
+func DumpConfig(tree interface{}, secrets ...*regexp.Regexp) interface{} {
+	if tree == nil {
+		return nil
+	}
+
+	value := reflect.ValueOf(tree)
+
+	switch value.Kind() {
+	case reflect.Map:
+		result := make(map[string]interface{}, value.Len())
+
+		for _, k := range value.MapKeys() {
+			key := formatMapKey(k, "")
+
+			if isSecret(key, secrets) {
+				result[key] = Redacted
+				continue
+			}
+
+			result[key] = DumpConfig(value.MapIndex(k).Interface(), secrets...)
+		}
+
+		return result
+	case reflect.Slice, reflect.Array:
+		if _, ok := tree.([]byte); ok {
+			return tree
+		}
+
+		result := make([]interface{}, value.Len())
+
+		for i := range result {
+			result[i] = DumpConfig(value.Index(i).Interface(), secrets...)
+		}
+
+		return result
+	}
+
+	return tree
+}
+
+// isSecret will check if the key matches one of the secret patterns
+func isSecret(key string, secrets []*regexp.Regexp) bool {
+	for _, secret := range secrets {
+		if secret.MatchString(key) {
+			return true
+		}
+	}
+
+	return false
+}
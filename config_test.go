@@ -0,0 +1,101 @@
+package mantau
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func configTree() map[string]interface{} {
+	return map[string]interface{}{
+		"name": "api",
+		"database": map[interface{}]interface{}{
+			"host":     "localhost",
+			"port":     5432,
+			"password": "secret",
+		},
+		"replicas": []interface{}{
+			map[interface{}]interface{}{"host": "replica-1", "api_key": "key"},
+		},
+	}
+}
+
+func TestDumpConfig(t *testing.T) {
+	dump := DumpConfig(configTree(), regexp.MustCompile(`(?i)password|key`))
+
+	assert.Equal(t, map[string]interface{}{
+		"name": "api",
+		"database": map[string]interface{}{
+			"host":     "localhost",
+			"port":     5432,
+			"password": Redacted,
+		},
+		"replicas": []interface{}{
+			map[string]interface{}{"host": "replica-1", "api_key": Redacted},
+		},
+	}, dump, "The dump do not match")
+}
+
+func TestTransformConfig(t *testing.T) {
+	result, err := New().TransformConfig(configTree(), Schema{
+		"db_host":  Field{Key: "database.host"},
+		"db_port":  Field{Key: "database.port", As: "string"},
+		"missing":  Field{Key: "database.user.name"},
+		"*":        Field{},
+		"database": Drop(),
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"db_host": "localhost",
+		"db_port": "5432",
+		"name":    "api",
+		"replicas": []interface{}{
+			Result{"host": "replica-1", "api_key": "key"},
+		},
+	}, result, "The result do not match")
+}
+
+func TestDottedStructKeys(t *testing.T) {
+	src := User{
+		Name:    "John doe",
+		Address: UserAddress{PostalCode: "123", Address: "Main street"},
+		Permissions: []Permission{
+			{PermissionName: "read", PermissionCode: 1},
+		},
+	}
+
+	result, err := New().Transform(src, Schema{
+		"name":        Field{Key: "name"},
+		"postal_code": Field{Key: "user_address.postal_code", Required: true},
+		"street":      Field{Key: "user_address.address", As: "string"},
+		"missing":     Field{Key: "user_address.missing"},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John doe", "postal_code": "123", "street": "Main street"}, result, "The result do not match")
+
+	type Order struct {
+		ID       int                    `json:"id"`
+		Customer *User                  `json:"customer"`
+		Meta     map[string]interface{} `json:"meta"`
+	}
+
+	schema := Schema{
+		"customer_name": Field{Key: "customer.name"},
+		"channel":       Field{Key: "meta.source.channel"},
+	}
+
+	result, err = New().Transform(Order{ID: 1, Customer: &src, Meta: map[string]interface{}{
+		"source": map[string]interface{}{"channel": "web"},
+	}}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"customer_name": "John doe", "channel": "web"}, result, "Should read through pointers and maps")
+
+	result, err = New().With(WithNilNested(NilNull)).Transform(Order{ID: 2}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"customer_name": null, "channel": null}, result, "A nil object in the path should make the value nil")
+}
@@ -0,0 +1,77 @@
+package mantau
+
+import "context"
+
+// Const will create a field emitting the given value, regardless of the source value
+func Const(value interface{}) Field {
+	return Field{inject: func(context.Context) (interface{}, bool) {
+		return value, true
+	}}
+}
+
+// FromContext will create a field emitting the value stored under the given key of the context
+// passed to TransformCtx. The key is omitted when the context doesn't have the value
+func FromContext(key interface{}) Field {
+	return Field{inject: func(ctx context.Context) (interface{}, bool) {
+		value := ctx.Value(key)
+
+		return value, value != nil
+	}}
+}
+
+// TransformCtx works like Transform but resolves the FromContext fields from the given context
+func (m *mantau) TransformCtx(ctx context.Context, src interface{}, schema Schema) (interface{}, error) {
+	c := m.withState()
+	c.state.ctx = ctx
+
+	return c.run(src, schema)
+}
+
+// context will return the context of the transformation
+func (m *mantau) context() context.Context {
+	if m.state == nil || m.state.ctx == nil {
+		return context.Background()
+	}
+
+	return m.state.ctx
+}
+
+// injectFields will add the constant and context values of the schema to the result,
+// a key already in the result follows Options.Collisions
+func (m *mantau) injectFields(result Result, schema Schema) error {
+	for key, field := range schema {
+		if field.inject == nil || m.gated(field) {
+			continue
+		}
+
+		value, ok := field.inject(m.context())
+
+		if !ok {
+			continue
+		}
+
+		if ok, err := m.checkCollision(result, key, key, ""); !ok {
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		value, err := m.scanSecrets(value)
+
+		if err != nil {
+			err = m.fieldError(key, err)
+
+			if m.collect(err) {
+				continue
+			}
+
+			return err
+		}
+
+		result[key] = value
+	}
+
+	return nil
+}
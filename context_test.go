@@ -0,0 +1,70 @@
+package mantau
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type contextKey string
+
+func TestConstAndFromContext(t *testing.T) {
+	schema := Schema{
+		"username":   Field{Key: "name"},
+		"version":    Const("v1"),
+		"request_id": FromContext(contextKey("request_id")),
+		"permissions": Field{
+			Key: "permissions",
+			Value: Schema{
+				"code": Field{Key: "permission_code"},
+				"type": Const("permission"),
+			},
+		},
+	}
+
+	src := User{
+		Name:        "John doe",
+		Permissions: []Permission{{"Admin", 0}},
+	}
+
+	ctx := context.WithValue(context.Background(), contextKey("request_id"), "abc-123")
+	result, err := New().TransformCtx(ctx, src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"username":   "John doe",
+		"version":    "v1",
+		"request_id": "abc-123",
+		"permissions": []Result{
+			{"code": 0, "type": "permission"},
+		},
+	}, result, "The result do not match")
+
+	result, err = New().Transform(src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.NotContains(t, result, "request_id", "Missing context values should be omitted")
+	assert.Equal(t, "v1", result.(Result)["version"], "Constants should be emitted without a context")
+}
+
+func TestInjectCollision(t *testing.T) {
+	schema := Schema{"request_id": FromContext(contextKey("request_id"))}.Wildcard()
+	src := map[string]interface{}{"request_id": "from-source", "name": "John doe"}
+	ctx := context.WithValue(context.Background(), contextKey("request_id"), "from-context")
+
+	_, err := New().TransformCtx(ctx, src, schema)
+
+	assert.True(t, errors.Is(err, ErrDuplicateKey), "An injected key already in the result should return an error")
+
+	result, err := New().With(WithCollisions(CollisionKeep)).TransformCtx(ctx, src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"request_id": "from-source", "name": "John doe"}, result, "The source value should be kept")
+
+	result, err = New().With(WithCollisions(CollisionOverride)).TransformCtx(ctx, src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"request_id": "from-context", "name": "John doe"}, result, "The injected value should override")
+}
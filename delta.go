@@ -0,0 +1,77 @@
+package mantau
+
+import "errors"
+
+// TransformDelta will transform two versions of the source with the schema and return the keys whose
+// transformed values changed, e.g. to publish a PATCH-style change event. Nested objects only contain
+// their changed keys, collections are emitted as a whole and a removed key is emitted as nil.
+// A nil old source is an empty object, so the delta of a created object is the whole object
+func (m *mantau) TransformDelta(oldSrc, newSrc interface{}, schema Schema) (Result, error) {
+	before, err := m.transformObject(oldSrc, schema)
+
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := m.transformObject(newSrc, schema)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return delta(before, after), nil
+}
+
+// transformObject will transform the source into an object, a nil source is an empty object
+func (m *mantau) transformObject(src interface{}, schema Schema) (Result, error) {
+	result, err := m.Transform(src, schema)
+
+	if err != nil || result == nil {
+		return Result{}, err
+	}
+
+	r, ok := result.(Result)
+
+	if !ok {
+		return nil, errors.New("Only objects can be compared or merged")
+	}
+
+	return r, nil
+}
+
+// delta will return the keys of after with a different value in before, and the removed keys as nil
+func delta(before, after Result) Result {
+	changes := Result{}
+
+	for key, value := range after {
+		old, ok := before[key]
+
+		if !ok {
+			changes[key] = value
+			continue
+		}
+
+		oldObject, oldIsObject := old.(Result)
+		newObject, newIsObject := value.(Result)
+
+		if oldIsObject && newIsObject && oldObject != nil && newObject != nil {
+			if nested := delta(oldObject, newObject); len(nested) > 0 {
+				changes[key] = nested
+			}
+
+			continue
+		}
+
+		if !equalValues(old, value) {
+			changes[key] = value
+		}
+	}
+
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			changes[key] = nil
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,58 @@
+package mantau
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformDelta(t *testing.T) {
+	schema := Schema{
+		"name":  Field{Key: "name"},
+		"email": Field{Key: "email", OmitZero: true},
+		"tags":  Field{Key: "tags"},
+		"address": Field{Key: "address", Value: Schema{
+			"city": Field{Key: "city"},
+			"code": Field{Key: "code"},
+		}},
+	}
+
+	before := map[string]interface{}{
+		"name":    "John",
+		"email":   "john@example.com",
+		"tags":    []string{"a", "b"},
+		"address": map[string]interface{}{"city": "Paris", "code": 75001},
+	}
+
+	after := map[string]interface{}{
+		"name":    "John",
+		"email":   "",
+		"tags":    []string{"a", "c"},
+		"address": map[string]interface{}{"city": "Paris", "code": 75002.0},
+	}
+
+	result, err := New().TransformDelta(before, after, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"email":   nil,
+		"tags":    []string{"a", "c"},
+		"address": Result{"code": 75002.0},
+	}, result)
+
+	result, err = New().TransformDelta(after, after, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{}, result, "Should be empty when nothing changed")
+}
+
+func TestTransformDeltaCreated(t *testing.T) {
+	result, err := New().TransformDelta(nil, map[string]interface{}{"name": "John"}, Schema{"name": Field{Key: "name"}})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John"}, result)
+
+	_, err = New().TransformDelta([]int{1}, []int{2}, Schema{})
+
+	assert.Error(t, err, "Should not diff collections")
+}
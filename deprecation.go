@@ -0,0 +1,54 @@
+package mantau
+
+import "sort"
+
+// Deprecation describes a deprecated field emitted by a transformation
+type Deprecation struct {
+	// Path is the dotted output path of the field, without collection indexes e.g. "address.code"
+	Path string `json:"path"`
+
+	// Message is the deprecation message of the field
+	Message string `json:"message"`
+}
+
+// TransformDeprecations works like Transform but also returns the deprecated fields emitted by the transformation,
+// ordered by their path. They can be added to the response e.g. as meta.deprecations or with SetDeprecationHeaders
+func (m *mantau) TransformDeprecations(src interface{}, schema Schema) (interface{}, []Deprecation, error) {
+	c := m.withState()
+	c.state.deprecations = map[string]string{}
+
+	result, err := c.run(src, schema)
+
+	if err != nil {
+		return result, nil, err
+	}
+
+	deprecations := make([]Deprecation, 0, len(c.state.deprecations))
+
+	for path, message := range c.state.deprecations {
+		deprecations = append(deprecations, Deprecation{Path: path, Message: message})
+	}
+
+	sort.Slice(deprecations, func(i, j int) bool {
+		return pathLess(deprecations[i].Path, deprecations[j].Path)
+	})
+
+	return result, deprecations, nil
+}
+
+// deprecated will report an emitted deprecated field
+func (m *mantau) deprecated(key string, field Field) {
+	if field.Deprecated == "" || m.state == nil {
+		return
+	}
+
+	path := stripIndexes(joinPath(m.state.path(), key))
+
+	if m.state.deprecations != nil {
+		m.state.deprecations[path] = field.Deprecated
+	}
+
+	if m.opt.OnDeprecated != nil {
+		m.opt.OnDeprecated(m.context(), Deprecation{Path: path, Message: field.Deprecated})
+	}
+}
@@ -0,0 +1,49 @@
+package mantau
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecations(t *testing.T) {
+	src := map[string]interface{}{
+		"name":     "John",
+		"username": "john",
+		"address":  []map[string]interface{}{{"zip": "123"}, {"zip": "456"}},
+	}
+
+	schema := Schema{
+		"name":     Field{Key: "name"},
+		"username": Field{Key: "username", Deprecated: "use name"},
+		"address": Field{Key: "address", Value: Schema{
+			"zip": Field{Key: "zip", Deprecated: "use postal_code"},
+		}},
+		"nickname": Field{Key: "nickname", Deprecated: "removed"},
+	}
+
+	result, deprecations, err := New().TransformDeprecations(src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, "john", result.(Result)["username"])
+	assert.Equal(t, []Deprecation{
+		{Path: "address.zip", Message: "use postal_code"},
+		{Path: "username", Message: "use name"},
+	}, deprecations, "Only the emitted deprecated fields should be reported")
+}
+
+func TestOnDeprecated(t *testing.T) {
+	reported := []Deprecation{}
+
+	m := New().With(WithOnDeprecated(func(ctx context.Context, d Deprecation) {
+		reported = append(reported, d)
+	}))
+
+	_, err := m.Transform(map[string]interface{}{"username": "john"}, Schema{
+		"username": Field{Key: "username", Deprecated: "use name"},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, []Deprecation{{Path: "username", Message: "use name"}}, reported)
+}
@@ -0,0 +1,23 @@
+package mantau
+
+import (
+	"go/build"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoreImports(t *testing.T) {
+	ctx := build.Default
+	ctx.BuildTags = []string{"mantau_core"}
+
+	pkg, err := ctx.ImportDir(".", 0)
+
+	assert.NoError(t, err, "Should not return any error")
+
+	for _, path := range pkg.Imports {
+		assert.False(t, strings.Contains(strings.Split(path, "/")[0], "."), "The core should only import the standard library, got %s", path)
+		assert.NotContains(t, []string{"net/http", "go/format"}, path, "The core should not import %s", path)
+	}
+}
@@ -0,0 +1,90 @@
+package mantau
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"sync"
+)
+
+// Encoder writes a transformed result in a wire format, e.g. JSON, msgpack or CBOR.
+// New formats are added by implementing it, see NewEncoder and RegisterEncoder
+type Encoder interface {
+	// ContentType is the media type of the format e.g. "application/json"
+	ContentType() string
+
+	// Encode will write the value to the writer
+	Encode(w io.Writer, v interface{}) error
+}
+
+// encoderFunc adapts an encoding function to the Encoder interface
+type encoderFunc struct {
+	contentType string
+	encode      func(w io.Writer, v interface{}) error
+}
+
+func (e encoderFunc) ContentType() string {
+	return e.contentType
+}
+
+func (e encoderFunc) Encode(w io.Writer, v interface{}) error {
+	return e.encode(w, v)
+}
+
+// NewEncoder will create an encoder from a content type and an encoding function, so a format library
+// can be plugged in without a new type e.g. NewEncoder("application/msgpack", msgpackEncode)
+func NewEncoder(contentType string, encode func(w io.Writer, v interface{}) error) Encoder {
+	return encoderFunc{contentType: contentType, encode: encode}
+}
+
+// JSONEncoder encodes results with encoding/json, it's registered by default
+var JSONEncoder = NewEncoder("application/json", func(w io.Writer, v interface{}) error {
+	return json.NewEncoder(w).Encode(v)
+})
+
+var (
+	encodersMu sync.RWMutex
+	encoders   = map[string]Encoder{}
+)
+
+func init() {
+	RegisterEncoder(JSONEncoder)
+}
+
+// RegisterEncoder will register the encoder under it's content type, replacing the previous encoder, if any
+func RegisterEncoder(e Encoder) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+
+	encoders[mediaType(e.ContentType())] = e
+}
+
+// EncoderFor will find the registered encoder of a content type, parameters like "charset=utf-8" are ignored
+func EncoderFor(contentType string) (Encoder, bool) {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+
+	e, ok := encoders[mediaType(contentType)]
+
+	return e, ok
+}
+
+// mediaType will strip the parameters of a content type
+func mediaType(contentType string) string {
+	if t, _, err := mime.ParseMediaType(contentType); err == nil {
+		return t
+	}
+
+	return contentType
+}
+
+// TransformTo will transform the source with the schema and write the result with the encoder
+func (m *mantau) TransformTo(w io.Writer, enc Encoder, src interface{}, schema Schema) error {
+	result, err := m.Transform(src, schema)
+
+	if err != nil {
+		return err
+	}
+
+	return enc.Encode(w, result)
+}
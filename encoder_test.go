@@ -0,0 +1,50 @@
+package mantau
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformTo(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	err := New().TransformTo(buf, JSONEncoder, map[string]interface{}{"name": "John"}, Schema{
+		"name": Field{Key: "name"},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, "{\"name\":\"John\"}\n", buf.String())
+}
+
+func TestRegisterEncoder(t *testing.T) {
+	text := NewEncoder("text/plain", func(w io.Writer, v interface{}) error {
+		_, err := fmt.Fprint(w, v.(Result)["name"])
+		return err
+	})
+
+	RegisterEncoder(text)
+
+	enc, ok := EncoderFor("text/plain; charset=utf-8")
+
+	assert.True(t, ok, "Should find the registered encoder")
+	assert.Equal(t, "text/plain", enc.ContentType())
+
+	json, ok := EncoderFor("application/json")
+
+	assert.True(t, ok, "JSON should be registered by default")
+	assert.Equal(t, "application/json", json.ContentType())
+
+	_, ok = EncoderFor("application/cbor")
+
+	assert.False(t, ok, "Should not find an unregistered encoder")
+
+	buf := &bytes.Buffer{}
+	err := New().TransformTo(buf, enc, map[string]interface{}{"name": "John"}, Schema{"name": Field{Key: "name"}})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, "John", buf.String())
+}
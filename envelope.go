@@ -0,0 +1,144 @@
+package mantau
+
+import (
+	"errors"
+	"log"
+)
+
+const (
+	// GenericErrorMessage is the message of an error body when the error doesn't implement PublicError
+	GenericErrorMessage = "Something went wrong"
+
+	// GenericFieldMessage is the message of a field of an error body when it's error doesn't implement PublicError
+	GenericFieldMessage = "Invalid value"
+)
+
+// EnvelopeOptions are the pagination parameters of a list response
+type EnvelopeOptions struct {
+	// Total is the number of items of every page
+	Total int
+
+	// Page is the current page, starting from 1
+	Page int
+
+	// PerPage is the maximum number of items of a page
+	PerPage int
+}
+
+// Envelope will transform a collection with the data schema and wrap it into a standard list response,
+// {"data": [...], "meta": {"total": ..., "page": ..., "per_page": ..., "total_pages": ...}}
+func (m *mantau) Envelope(src interface{}, dataSchema Schema, meta EnvelopeOptions) (Result, error) {
+	data, err := m.Transform(src, dataSchema)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if data == nil {
+		data = []Result{}
+	}
+
+	totalPages := 0
+
+	if meta.PerPage > 0 {
+		totalPages = (meta.Total + meta.PerPage - 1) / meta.PerPage
+	}
+
+	return Result{
+		"data": data,
+		"meta": Result{
+			"total":       meta.Total,
+			"page":        meta.Page,
+			"per_page":    meta.PerPage,
+			"total_pages": totalPages,
+		},
+	}, nil
+}
+
+// ErrorCoder is implemented by errors with a machine readable code, it's emitted as the "code" of the error body
+type ErrorCoder interface {
+	Code() string
+}
+
+// PublicError is implemented by errors whose message can be shown to clients, it's emitted as the "message"
+// of the error body. The messages of other errors may leak internals, they are replaced by a generic message
+type PublicError interface {
+	PublicMessage() string
+}
+
+// Respond will shape a success or an error body under the same envelope, so a handler has a single exit path.
+// When err is nil the source is transformed with the schema into {"ok": true, "data": ...}, otherwise
+// or when the transformation fails the error is emitted as {"ok": false, "error": {"message": ...}}.
+// The error body has the "code" of an ErrorCoder and the "fields" errors of a *FieldError or a *MultiError,
+// a field error of a *CoercionError also has the "type" of the value and the type it's coerced "as".
+// The messages are generic unless the errors implement PublicError, the detailed error is passed to
+// Options.OnError or written to the standard logger when it's not set
+func (m *mantau) Respond(src interface{}, schema Schema, err error) Result {
+	if err == nil {
+		var data interface{}
+
+		if data, err = m.Transform(src, schema); err == nil {
+			return Result{"ok": true, "data": data}
+		}
+	}
+
+	if m.opt.OnError != nil {
+		m.opt.OnError(m.context(), err)
+	} else {
+		log.Printf("mantau: %v", err)
+	}
+
+	return Result{"ok": false, "error": errorBody(err)}
+}
+
+// publicMessage will return the message of a PublicError, or the generic message
+func publicMessage(err error, generic string) string {
+	var public PublicError
+
+	if errors.As(err, &public) {
+		return public.PublicMessage()
+	}
+
+	return generic
+}
+
+// errorBody will describe an error with it's public message, code and field errors
+func errorBody(err error) Result {
+	body := Result{"message": publicMessage(err, GenericErrorMessage)}
+
+	var coder ErrorCoder
+
+	if errors.As(err, &coder) {
+		body["code"] = coder.Code()
+	}
+
+	var fieldErrs []*FieldError
+	var multi *MultiError
+	var fieldErr *FieldError
+
+	switch {
+	case errors.As(err, &multi):
+		fieldErrs = multi.Errors
+	case errors.As(err, &fieldErr):
+		fieldErrs = []*FieldError{fieldErr}
+	}
+
+	if len(fieldErrs) > 0 {
+		fields := make([]Result, len(fieldErrs))
+
+		for i, e := range fieldErrs {
+			fields[i] = Result{"path": e.Path, "message": publicMessage(e.Err, GenericFieldMessage)}
+
+			var coercionErr *CoercionError
+
+			if errors.As(e.Err, &coercionErr) {
+				fields[i]["type"] = coercionErr.Type
+				fields[i]["as"] = coercionErr.As
+			}
+		}
+
+		body["fields"] = fields
+	}
+
+	return body
+}
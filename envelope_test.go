@@ -0,0 +1,77 @@
+package mantau
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvelope(t *testing.T) {
+	schema := Schema{"name": Field{Key: "permission_name"}}
+
+	result, err := New().Envelope([]Permission{{"Admin", 1}, {"Customer", 2}}, schema, EnvelopeOptions{
+		Total:   21,
+		Page:    2,
+		PerPage: 10,
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"data": []Result{{"name": "Admin"}, {"name": "Customer"}},
+		"meta": Result{"total": 21, "page": 2, "per_page": 10, "total_pages": 3},
+	}, result, "The result do not match")
+
+	result, err = New().Envelope(nil, schema, EnvelopeOptions{Page: 1})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"data": []Result{},
+		"meta": Result{"total": 0, "page": 1, "per_page": 0, "total_pages": 0},
+	}, result, "An empty page should have an empty data")
+}
+
+type codedError struct{}
+
+func (codedError) Error() string { return "Not found" }
+func (codedError) Code() string  { return "not_found" }
+
+type publicError struct{}
+
+func (publicError) Error() string         { return "User 7 not found in users_v2" }
+func (publicError) PublicMessage() string { return "User not found" }
+
+func TestRespond(t *testing.T) {
+	logged := []error{}
+	m := New().With(WithOnError(func(ctx context.Context, err error) {
+		logged = append(logged, err)
+	}))
+	schema := Schema{"name": Field{Key: "name"}}
+
+	assert.Equal(t, Result{
+		"ok":   true,
+		"data": Result{"name": "John"},
+	}, m.Respond(map[string]interface{}{"name": "John"}, schema, nil), "The success body do not match")
+
+	assert.Equal(t, Result{
+		"ok":    false,
+		"error": Result{"message": GenericErrorMessage, "code": "not_found"},
+	}, m.Respond(nil, schema, fmt.Errorf("Cannot find the user: %w", codedError{})), "The error body do not match")
+
+	assert.Equal(t, Result{
+		"ok":    false,
+		"error": Result{"message": "User not found"},
+	}, m.Respond(nil, schema, publicError{}), "The public message should be emitted")
+
+	body := m.Respond(map[string]interface{}{"address": "Main street"}, Schema{
+		"address": Field{Key: "address", Value: Schema{"city": Field{Key: "city"}}},
+	}, nil)
+
+	assert.Equal(t, false, body["ok"])
+	assert.Equal(t, "address", body["error"].(Result)["fields"].([]Result)[0]["path"], "The transformation error should have it's fields")
+	assert.Equal(t, GenericFieldMessage, body["error"].(Result)["fields"].([]Result)[0]["message"], "The field message should be generic")
+
+	assert.Len(t, logged, 3, "The detailed errors should be passed to OnError")
+	assert.EqualError(t, logged[0], "Cannot find the user: Not found")
+}
@@ -0,0 +1,249 @@
+package mantau
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FieldError describes an error of a single field
+type FieldError struct {
+	// Path is the dotted output path of the field e.g. "address.code"
+	Path string
+
+	// Err is the cause of the error
+	Err error
+}
+
+// Error will describe the error prefixed by the field path
+func (e *FieldError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap will return the cause of the error
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// CoercionError is returned when the value of a field cannot be coerced with Field.As,
+// it's wrapped into a *FieldError with the path of the field
+type CoercionError struct {
+	// Type is the Go type of the value e.g. "string"
+	Type string
+
+	// As is the requested type e.g. "int"
+	As string
+
+	// Value is the value that cannot be coerced, it's nil when the value is redacted
+	Value interface{}
+
+	// Redacted reports that the value is sensitive and left out of the message
+	Redacted bool
+
+	// Err is the cause of the error, if any e.g. a strconv.NumError
+	Err error
+}
+
+// Error will describe the value, it's type and the requested type e.g. `Cannot coerce string "many" into int`
+func (e *CoercionError) Error() string {
+	if e.Redacted {
+		return fmt.Sprintf("Cannot coerce %s %s into %s", e.Type, Redacted, e.As)
+	}
+
+	return fmt.Sprintf("Cannot coerce %s %q into %s", e.Type, fmt.Sprint(e.Value), e.As)
+}
+
+// Unwrap will return the cause of the error
+func (e *CoercionError) Unwrap() error {
+	return e.Err
+}
+
+// Is will report if the target is ErrCoercion
+func (e *CoercionError) Is(target error) bool {
+	return target == ErrCoercion
+}
+
+// RequiredFieldError is returned when the source value of a Field.Required field is missing or nil,
+// it's wrapped into a *FieldError with the path of the field
+type RequiredFieldError struct {
+	// Key is the schema key of the field
+	Key string
+
+	// SourcePath is the dotted path of the missing source value e.g. "user.email"
+	SourcePath string
+}
+
+// Error will describe the missing field
+func (e *RequiredFieldError) Error() string {
+	return fmt.Sprintf("Required field %q is missing from %q", e.Key, e.SourcePath)
+}
+
+// Is will report if the target is ErrRequiredField
+func (e *RequiredFieldError) Is(target error) bool {
+	return target == ErrRequiredField
+}
+
+// DuplicateKeyError is returned when a field produces an output key that is already in the result,
+// it's wrapped into a *FieldError with the path of the field
+type DuplicateKeyError struct {
+	// Key is the duplicated output key
+	Key string
+
+	// Source is the source key of the field producing the key again, empty for computed fields
+	Source string
+}
+
+// Error will describe the duplicated key
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("Output key %q produced from %q is already in the result", e.Key, e.Source)
+}
+
+// Is will report if the target is ErrDuplicateKey
+func (e *DuplicateKeyError) Is(target error) bool {
+	return target == ErrDuplicateKey
+}
+
+// redactCoercion will leave the value of a classified or masked field out of a *CoercionError
+func redactCoercion(err error, field Field) error {
+	var coercionErr *CoercionError
+
+	if !errors.As(err, &coercionErr) {
+		return err
+	}
+
+	if field.Mask != "" || (field.Classification != "" && field.Classification != ClassPublic) {
+		coercionErr.Value = nil
+		coercionErr.Redacted = true
+	}
+
+	return err
+}
+
+// MultiError is returned with Options.CollectErrors, it contains every field error
+// ordered by their path so the message is the same across runs
+type MultiError struct {
+	Errors []*FieldError
+
+	// Aborted reports that the transformation stopped after more than Options.MaxErrors errors
+	Aborted bool
+}
+
+// Error will describe every field error on it's own line like errors.Join
+func (e *MultiError) Error() string {
+	lines := make([]string, len(e.Errors))
+
+	for i, err := range e.Errors {
+		lines[i] = err.Error()
+	}
+
+	if e.Aborted {
+		lines = append(lines, ErrTooManyErrors.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap will return the field errors so errors.Is and errors.As can inspect every one of them
+func (e *MultiError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+
+	return errs
+}
+
+// Is will report if any field error matches the target, for Go versions
+// where errors.Is doesn't unwrap multiple errors
+func (e *MultiError) Is(target error) bool {
+	if e.Aborted && target == ErrTooManyErrors {
+		return true
+	}
+
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fieldError will wrap the error of the field under the given key with it's path.
+// Errors of nested fields are already wrapped and returned as they are
+func (m *mantau) fieldError(key string, err error) error {
+	var fieldErr *FieldError
+
+	if errors.As(err, &fieldErr) {
+		return err
+	}
+
+	path := key
+
+	if m.state != nil {
+		path = joinPath(m.state.path(), key)
+	}
+
+	return &FieldError{Path: path, Err: err}
+}
+
+// collect will record a field error when errors are collected.
+// It returns false when the error should stop the transformation, including when there are more than Options.MaxErrors errors
+func (m *mantau) collect(err error) bool {
+	if m.state == nil || !m.opt.CollectErrors || errors.Is(err, ErrResultTooLarge) {
+		return false
+	}
+
+	var fieldErr *FieldError
+
+	if !errors.As(err, &fieldErr) {
+		return false
+	}
+
+	m.state.errors = append(m.state.errors, fieldErr)
+
+	return m.opt.MaxErrors <= 0 || len(m.state.errors) <= m.opt.MaxErrors
+}
+
+// run will transform the source with the state of the instance
+func (m *mantau) run(src interface{}, schema Schema) (interface{}, error) {
+	if !m.opt.VerifySource {
+		return m.finish(m.serialize(src, schema))
+	}
+
+	before := sourceHash(src)
+	result, err := m.finish(m.serialize(src, schema))
+
+	if err == nil && sourceHash(src) != before {
+		return nil, ErrSourceMutated
+	}
+
+	return result, err
+}
+
+// finish will return the collected errors of the transformation, if any, as a *MultiError ordered by path
+func (m *mantau) finish(result interface{}, err error) (interface{}, error) {
+	aborted := err != nil && m.opt.MaxErrors > 0 && len(m.state.errors) > m.opt.MaxErrors
+
+	if (err != nil && !aborted) || len(m.state.errors) == 0 {
+		return result, err
+	}
+
+	errs := m.state.errors
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Path != errs[j].Path {
+			return pathLess(errs[i].Path, errs[j].Path)
+		}
+
+		return errs[i].Err.Error() < errs[j].Err.Error()
+	})
+
+	if aborted {
+		return nil, &MultiError{Errors: errs, Aborted: true}
+	}
+
+	return result, &MultiError{Errors: errs}
+}
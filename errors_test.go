@@ -0,0 +1,171 @@
+package mantau
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectErrors(t *testing.T) {
+	data := map[string]interface{}{
+		"name":  "Apple",
+		"qty":   "many",
+		"price": "cheap",
+		"store": map[string]interface{}{"code": "abc", "name": "Store"},
+		"tags":  "fruit",
+	}
+
+	schema := Schema{
+		"name":  Field{Key: "name"},
+		"qty":   Field{Key: "qty", As: "int"},
+		"price": Field{Key: "price", As: "float"},
+		"store": Field{
+			Key: "store",
+			Value: Schema{
+				"code": Field{Key: "code", As: "int"},
+				"name": Field{Key: "name"},
+			},
+		},
+		"tags": Field{Key: "tags", Value: Schema{}},
+	}
+
+	_, err := New().Transform(data, schema)
+
+	var fieldErr *FieldError
+
+	assert.True(t, errors.As(err, &fieldErr), "Field errors should be wrapped with their path")
+
+	m := New().With(WithCollectErrors(true))
+
+	for i := 0; i < 10; i++ {
+		result, err := m.Transform(data, schema)
+
+		var multi *MultiError
+
+		assert.True(t, errors.As(err, &multi), "Should return a multi error")
+		assert.Equal(t, Result{"name": "Apple", "store": Result{"name": "Store"}}, result, "Partial result should be returned")
+		assert.Equal(t, []string{"price", "qty", "store.code", "tags"}, []string{
+			multi.Errors[0].Path, multi.Errors[1].Path, multi.Errors[2].Path, multi.Errors[3].Path,
+		}, "Errors should be ordered by their path")
+		assert.Len(t, multi.Unwrap(), 4, "Every error should be unwrapped")
+		assert.True(t, errors.Is(err, ErrSchemaMismatch), "Multi error should match any of it's errors")
+		assert.Equal(t, multi.Errors[0].Error()+"\n"+multi.Errors[1].Error()+"\n"+multi.Errors[2].Error()+"\n"+multi.Errors[3].Error(), err.Error())
+	}
+
+	items := make([]interface{}, 11)
+
+	for i := range items {
+		items[i] = map[string]interface{}{"qty": "many"}
+	}
+
+	_, err = m.Transform(map[string]interface{}{"items": items}, Schema{
+		"items": Field{Key: "items", Value: Schema{"qty": Field{Key: "qty", As: "int"}}},
+	})
+
+	var multi *MultiError
+
+	assert.True(t, errors.As(err, &multi), "Should return a multi error")
+	assert.Equal(t, "items[2].qty", multi.Errors[2].Path, "The indexes should be ordered as numbers")
+	assert.Equal(t, "items[10].qty", multi.Errors[10].Path, "The indexes should be ordered as numbers")
+}
+
+func TestMaxErrors(t *testing.T) {
+	data := map[string]interface{}{"a": "x", "b": "x", "c": "x", "d": "x"}
+	schema := Schema{
+		"a": Field{Key: "a", As: "int"},
+		"b": Field{Key: "b", As: "int"},
+		"c": Field{Key: "c", As: "int"},
+		"d": Field{Key: "d", As: "int"},
+	}
+
+	result, err := New().With(WithCollectErrors(true), WithMaxErrors(2)).Transform(data, schema)
+
+	var multi *MultiError
+
+	assert.Nil(t, result, "An aborted transformation should not return a result")
+	assert.True(t, errors.As(err, &multi), "Should return a multi error")
+	assert.True(t, multi.Aborted, "The transformation should be aborted")
+	assert.Len(t, multi.Errors, 3, "The errors until the limit was exceeded should be returned")
+	assert.True(t, errors.Is(err, ErrTooManyErrors), "Should match ErrTooManyErrors")
+
+	_, err = New().With(WithCollectErrors(true), WithMaxErrors(4)).Transform(data, schema)
+
+	assert.True(t, errors.As(err, &multi), "Should return a multi error")
+	assert.False(t, errors.Is(err, ErrTooManyErrors), "Should not be aborted within the limit")
+	assert.Len(t, multi.Errors, 4, "Every error should be returned")
+}
+
+func TestCoercionRange(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  interface{}
+	}{
+		{"max uint64", uint64(math.MaxUint64), nil},
+		{"above max int64", uint64(math.MaxInt64) + 1, nil},
+		{"max int64", uint64(math.MaxInt64), int64(math.MaxInt64)},
+		{"NaN", math.NaN(), nil},
+		{"positive infinity", math.Inf(1), nil},
+		{"negative infinity", math.Inf(-1), nil},
+		{"above max int64 float", 1e19, nil},
+		{"below min int64 float", -1e19, nil},
+		{"min int64 float", float64(math.MinInt64), int64(math.MinInt64)},
+		{"float32 NaN", float32(math.NaN()), nil},
+		{"float", 42.9, int64(42)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := coerce(tt.value, "int")
+
+			if tt.want != nil {
+				assert.NoError(t, err, "Should not return any error")
+				assert.Equal(t, tt.want, result, "The result do not match")
+				return
+			}
+
+			var coercionErr *CoercionError
+
+			assert.True(t, errors.As(err, &coercionErr), "Should return a coercion error")
+			assert.True(t, errors.Is(err, strconv.ErrRange), "Should describe the value as out of range")
+			assert.Nil(t, result, "Should not return a value")
+		})
+	}
+}
+
+func TestCoercionError(t *testing.T) {
+	_, err := New().Transform(map[string]interface{}{
+		"store": map[string]interface{}{"qty": "many"},
+	}, Schema{
+		"store": Field{Key: "store", Value: Schema{
+			"qty": Field{Key: "qty", As: "int"},
+		}},
+	})
+
+	var coercionErr *CoercionError
+
+	assert.True(t, errors.As(err, &coercionErr), "Should return a coercion error")
+	assert.True(t, errors.Is(err, ErrCoercion), "Should match ErrCoercion")
+	assert.Equal(t, "string", coercionErr.Type)
+	assert.Equal(t, "int", coercionErr.As)
+	assert.Equal(t, "many", coercionErr.Value)
+	assert.EqualError(t, err, `store.qty: Cannot coerce string "many" into int`)
+
+	_, err = New().Transform(map[string]interface{}{"pin": "12ab"}, Schema{
+		"pin": Field{Key: "pin", As: "int", Classification: ClassSecret},
+	})
+
+	assert.EqualError(t, err, "pin: Cannot coerce string [REDACTED] into int", "The value of a sensitive field should be redacted")
+
+	body := New().Respond(map[string]interface{}{"qty": []int{1}}, Schema{"qty": Field{Key: "qty", As: "bool"}}, nil)
+
+	assert.Equal(t, []Result{{
+		"path":    "qty",
+		"message": GenericFieldMessage,
+		"type":    "[]int",
+		"as":      "bool",
+	}}, body["error"].(Result)["fields"], "The error body should describe the coercion")
+}
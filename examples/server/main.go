@@ -0,0 +1,20 @@
+//go:build !mantau_core
+// +build !mantau_core
+
+// Command server is an example HTTP API built with mantau. It serves users with versioned schemas
+// from a registry, sparse fieldsets and request headers read through the middleware.
+//
+//	go run ./examples/server
+//	curl 'localhost:8080/users?id=1&version=v2&fields=name,email' -H 'X-Request-Id: abc'
+//	curl 'localhost:8080/schemas?name=user&version=v2'
+package main
+
+import (
+	"log"
+	"net/http"
+)
+
+func main() {
+	log.Println("Listening on :8080")
+	log.Fatal(http.ListenAndServe(":8080", newServer()))
+}
@@ -0,0 +1,142 @@
+//go:build !mantau_core
+// +build !mantau_core
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dwadp/mantau"
+)
+
+// User is the model stored by the example server
+type User struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Password  string    `json:"password"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// errNotFound is returned for an unknown user
+var errNotFound = notFound{}
+
+type notFound struct{}
+
+func (notFound) Error() string { return "User not found" }
+func (notFound) Code() string  { return "not_found" }
+
+// PublicMessage lets Respond show the message to clients
+func (notFound) PublicMessage() string { return "User not found" }
+
+// badRequest is an error caused by the request, it's message is shown to clients
+type badRequest string
+
+func (e badRequest) Error() string         { return string(e) }
+func (e badRequest) PublicMessage() string { return string(e) }
+
+var users = map[int]User{
+	1: {ID: 1, Name: "John Doe", Username: "john", Email: "John@Example.com", Password: "secret", CreatedAt: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)},
+}
+
+// newRegistry will register every version of the user schema
+func newRegistry() *mantau.Registry {
+	registry := mantau.NewRegistry()
+
+	v1 := mantau.Schema{
+		"id":         mantau.Field{Key: "id"},
+		"name":       mantau.Field{Key: "name"},
+		"username":   mantau.Field{Key: "username", Deprecated: "use name"},
+		"email":      mantau.Field{Key: "email"},
+		"request_id": mantau.FromHeader("X-Request-Id"),
+	}
+
+	registry.Register("user", "v1", v1)
+	registry.Register("user", "v2", v1.Override(mantau.Schema{
+		"id":         mantau.Field{Key: "id", As: "string"},
+		"email":      mantau.Field{Key: "email", Normalize: mantau.NormalizeEmail},
+		"created_at": mantau.Field{Key: "created_at"},
+		"username":   mantau.Tombstone(),
+	}))
+
+	return registry
+}
+
+// newServer will create the handler of the example server
+func newServer() http.Handler {
+	registry := newRegistry()
+	m := mantau.New()
+
+	mux := http.NewServeMux()
+	mux.Handle("/schemas", mantau.NewRegistryHandler(registry))
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		version := r.URL.Query().Get("version")
+
+		if version == "" {
+			version = "v1"
+		}
+
+		schema, ok := registry.Get("user", version)
+
+		if !ok {
+			write(w, http.StatusBadRequest, m.Respond(nil, nil, badRequest("Unknown version "+version)))
+			return
+		}
+
+		schema = sparse(schema, r.URL.Query().Get("fields"))
+		id, _ := strconv.Atoi(r.URL.Query().Get("id"))
+		user, ok := users[id]
+
+		if !ok {
+			write(w, http.StatusNotFound, m.Respond(nil, schema, errNotFound))
+			return
+		}
+
+		deprecations := []mantau.Deprecation{}
+
+		// The context carries the request headers for the FromHeader fields
+		result, err := m.With(mantau.WithOnDeprecated(func(ctx context.Context, d mantau.Deprecation) {
+			deprecations = append(deprecations, d)
+		})).TransformCtx(r.Context(), user, schema)
+
+		if err != nil {
+			write(w, http.StatusInternalServerError, m.Respond(nil, schema, err))
+			return
+		}
+
+		mantau.SetDeprecationHeaders(w.Header(), deprecations)
+		write(w, http.StatusOK, mantau.Result{"ok": true, "data": result})
+	})
+
+	return mantau.HeadersMiddleware(mux)
+}
+
+// sparse will only keep the schema keys of a comma separated fieldset e.g. "name,email"
+func sparse(schema mantau.Schema, fields string) mantau.Schema {
+	if fields == "" {
+		return schema
+	}
+
+	result := mantau.Schema{}
+
+	for _, key := range strings.Split(fields, ",") {
+		if field, ok := schema[strings.TrimSpace(key)]; ok {
+			result[strings.TrimSpace(key)] = field
+		}
+	}
+
+	return result
+}
+
+func write(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	json.NewEncoder(w).Encode(body)
+}
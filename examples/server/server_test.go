@@ -0,0 +1,90 @@
+//go:build !mantau_core
+// +build !mantau_core
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func get(t *testing.T, url string, headers map[string]string) (*http.Response, map[string]interface{}) {
+	server := httptest.NewServer(newServer())
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+url, nil)
+
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer res.Body.Close()
+
+	body := map[string]interface{}{}
+	json.NewDecoder(res.Body).Decode(&body)
+
+	return res, body
+}
+
+func TestUsersV1(t *testing.T) {
+	res, body := get(t, "/users?id=1", map[string]string{"X-Request-Id": "abc"})
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "true", res.Header.Get("Deprecation"))
+	assert.Equal(t, map[string]interface{}{
+		"ok": true,
+		"data": map[string]interface{}{
+			"id":         float64(1),
+			"name":       "John Doe",
+			"username":   "john",
+			"email":      "John@Example.com",
+			"request_id": "abc",
+		},
+	}, body)
+}
+
+func TestUsersV2SparseFieldset(t *testing.T) {
+	res, body := get(t, "/users?id=1&version=v2&fields=id,email,created_at", nil)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Empty(t, res.Header.Get("Deprecation"))
+	assert.Equal(t, map[string]interface{}{
+		"ok": true,
+		"data": map[string]interface{}{
+			"id":         "1",
+			"email":      "john@example.com",
+			"created_at": "2021-01-02T03:04:05Z",
+		},
+	}, body)
+}
+
+func TestUsersErrors(t *testing.T) {
+	res, body := get(t, "/users?id=2", nil)
+
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	assert.Equal(t, map[string]interface{}{
+		"ok":    false,
+		"error": map[string]interface{}{"message": "User not found", "code": "not_found"},
+	}, body)
+
+	res, _ = get(t, "/users?id=1&version=v3", nil)
+
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestSchemas(t *testing.T) {
+	res, body := get(t, "/schemas", nil)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, map[string]interface{}{"user": []interface{}{"v1", "v2"}}, body)
+}
@@ -0,0 +1,22 @@
+package mantau
+
+// Explanation describes how a source value becomes the result, field by field. It can be encoded as JSON
+// and rendered by an admin tool to show how a record becomes an API response
+type Explanation struct {
+	// Result is the transformed value
+	Result interface{} `json:"result"`
+
+	// Fields are the traced fields ordered by their output path, see TransformTrace
+	Fields []TraceEntry `json:"fields"`
+}
+
+// Explain will transform the source and describe how every field of the result was mapped
+func (m *mantau) Explain(src interface{}, schema Schema) (*Explanation, error) {
+	result, trace, err := m.TransformTrace(src, schema)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Explanation{Result: result, Fields: trace.Entries}, nil
+}
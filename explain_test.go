@@ -0,0 +1,61 @@
+package mantau
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	m := New()
+	m.SetOpt(&Options{Hook: "json", Clearance: ClassPublic, Enforcement: EnforceRedact})
+
+	explanation, err := m.Explain(map[string]interface{}{
+		"id":     7,
+		"email":  " John@Example.com ",
+		"card":   "4242424242424242",
+		"salary": 1200,
+		"note":   "",
+	}, Schema{
+		"id":     Field{Key: "id", As: "string"},
+		"email":  Field{Key: "email", Normalize: NormalizeEmail},
+		"card":   Field{Key: "card", Mask: "last4"},
+		"salary": Field{Key: "salary", Classification: ClassSecret},
+		"note":   Field{Key: "note", OmitZero: true},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"id": "7", "email": "john@example.com", "card": "************4242", "salary": Redacted}, explanation.Result)
+	assert.Equal(t, []TraceEntry{
+		{Source: "card", Output: "card", Kind: Other, Type: "string", Outcome: OutcomeEmitted, Transformers: []string{"mask last4"}},
+		{Source: "email", Output: "email", Kind: Other, Type: "string", Outcome: OutcomeEmitted, Transformers: []string{"normalize email"}},
+		{Source: "id", Output: "id", Kind: Other, Type: "int", Outcome: OutcomeEmitted, Coercion: "int to string"},
+		{Source: "note", Output: "note", Kind: Other, Type: "string", Outcome: OutcomeOmitted},
+		{Source: "salary", Output: "salary", Kind: Other, Type: "int", Outcome: OutcomeRedacted},
+	}, explanation.Fields, "The explained fields do not match")
+
+	encoded, err := json.Marshal(explanation)
+
+	assert.NoError(t, err, "Explanation should be encoded as JSON")
+	assert.Contains(t, string(encoded), `{"source":"email","output":"email","kind":"other","type":"string","outcome":"emitted","transformers":["normalize email"]}`)
+}
+
+func TestExplainNested(t *testing.T) {
+	m := New()
+
+	explanation, err := m.Explain(User{
+		Name:        "John doe",
+		Permissions: []Permission{{"Admin", 0}},
+	}, Schema{
+		"user_permissions": Field{
+			Key:   "permissions",
+			Value: Schema{"name": Field{Key: "permission_name"}},
+		},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Len(t, explanation.Fields, 2, "Both the collection and it's field should be explained")
+	assert.Equal(t, "user_permissions[0].name", explanation.Fields[1].Output)
+	assert.Equal(t, "permissions[0].permission_name", explanation.Fields[1].Source)
+}
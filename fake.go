@@ -0,0 +1,176 @@
+package mantau
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FakeOptions configures the values generated by GenerateFake
+type FakeOptions struct {
+	// Seed makes the generated values reproducible, a zero seed uses the current time
+	Seed int64
+
+	// Elements is the number of elements generated for collections, defaults to 2
+	Elements int
+}
+
+// fakeWords are the words used for generated strings
+var fakeWords = []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel", "india", "juliet"}
+
+// fakeNames are the names used for generated name keys
+var fakeNames = []string{"Alice", "Bob", "Carol", "Dave", "Erin", "Frank", "Grace", "Heidi"}
+
+// faker generates the values of GenerateFake
+type faker struct {
+	rand     *rand.Rand
+	elements int
+}
+
+// GenerateFake will generate a Result shaped like the output of the schema, populated with plausible values
+// based on the keys and coercion types of it's fields, e.g. an "email" key gets an email address
+// and an "int" coerced field gets an int64. It can be used to seed tests and API mocks from the same schema
+func GenerateFake(schema Schema, opts FakeOptions) Result {
+	seed := opts.Seed
+
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	f := &faker{rand: rand.New(rand.NewSource(seed)), elements: opts.Elements}
+
+	if f.elements <= 0 {
+		f.elements = 2
+	}
+
+	return f.object(schema)
+}
+
+// object will generate a Result for the schema
+func (f *faker) object(schema Schema) Result {
+	result := Result{}
+	schema = schema.outputSchema()
+	keys := make([]string, 0, len(schema))
+
+	for key := range schema {
+		keys = append(keys, key)
+	}
+
+	// the keys are sorted so the same seed generates the same values
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field := schema[key]
+
+		if !isMapping(key, field) || field.KeyPattern != nil {
+			continue
+		}
+
+		value := f.field(key, field)
+
+		if nested, ok := value.(Result); ok && field.Flatten {
+			for k, v := range nested {
+				result[k] = v
+			}
+
+			continue
+		}
+
+		result[key] = value
+	}
+
+	return result
+}
+
+// field will generate the value of a single field
+func (f *faker) field(key string, field Field) interface{} {
+	if field.inject != nil {
+		if value, ok := field.inject(context.Background()); ok {
+			return value
+		}
+	}
+
+	if field.money != "" {
+		return Result{"amount": fmt.Sprintf("%d.%02d", f.rand.Intn(1000), f.rand.Intn(100)), "currency": "USD"}
+	}
+
+	nested, isSchema := field.Value.(Schema)
+
+	switch {
+	case field.Pluck != "":
+		values := make([]interface{}, f.elements)
+
+		for i := range values {
+			values[i] = f.value(field.Pluck, "")
+		}
+
+		return values
+	case isSchema && field.IndexBy != "":
+		result := Result{}
+
+		for i := 0; i < f.elements; i++ {
+			result[fmt.Sprint(i+1)] = f.object(nested)
+		}
+
+		return result
+	case isSchema:
+		return f.object(nested)
+	}
+
+	value := f.value(key, field.As)
+
+	if field.As == "string" {
+		value, _ = coerce(value, "string")
+	}
+
+	if field.Mask != "" {
+		if masked, err := mask(value, field.Mask); err == nil {
+			return masked
+		}
+	}
+
+	return value
+}
+
+// value will generate a value based on the coercion type or the name of the key
+func (f *faker) value(key, as string) interface{} {
+	switch as {
+	case "int":
+		return int64(f.rand.Intn(1000) + 1)
+	case "float":
+		return float64(f.rand.Intn(100000)) / 100
+	case "bool":
+		return f.rand.Intn(2) == 1
+	}
+
+	name := strings.ToLower(key)
+
+	switch {
+	case strings.Contains(name, "email"):
+		return fmt.Sprintf("%s%d@example.com", f.word(), f.rand.Intn(1000))
+	case strings.Contains(name, "phone"):
+		return fmt.Sprintf("+1555%07d", f.rand.Intn(10000000))
+	case strings.Contains(name, "url"), strings.Contains(name, "website"):
+		return fmt.Sprintf("https://example.com/%s", f.word())
+	case strings.HasSuffix(name, "_at"), strings.Contains(name, "date"), strings.Contains(name, "time"):
+		return time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(f.rand.Intn(365*24)) * time.Hour).Format(time.RFC3339)
+	case strings.Contains(name, "name"):
+		return fakeNames[f.rand.Intn(len(fakeNames))]
+	case name == "id", strings.HasSuffix(name, "_id"), strings.HasSuffix(key, "ID"), strings.HasSuffix(key, "Id"):
+		return int64(f.rand.Intn(100000) + 1)
+	case strings.HasPrefix(name, "is_"), strings.HasPrefix(name, "has_"):
+		return f.rand.Intn(2) == 1
+	case strings.Contains(name, "count"), strings.Contains(name, "total"), name == "age", strings.HasSuffix(name, "_age"):
+		return int64(f.rand.Intn(100))
+	}
+
+	return f.word()
+}
+
+// word will pick a random word
+func (f *faker) word() string {
+	return fakeWords[f.rand.Intn(len(fakeWords))]
+}
@@ -0,0 +1,53 @@
+package mantau
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateFake(t *testing.T) {
+	schema := Schema{
+		"id":         Field{Key: "ID"},
+		"email":      Field{Key: "Email"},
+		"age":        Field{Key: "Age", As: "float"},
+		"active":     Field{Key: "Active", As: "bool"},
+		"code":       Field{Key: "Code", As: "string"},
+		"created_at": Field{Key: "CreatedAt"},
+		"version":    Const("v1"),
+		"address": Field{Key: "Address", Value: Schema{
+			"city": Field{Key: "City"},
+		}},
+		"tags":   Field{Key: "Tags", Pluck: "name"},
+		"secret": Drop(),
+	}
+
+	result := GenerateFake(schema, FakeOptions{Seed: 42})
+
+	assert.IsType(t, int64(0), result["id"])
+	assert.Regexp(t, `^\w+\d+@example\.com$`, result["email"])
+	assert.IsType(t, float64(0), result["age"])
+	assert.IsType(t, true, result["active"])
+	assert.IsType(t, "", result["code"])
+	assert.Regexp(t, `^2020-`, result["created_at"])
+	assert.Equal(t, "v1", result["version"])
+	assert.IsType(t, Result{}, result["address"])
+	assert.Contains(t, result["address"], "city")
+	assert.Len(t, result["tags"], 2)
+	assert.NotContains(t, result, "secret")
+
+	assert.Equal(t, result, GenerateFake(schema, FakeOptions{Seed: 42}), "The same seed should generate the same result")
+}
+
+func TestGenerateFakeDottedKeys(t *testing.T) {
+	result := GenerateFake(Schema{
+		"address":          Field{Key: "Address", Value: Schema{"street": Field{Key: "Street"}}},
+		"address.city":     Field{Key: "City"},
+		"address.geo.zone": Field{Key: "Zone", As: "int"},
+	}, FakeOptions{Seed: 42})
+
+	assert.NotContains(t, result, "address.city", "The dotted key should not be emitted")
+	assert.Contains(t, result["address"], "street")
+	assert.Contains(t, result["address"], "city")
+	assert.IsType(t, int64(0), result["address"].(Result)["geo"].(Result)["zone"])
+}
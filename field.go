@@ -0,0 +1,682 @@
+package mantau
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseTag will split a hook tag like `mantau:"price,as=string,omitzero,mask=last4,round=2,truncate=80,date=long"`
+// into the matching key and the field behavior declared by it's options
+func parseTag(tag string) (string, Field) {
+	parts := strings.Split(tag, ",")
+	field := Field{Key: parts[0]}
+
+	for _, option := range parts[1:] {
+		name := strings.TrimSpace(option)
+		value := ""
+
+		if i := strings.Index(name, "="); i >= 0 {
+			name, value = name[:i], name[i+1:]
+		}
+
+		switch name {
+		case "as":
+			field.As = value
+		case "omitzero":
+			field.OmitZero = true
+		case "mask":
+			field.Mask = value
+		case "squash", "flatten":
+			field.Flatten = true
+		case "class":
+			field.Classification = Classification(value)
+		case "normalize":
+			field.Normalize = Normalizer(value)
+		case "round":
+			if places, err := strconv.Atoi(value); err == nil {
+				field.Round = Round(places)
+			}
+		case "truncate":
+			if length, err := strconv.Atoi(value); err == nil {
+				field.Truncate = Truncate(length, "...")
+			}
+		case "date":
+			field.Date = DateStyle(value)
+		}
+	}
+
+	return field.Key, field
+}
+
+// withTag will merge the behavior declared on a struct tag into the schema field,
+// options set on the schema field take precedence over the tag options
+func (f Field) withTag(tag Field) Field {
+	if f.As == "" {
+		f.As = tag.As
+	}
+
+	if !f.OmitZero {
+		f.OmitZero = tag.OmitZero
+	}
+
+	if f.Mask == "" {
+		f.Mask = tag.Mask
+	}
+
+	if !f.Flatten {
+		f.Flatten = tag.Flatten
+	}
+
+	if f.Classification == "" {
+		f.Classification = tag.Classification
+	}
+
+	if f.Normalize == "" {
+		f.Normalize = tag.Normalize
+	}
+
+	if f.Round == nil {
+		f.Round = tag.Round
+	}
+
+	if f.Truncate == nil {
+		f.Truncate = tag.Truncate
+	}
+
+	if f.Date == "" {
+		f.Date = tag.Date
+	}
+
+	return f
+}
+
+// checkMismatch will check if the field nested schema matches the source value.
+// A nested schema requires a struct, map or a collection of them, while a field without
+// a nested schema should not receive a struct or a map
+func (m *mantau) checkMismatch(field Field, value interface{}) error {
+	if isNilValue(value) {
+		return nil
+	}
+
+	if field.MapAs != "" {
+		if m.getValue(value).Kind() != reflect.Map {
+			return fmt.Errorf("emits a map but the source value is %T", value)
+		}
+
+		return nil
+	}
+
+	if field.Pluck != "" {
+		if kind := m.getValue(value).Kind(); kind != reflect.Slice && kind != reflect.Array {
+			return fmt.Errorf("plucks a collection but the source value is %T", value)
+		}
+
+		return nil
+	}
+
+	if field.IndexBy != "" {
+		if kind := m.getValue(value).Kind(); kind != reflect.Slice && kind != reflect.Array {
+			return fmt.Errorf("indexes a collection but the source value is %T", value)
+		}
+	}
+
+	container := isContainerType(reflect.TypeOf(value))
+
+	if field.Value != nil && !container && m.shouldSkipTransform(value) {
+		return fmt.Errorf("has a nested schema but the source value is %T", value)
+	}
+
+	if field.Value == nil && container {
+		return fmt.Errorf("has no nested schema but the source value is %T", value)
+	}
+
+	return nil
+}
+
+// isContainerType will check if a type is a struct, a map or a collection of them
+func isContainerType(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if isLeafType(t) {
+		return false
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return t != reflect.TypeOf(time.Time{})
+	case reflect.Map:
+		return true
+	case reflect.Slice, reflect.Array:
+		return isContainerType(t.Elem())
+	}
+
+	return false
+}
+
+// transformPluck will transform a collection into a flat list of the plucked attribute of every element.
+// Elements without the attribute are skipped
+func (m *mantau) transformPluck(field Field, src interface{}) (interface{}, error) {
+	if isNilValue(src) {
+		return nil, nil
+	}
+
+	const key = "value"
+
+	schema := Schema{key: Field{Key: field.Pluck, Value: field.Value}}
+	value := m.getValue(src)
+	result := make([]interface{}, 0, value.Len())
+
+	for i := 0; i < value.Len(); i++ {
+		m.enterIndex(i)
+		v, err := m.transformValue(value.Index(i).Interface(), schema)
+		m.leave()
+
+		if err != nil {
+			return nil, err
+		}
+
+		element, ok := v.(Result)
+
+		if !ok {
+			continue
+		}
+
+		if plucked, ok := element[key]; ok {
+			result = append(result, plucked)
+		}
+	}
+
+	return result, nil
+}
+
+// transformIndexBy will transform a collection into a Result keyed by the given attribute of every element.
+// Elements without the attribute or with a duplicate one will return an error
+func (m *mantau) transformIndexBy(attribute string, src interface{}, schema Schema) (interface{}, error) {
+	if isNilValue(src) {
+		return nil, nil
+	}
+
+	const key = "key"
+
+	keySchema := Schema{key: Field{Key: attribute}}
+	value := m.getValue(src)
+	result := make(Result, value.Len())
+
+	for i := 0; i < value.Len(); i++ {
+		element := value.Index(i).Interface()
+		m.enterIndex(i)
+
+		k, err := m.transformValue(element, keySchema)
+
+		if err != nil {
+			m.leave()
+			return nil, err
+		}
+
+		keys, _ := k.(Result)
+		index, ok := keys[key]
+
+		if !ok {
+			m.leave()
+			return nil, fmt.Errorf("Cannot find index attribute %q", attribute)
+		}
+
+		v, err := m.transformValue(element, schema)
+		m.leave()
+
+		if err != nil {
+			return nil, err
+		}
+
+		name := fmt.Sprint(index)
+
+		if _, ok := result[name]; ok {
+			return nil, fmt.Errorf("Duplicate index %q", name)
+		}
+
+		result[name] = v
+	}
+
+	return result, nil
+}
+
+// pick will return a single element of a slice or an array, or nil when the collection is empty.
+// Other values are returned as they are
+func (m *mantau) pick(src interface{}, position Position) interface{} {
+	if isNilValue(src) {
+		return nil
+	}
+
+	value := m.getValue(src)
+
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return src
+	}
+
+	if value.Len() == 0 {
+		return nil
+	}
+
+	if position == LastElement {
+		return value.Index(value.Len() - 1).Interface()
+	}
+
+	return value.Index(0).Interface()
+}
+
+// truncate will keep the first max elements of a slice or an array.
+// It returns true when the collection had more elements
+func (m *mantau) truncate(src interface{}, max int) (interface{}, bool) {
+	if isNilValue(src) {
+		return src, false
+	}
+
+	value := m.getValue(src)
+
+	if (value.Kind() != reflect.Slice && value.Kind() != reflect.Array) || value.Len() <= max {
+		return src, false
+	}
+
+	if value.Kind() == reflect.Slice {
+		return value.Slice(0, max).Interface(), true
+	}
+
+	result := reflect.MakeSlice(reflect.SliceOf(value.Type().Elem()), max, max)
+	reflect.Copy(result, value)
+
+	return result.Interface(), true
+}
+
+// applyField will apply the field behavior to a transformed value.
+// It returns false when the value should be omitted from the result
+func (m *mantau) applyField(field Field, value interface{}) (interface{}, bool, error) {
+	if field.OmitZero && (value == nil || reflect.ValueOf(value).IsZero()) {
+		return nil, false, nil
+	}
+
+	if len(field.pipe) > 0 {
+		piped, err := runPipe(field, value)
+
+		if err != nil {
+			return nil, false, redactCoercion(err, field)
+		}
+
+		value = piped
+	}
+
+	if value == nil {
+		return nil, true, nil
+	}
+
+	if field.Transform != nil {
+		transformed, err := m.transform(field, value)
+
+		if err != nil || transformed == nil {
+			return nil, err == nil, err
+		}
+
+		value = transformed
+	}
+
+	if field.Convert != nil {
+		converted, err := convert(value, field.Convert)
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		value = converted
+	}
+
+	if field.Date != "" {
+		formatted, err := m.formatDate(value, field.Date)
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		value = formatted
+	}
+
+	if empty := reflect.ValueOf(value); (empty.Kind() == reflect.Slice || empty.Kind() == reflect.Array) && empty.Len() == 0 {
+		policy := field.EmptyCollections
+
+		if policy == "" {
+			policy = m.opt.EmptyCollections
+		}
+
+		switch policy {
+		case EmptyOmit:
+			return nil, false, nil
+		case EmptyNil:
+			// A typed nil collection is kept in the result and encoded as null
+			return reflect.Zero(empty.Type()).Interface(), true, nil
+		}
+	}
+
+	var err error
+
+	if field.Normalize != "" {
+		value, err = m.normalize(value, field.Normalize)
+
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	if field.Round != nil {
+		value, err = round(value, field.Round)
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		// A rounded float coerced into a string keeps it's trailing zeros e.g. "1.50"
+		if field.As == "string" {
+			value = formatRounded(value, field.Round)
+		}
+	}
+
+	if field.As != "" {
+		value, err = coerce(value, field.As)
+
+		if err != nil {
+			return nil, false, redactCoercion(err, field)
+		}
+	}
+
+	policy := field.LargeInts
+
+	if policy == "" {
+		policy = m.opt.LargeInts
+	}
+
+	if policy == LargeIntString {
+		value = largeIntsAsString(value)
+	}
+
+	nonFinite := field.NonFinite
+
+	if nonFinite == "" {
+		nonFinite = m.opt.NonFinite
+	}
+
+	if nonFinite != "" && nonFinite != NonFiniteKeep {
+		value, err = replaceNonFinite(value, nonFinite)
+
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	if field.Mask != "" {
+		value, err = mask(value, field.Mask)
+
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	return value, true, nil
+}
+
+// maxSafeInt is the largest integer a float64, and so a JavaScript number, can represent exactly
+const maxSafeInt = 1<<53 - 1
+
+// largeIntsAsString will convert integers above maxSafeInt, or a collection of them, into strings
+func largeIntsAsString(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+
+	switch {
+	case isSigned(v.Kind()):
+		if v.Int() > maxSafeInt || v.Int() < -maxSafeInt {
+			return strconv.FormatInt(v.Int(), 10)
+		}
+	case isUnsigned(v.Kind()):
+		if v.Uint() > maxSafeInt {
+			return strconv.FormatUint(v.Uint(), 10)
+		}
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		kind := v.Type().Elem().Kind()
+
+		if !isSigned(kind) && (!isUnsigned(kind) || kind == reflect.Uint8) {
+			return value
+		}
+
+		result := make([]interface{}, v.Len())
+
+		for i := 0; i < v.Len(); i++ {
+			result[i] = largeIntsAsString(v.Index(i).Interface())
+		}
+
+		return result
+	}
+
+	return value
+}
+
+// replaceNonFinite will replace NaN and infinite floats, including the elements of float collections, based on the policy
+func replaceNonFinite(value interface{}, policy NonFinitePolicy) (interface{}, error) {
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+
+		if !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return value, nil
+		}
+
+		switch policy {
+		case NonFiniteNil:
+			// A typed nil is kept in the result and encoded as null
+			return (*float64)(nil), nil
+		case NonFiniteString:
+			return strconv.FormatFloat(f, 'g', -1, 64), nil
+		}
+
+		return nil, fmt.Errorf("%w: %v", ErrNonFinite, f)
+	case reflect.Slice, reflect.Array:
+		if kind := v.Type().Elem().Kind(); kind != reflect.Float32 && kind != reflect.Float64 {
+			return value, nil
+		}
+
+		result := make([]interface{}, v.Len())
+
+		for i := 0; i < v.Len(); i++ {
+			element, err := replaceNonFinite(v.Index(i).Interface(), policy)
+
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = element
+		}
+
+		return result, nil
+	}
+
+	return value, nil
+}
+
+// coerce will convert a value into the given type name
+func coerce(value interface{}, as string) (interface{}, error) {
+	v := reflect.ValueOf(value)
+
+	switch as {
+	case "string":
+		if s, ok := value.(fmt.Stringer); ok {
+			return s.String(), nil
+		}
+
+		switch {
+		case v.Kind() == reflect.String:
+			return v.String(), nil
+		case v.Kind() == reflect.Bool:
+			return strconv.FormatBool(v.Bool()), nil
+		case isSigned(v.Kind()):
+			return strconv.FormatInt(v.Int(), 10), nil
+		case isUnsigned(v.Kind()):
+			return strconv.FormatUint(v.Uint(), 10), nil
+		case v.Kind() == reflect.Float32:
+			return strconv.FormatFloat(v.Float(), 'f', -1, 32), nil
+		case v.Kind() == reflect.Float64:
+			return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+		}
+	case "int":
+		switch {
+		case v.Kind() == reflect.String:
+			n, err := strconv.ParseInt(strings.TrimSpace(v.String()), 10, 64)
+
+			if err != nil {
+				return nil, coercionError(value, as, err)
+			}
+
+			return n, nil
+		case v.Kind() == reflect.Bool:
+			if v.Bool() {
+				return int64(1), nil
+			}
+
+			return int64(0), nil
+		case isSigned(v.Kind()):
+			return v.Int(), nil
+		case isUnsigned(v.Kind()):
+			if v.Uint() > math.MaxInt64 {
+				return nil, coercionError(value, as, strconv.ErrRange)
+			}
+
+			return int64(v.Uint()), nil
+		case v.Kind() == reflect.Float32, v.Kind() == reflect.Float64:
+			// NaN fails both comparisons, 2^63 itself is out of range
+			if f := v.Float(); !(f >= math.MinInt64 && f < math.MaxInt64) {
+				return nil, coercionError(value, as, strconv.ErrRange)
+			}
+
+			return int64(v.Float()), nil
+		}
+	case "float":
+		switch {
+		case v.Kind() == reflect.String:
+			f, err := strconv.ParseFloat(strings.TrimSpace(v.String()), 64)
+
+			if err != nil {
+				return nil, coercionError(value, as, err)
+			}
+
+			return f, nil
+		case isNumberKind(v.Kind()):
+			return toFloat(v), nil
+		}
+	case "bool":
+		switch {
+		case v.Kind() == reflect.String:
+			b, err := strconv.ParseBool(strings.TrimSpace(v.String()))
+
+			if err != nil {
+				return nil, coercionError(value, as, err)
+			}
+
+			return b, nil
+		case v.Kind() == reflect.Bool:
+			return v.Bool(), nil
+		case isNumberKind(v.Kind()):
+			return toFloat(v) != 0, nil
+		}
+	default:
+		return nil, fmt.Errorf("Unknown coercion type %q", as)
+	}
+
+	return nil, coercionError(value, as, nil)
+}
+
+// coercionError will describe a value that cannot be coerced into the given type
+func coercionError(value interface{}, as string, err error) error {
+	return &CoercionError{Type: fmt.Sprintf("%T", value), As: as, Value: value, Err: err}
+}
+
+// mask will hide the characters of a value. The mask could be "all" to hide every character,
+// "lastN" to only show the last N characters or "firstN" to only show the first N characters
+func mask(value interface{}, spec string) (interface{}, error) {
+	s, ok := value.(string)
+
+	if !ok {
+		str, err := coerce(value, "string")
+
+		if err != nil {
+			return nil, err
+		}
+
+		s = str.(string)
+	}
+
+	runes := []rune(s)
+	visible := 0
+	fromEnd := true
+
+	switch {
+	case spec == "all":
+	case strings.HasPrefix(spec, "last"):
+		n, err := strconv.Atoi(strings.TrimPrefix(spec, "last"))
+
+		if err != nil {
+			return nil, errors.New("Invalid mask " + spec)
+		}
+
+		visible = n
+	case strings.HasPrefix(spec, "first"):
+		n, err := strconv.Atoi(strings.TrimPrefix(spec, "first"))
+
+		if err != nil {
+			return nil, errors.New("Invalid mask " + spec)
+		}
+
+		visible = n
+		fromEnd = false
+	default:
+		return nil, errors.New("Invalid mask " + spec)
+	}
+
+	if visible > len(runes) {
+		visible = len(runes)
+	}
+
+	for i := range runes {
+		if fromEnd && i < len(runes)-visible {
+			runes[i] = '*'
+		}
+
+		if !fromEnd && i >= visible {
+			runes[i] = '*'
+		}
+	}
+
+	return string(runes), nil
+}
+
+// unsupported will check if the value is a func, a channel or an unsafe pointer, or a pointer to one of them
+func unsupported(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+
+	t := reflect.TypeOf(value)
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,408 @@
+package mantau
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TaggedProduct struct {
+	Name     string  `mantau:"name"`
+	Price    float64 `mantau:"price,as=string"`
+	Discount int     `mantau:"discount,omitzero"`
+	Card     string  `mantau:"card,mask=last4"`
+	Stock    string  `mantau:"stock,as=int"`
+}
+
+func TestParseTag(t *testing.T) {
+	name, field := parseTag("price,as=string,omitzero,mask=last4")
+
+	assert.Equal(t, "price", name)
+	assert.Equal(t, Field{Key: "price", As: "string", OmitZero: true, Mask: "last4"}, field)
+
+	name, field = parseTag("email,omitempty")
+
+	assert.Equal(t, "email", name, "Unknown options should be ignored")
+	assert.Equal(t, Field{Key: "email"}, field)
+}
+
+func TestTagOptions(t *testing.T) {
+	m := New()
+	m.SetOpt(&Options{Hook: "mantau"})
+
+	schema := Schema{
+		"name":     Field{Key: "name"},
+		"price":    Field{Key: "price"},
+		"discount": Field{Key: "discount"},
+		"card":     Field{Key: "card"},
+		"stock":    Field{Key: "stock"},
+	}
+
+	result, err := m.Transform(TaggedProduct{
+		Name:  "Apple",
+		Price: 2.5,
+		Card:  "4111111111111111",
+		Stock: "20",
+	}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"name":  "Apple",
+		"price": "2.5",
+		"card":  "************1111",
+		"stock": int64(20),
+	}, result, "The result do not match")
+
+	schema["card"] = Field{Key: "card", Mask: "all"}
+
+	result, err = m.Transform(TaggedProduct{Card: "4111", Stock: "1"}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, "****", result.(Result)["card"], "Schema options should take precedence over the tag")
+
+	_, err = m.Transform(TaggedProduct{Stock: "many"}, schema)
+
+	assert.Error(t, err, "Invalid coercion should return error")
+}
+
+func TestMask(t *testing.T) {
+	tests := map[string]string{
+		"all":    "*****",
+		"last2":  "***lo",
+		"first1": "h****",
+		"last10": "hello",
+	}
+
+	for spec, want := range tests {
+		result, err := mask("hello", spec)
+
+		assert.NoError(t, err, "Should not return any error")
+		assert.Equal(t, want, result, "The result do not match")
+	}
+
+	_, err := mask("hello", "middle")
+
+	assert.Error(t, err, "Unknown mask should return error")
+}
+
+func TestSchemaMismatch(t *testing.T) {
+	m := New()
+
+	data := User{
+		Name:    "John doe",
+		Address: UserAddress{PostalCode: "809120"},
+	}
+
+	nestedOnPrimitive := Schema{
+		"username": Field{Key: "name", Value: Schema{"first": Field{Key: "first"}}},
+	}
+
+	primitiveOnNested := Schema{
+		"address": Field{Key: "user_address"},
+	}
+
+	_, err := m.Transform(data, nestedOnPrimitive)
+
+	assert.True(t, errors.Is(err, ErrSchemaMismatch), "Nested schema on a primitive should return mismatch error")
+	assert.Contains(t, err.Error(), `"username"`, "The error should describe the field")
+
+	_, err = m.Transform(data, primitiveOnNested)
+
+	assert.True(t, errors.Is(err, ErrSchemaMismatch), "Missing nested schema on a struct should return mismatch error")
+
+	m.SetOpt(&Options{Hook: "json", Mismatch: MismatchOmit})
+
+	result, err := m.Transform(data, nestedOnPrimitive)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{}, result, "Mismatched field should be omitted")
+
+	m.SetOpt(&Options{Hook: "json", Mismatch: MismatchRaw})
+
+	result, err = m.Transform(data, primitiveOnNested)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"address": data.Address}, result, "Mismatched field should use the source value")
+}
+
+func TestLargeInts(t *testing.T) {
+	data := map[string]interface{}{
+		"id":       int64(9007199254740993),
+		"small":    int64(42),
+		"negative": int64(-9007199254740993),
+		"unsigned": uint64(18446744073709551615),
+		"ids":      []int64{1, 9007199254740993},
+	}
+
+	schema := Schema{
+		"id":       Field{Key: "id"},
+		"small":    Field{Key: "small"},
+		"negative": Field{Key: "negative"},
+		"unsigned": Field{Key: "unsigned", LargeInts: LargeIntNumber},
+		"ids":      Field{Key: "ids"},
+	}
+
+	m := New()
+	m.SetOpt(&Options{Hook: "json", LargeInts: LargeIntString})
+
+	result, err := m.Transform(data, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"id":       "9007199254740993",
+		"small":    int64(42),
+		"negative": "-9007199254740993",
+		"unsigned": uint64(18446744073709551615),
+		"ids":      []interface{}{int64(1), "9007199254740993"},
+	}, result, "The result do not match")
+
+	schema["id"] = Field{Key: "id", LargeInts: LargeIntString}
+
+	result, err = New().Transform(data, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, "9007199254740993", result.(Result)["id"], "Field policy should be used")
+	assert.Equal(t, []int64{1, 9007199254740993}, result.(Result)["ids"], "Large integers should be numbers by default")
+}
+
+func TestEmptyCollections(t *testing.T) {
+	user := User{Name: "John doe", Permissions: []Permission{}}
+
+	schema := Schema{
+		"username": Field{Key: "name"},
+		"user_permissions": Field{
+			Key:   "permissions",
+			Value: Schema{"name": Field{Key: "permission_name"}},
+		},
+	}
+
+	result, err := New().Transform(user, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"username": "John doe", "user_permissions": []Result{}}, result, "Empty collections should be kept by default")
+
+	m := New().With(WithEmptyCollections(EmptyNil))
+
+	result, err = m.Transform(user, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"username": "John doe", "user_permissions": []Result(nil)}, result, "Empty collections should be nil")
+
+	m = New().With(WithEmptyCollections(EmptyOmit))
+
+	result, err = m.Transform(user, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"username": "John doe"}, result, "Empty collections should be omitted")
+
+	schema["user_permissions"] = Field{
+		Key:              "permissions",
+		Value:            Schema{"name": Field{Key: "permission_name"}},
+		EmptyCollections: EmptyArray,
+	}
+
+	result, err = m.Transform(user, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"username": "John doe", "user_permissions": []Result{}}, result, "Field policy should be used")
+}
+
+func TestMaxElements(t *testing.T) {
+	user := User{
+		Name:        "John doe",
+		Permissions: []Permission{{"Admin", 0}, {"Customer", 1}, {"Seller", 2}},
+	}
+
+	permission := Schema{"code": Field{Key: "permission_code"}}
+
+	result, err := New().Transform(user, Schema{
+		"permissions": Field{Key: "permissions", Value: permission, MaxElements: 2, MarkTruncated: true},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"permissions":           []Result{{"code": 0}, {"code": 1}},
+		"permissions_truncated": true,
+	}, result, "The result do not match")
+
+	result, err = New().Transform(map[string]interface{}{
+		"permissions": [3]Permission{{"Admin", 0}, {"Customer", 1}, {"Seller", 2}},
+	}, Schema{
+		"permissions": Field{Key: "permissions", Value: permission, MaxElements: 1},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"permissions": []Result{{"code": 0}}}, result, "Arrays should be truncated")
+
+	result, err = New().Transform(user, Schema{
+		"permissions": Field{Key: "permissions", Value: permission, MaxElements: 3, MarkTruncated: true},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"permissions": []Result{{"code": 0}, {"code": 1}, {"code": 2}}}, result, "Short collections should not be marked")
+}
+
+func TestPluck(t *testing.T) {
+	result, err := New().Transform(User{
+		Permissions: []Permission{{"Admin", 0}, {"Customer", 1}},
+		Products: []map[string]interface{}{
+			{"product_name": "Apple"},
+			{"product_qty": 2},
+		},
+	}, Schema{
+		"permissions": Field{Key: "permissions", Pluck: "permission_name"},
+		"products":    Field{Key: "products", Pluck: "product_name"},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"permissions": []interface{}{"Admin", "Customer"},
+		"products":    []interface{}{"Apple"},
+	}, result, "The result do not match")
+
+	_, err = New().Transform(User{Name: "John doe"}, Schema{
+		"name": Field{Key: "name", Pluck: "first"},
+	})
+
+	assert.True(t, errors.Is(err, ErrSchemaMismatch), "Plucking a primitive should return mismatch error")
+}
+
+func TestIndexBy(t *testing.T) {
+	schema := Schema{
+		"permissions": Field{
+			Key:     "permissions",
+			IndexBy: "permission_code",
+			Value: Schema{
+				"name": Field{Key: "permission_name"},
+			},
+		},
+	}
+
+	result, err := New().Transform(User{
+		Permissions: []Permission{{"Admin", 0}, {"Customer", 1}},
+	}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"permissions": Result{
+			"0": Result{"name": "Admin"},
+			"1": Result{"name": "Customer"},
+		},
+	}, result, "The result do not match")
+
+	_, err = New().Transform(User{
+		Permissions: []Permission{{"Admin", 0}, {"Customer", 0}},
+	}, schema)
+
+	assert.Error(t, err, "Duplicate index should return an error")
+}
+
+func TestNonFinite(t *testing.T) {
+	data := map[string]interface{}{
+		"nan":    math.NaN(),
+		"inf":    math.Inf(1),
+		"finite": 1.5,
+		"values": []float64{1, math.Inf(-1)},
+	}
+
+	schema := Schema{
+		"nan":    Field{Key: "nan"},
+		"inf":    Field{Key: "inf"},
+		"finite": Field{Key: "finite"},
+		"values": Field{Key: "values", NonFinite: NonFiniteString},
+	}
+
+	result, err := New().With(WithNonFinite(NonFiniteNil)).Transform(data, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"nan":    (*float64)(nil),
+		"inf":    (*float64)(nil),
+		"finite": 1.5,
+		"values": []interface{}{float64(1), "-Inf"},
+	}, result, "The result do not match")
+
+	encoded, err := json.Marshal(result)
+
+	assert.NoError(t, err, "The result should be encodable")
+	assert.JSONEq(t, `{"nan": null, "inf": null, "finite": 1.5, "values": [1, "-Inf"]}`, string(encoded))
+
+	_, err = New().With(WithNonFinite(NonFiniteError)).Transform(data, schema)
+
+	assert.True(t, errors.Is(err, ErrNonFinite), "Should return a non-finite error")
+}
+
+func TestFieldTransform(t *testing.T) {
+	upper := func(value interface{}) (interface{}, error) {
+		return strings.ToUpper(value.(string)), nil
+	}
+
+	schema := Schema{
+		"name": Field{Key: "name", Transform: upper},
+		"address": Field{Key: "address", Value: Schema{
+			"city": Field{Key: "city", Transform: upper, Mask: "first3"},
+		}},
+		"price": Field{Key: "price", As: "string", Transform: func(value interface{}) (interface{}, error) {
+			return fmt.Sprintf("$%.2f", value), nil
+		}},
+		"missing": Field{Key: "missing", Transform: upper},
+	}
+
+	data := map[string]interface{}{
+		"name":    "john",
+		"address": map[string]interface{}{"city": "paris"},
+		"price":   12.5,
+		"missing": nil,
+	}
+
+	result, err := New().Transform(data, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"name":    "JOHN",
+		"address": Result{"city": "PAR**"},
+		"price":   "$12.50",
+	}, result, "The result do not match")
+
+	_, err = New().Transform(data, Schema{"name": Field{Key: "name", Transform: func(value interface{}) (interface{}, error) {
+		return nil, errors.New("Invalid name")
+	}}})
+
+	assert.EqualError(t, err, "name: Invalid name")
+}
+
+func TestUnsupported(t *testing.T) {
+	type Job struct {
+		Name     string        `json:"name"`
+		Run      func() error  `json:"run"`
+		Done     chan struct{} `json:"done"`
+		Callback *func()       `json:"callback"`
+	}
+
+	src := Job{Name: "backup", Run: func() error { return nil }, Done: make(chan struct{})}
+	schema := Schema{
+		"name": Field{Key: "name"},
+		"run":  Field{Key: "run"},
+		"done": Field{Key: "done"},
+	}
+
+	result, err := New().With(WithKeepNil(true)).Transform(src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "backup"}, result, "Funcs and channels should be skipped")
+
+	result, err = New().Transform(src, Schema{"name": Field{Key: "name"}}.Wildcard())
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "backup"}, result, "Funcs and channels should not be passed through")
+
+	_, err = New().With(WithUnsupported(UnsupportedError)).Transform(src, schema)
+
+	assert.True(t, errors.Is(err, ErrUnsupportedType), "Should return an unsupported type error")
+	assert.Regexp(t, `^(run|done): Unsupported type: (run func\(\) error|done chan struct \{\}) cannot be transformed$`, err.Error())
+}
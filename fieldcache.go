@@ -0,0 +1,188 @@
+package mantau
+
+import (
+	"container/list"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ValueCache stores the values returned by the Transform callbacks of the fields created with Field.Cache,
+// see Options.ValueCache. It must be safe for concurrent use
+type ValueCache interface {
+	Get(key string) (interface{}, bool)
+	Set(key string, value interface{}, ttl time.Duration)
+}
+
+// fieldCache is the cache declaration of a field created with Field.Cache or Field.CacheAs
+type fieldCache struct {
+	// name identifies the cached values, the id of the declaration is used when it's empty
+	name string
+	id   uint64
+	ttl  time.Duration
+}
+
+// fieldCacheID is the id of the last cache declaration created with Field.Cache
+var fieldCacheID uint64
+
+// DefaultValueCacheSize is the number of values kept by the in-memory cache used when Options.ValueCache is not set
+const DefaultValueCacheSize = 10000
+
+// defaultValueCache is the cache used when Options.ValueCache is not set
+var defaultValueCache = NewMemoryValueCache(DefaultValueCacheSize)
+
+// Cache will return a copy of the field whose Transform callback results are cached for the ttl, by the value
+// they are called with, e.g. for geo lookups or currency conversions. The values are reused across objects,
+// transformations and instances sharing the cache. They are keyed by the cache declaration, so every call of Cache
+// has it's own values even for closures of the same function. Schemas built per request should declare the field once
+// or use CacheAs to share the values. Errors are not cached
+func (f Field) Cache(ttl time.Duration) Field {
+	return f.CacheAs("", ttl)
+}
+
+// CacheAs works like Cache but the cached values are keyed by the given name e.g. "fx:EUR", so fields declared
+// again e.g. by schemas built per request share them. The callbacks of a name must return the same values.
+// Without a name, it works like Cache
+func (f Field) CacheAs(name string, ttl time.Duration) Field {
+	f.cache = &fieldCache{name: name, ttl: ttl}
+
+	if name == "" {
+		f.cache.id = atomic.AddUint64(&fieldCacheID, 1)
+	}
+
+	return f
+}
+
+// transform will call the Transform callback of the field, through the value cache when the field is cached
+func (m *mantau) transform(field Field, value interface{}) (interface{}, error) {
+	if field.cache == nil {
+		return field.Transform(value)
+	}
+
+	cache := m.opt.ValueCache
+
+	if cache == nil {
+		cache = defaultValueCache
+	}
+
+	name := field.cache.name
+
+	if name == "" {
+		name = "#" + strconv.FormatUint(field.cache.id, 10)
+	}
+
+	key := name + ":" + valueKey(value)
+
+	if cached, ok := cache.Get(key); ok {
+		return cached, nil
+	}
+
+	transformed, err := field.Transform(value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cache.Set(key, transformed, field.cache.ttl)
+
+	return transformed, nil
+}
+
+// valueKey will identify a value in a cache key, primitives are written as they are
+// while other values are identified by their deep hash
+func valueKey(value interface{}) string {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return fmt.Sprintf("%T:%v", value, value)
+	}
+
+	return fmt.Sprintf("%T#%x", value, sourceHash(value))
+}
+
+// MemoryValueCache is an in-memory ValueCache keeping a bounded number of values,
+// the least recently used values are removed once it's full
+type MemoryValueCache struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+
+	// order lists the entries from the least to the most recently used
+	order *list.List
+	size  int
+}
+
+// cachedValue is a value stored in a MemoryValueCache
+type cachedValue struct {
+	key     string
+	value   interface{}
+	expires time.Time
+}
+
+// NewMemoryValueCache will create an empty in-memory value cache keeping up to size values,
+// DefaultValueCacheSize when it's not positive
+func NewMemoryValueCache(size int) *MemoryValueCache {
+	if size <= 0 {
+		size = DefaultValueCacheSize
+	}
+
+	return &MemoryValueCache{entries: map[string]*list.Element{}, order: list.New(), size: size}
+}
+
+// Get will return the value stored under the key, unless it's expired
+func (c *MemoryValueCache) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*cachedValue)
+
+	if time.Now().After(entry.expires) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+
+		return nil, false
+	}
+
+	c.order.MoveToBack(elem)
+
+	return entry.value, true
+}
+
+// Set will store the value under the key for the ttl, removing the least recently used value when the cache is full
+func (c *MemoryValueCache) Set(key string, value interface{}, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cachedValue{key: key, value: value, expires: time.Now().Add(ttl)}
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.order.MoveToBack(elem)
+
+		return
+	}
+
+	c.entries[key] = c.order.PushBack(entry)
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Front()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedValue).key)
+	}
+}
+
+// Len will return the number of values stored, including the expired values not removed yet
+func (c *MemoryValueCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
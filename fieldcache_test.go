@@ -0,0 +1,129 @@
+package mantau
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldCache(t *testing.T) {
+	calls := 0
+	lookup := func(v interface{}) (interface{}, error) {
+		calls++
+
+		if v == "nowhere" {
+			return nil, errors.New("Unknown city")
+		}
+
+		return strings.ToUpper(v.(string)), nil
+	}
+
+	schema := Schema{"city": Field{Key: "city", Transform: lookup}.Cache(time.Minute)}
+	m := New().With(WithValueCache(NewMemoryValueCache(0)))
+
+	result, err := m.Transform([]map[string]interface{}{{"city": "jakarta"}, {"city": "berlin"}, {"city": "jakarta"}}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, []Result{{"city": "JAKARTA"}, {"city": "BERLIN"}, {"city": "JAKARTA"}}, result)
+	assert.Equal(t, 2, calls, "The cached value should be reused across objects")
+
+	_, err = m.Transform(map[string]interface{}{"city": "berlin"}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, 2, calls, "The cached value should be reused across transformations")
+
+	for i := 0; i < 2; i++ {
+		_, err = m.Transform(map[string]interface{}{"city": "nowhere"}, schema)
+
+		assert.Error(t, err, "The error should be returned")
+	}
+
+	assert.Equal(t, 4, calls, "Errors should not be cached")
+
+	other := Schema{"city": Field{Key: "city", Transform: lookup}.Cache(time.Minute)}
+	_, err = m.Transform(map[string]interface{}{"city": "berlin"}, other)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, 5, calls, "A field declared again should not read the values of another declaration")
+	assert.Equal(t, schema.Hash(), other.Hash(), "The same cache declaration should have the same schema hash")
+
+	named := func() Schema {
+		return Schema{"city": Field{Key: "city", Transform: lookup}.CacheAs("city", time.Minute)}
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err = m.Transform(map[string]interface{}{"city": "berlin"}, named())
+
+		assert.NoError(t, err, "Should not return any error")
+	}
+
+	assert.Equal(t, 6, calls, "A schema built again should reuse the values cached by name")
+
+	convert := func(currency string) func(interface{}) (interface{}, error) {
+		return func(v interface{}) (interface{}, error) {
+			calls++
+			return currency, nil
+		}
+	}
+
+	result, err = m.Transform(map[string]interface{}{"price": 10}, Schema{
+		"eur": Field{Key: "price", Transform: convert("EUR")}.CacheAs("fx:EUR", time.Minute),
+		"usd": Field{Key: "price", Transform: convert("USD")}.CacheAs("fx:USD", time.Minute),
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"eur": "EUR", "usd": "USD"}, result, "Closures cached by name should not share values")
+
+	result, err = m.Transform(map[string]interface{}{"price": 10}, Schema{
+		"eur": Field{Key: "price", Transform: convert("EUR")}.Cache(time.Minute),
+		"usd": Field{Key: "price", Transform: convert("USD")}.Cache(time.Minute),
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"eur": "EUR", "usd": "USD"}, result, "Closures of the same function should not share values")
+
+	_, err = Schema{"city": Field{Key: "city"}.Cache(time.Minute)}.MarshalJSON()
+
+	assert.EqualError(t, err, `Cannot encode the field "city", cached fields cannot be encoded`)
+}
+
+func TestMemoryValueCache(t *testing.T) {
+	cache := NewMemoryValueCache(0)
+	cache.Set("fresh", 1, time.Minute)
+	cache.Set("expired", 2, -time.Second)
+
+	value, ok := cache.Get("fresh")
+
+	assert.True(t, ok, "The value should be found")
+	assert.Equal(t, 1, value)
+
+	_, ok = cache.Get("expired")
+
+	assert.False(t, ok, "An expired value should not be found")
+
+	cache = NewMemoryValueCache(2)
+	cache.Set("a", 1, time.Minute)
+	cache.Set("b", 2, time.Minute)
+	cache.Get("a")
+	cache.Set("c", 3, time.Minute)
+
+	assert.Equal(t, 2, cache.Len(), "The cache should not grow beyond it's size")
+
+	_, ok = cache.Get("b")
+
+	assert.False(t, ok, "The least recently used value should be removed")
+
+	_, ok = cache.Get("a")
+
+	assert.True(t, ok, "The recently used value should be kept")
+}
+
+func TestValueKey(t *testing.T) {
+	assert.Equal(t, "string:42", valueKey("42"))
+	assert.Equal(t, "int:42", valueKey(42))
+	assert.Equal(t, valueKey(Permission{"Admin", 1}), valueKey(Permission{"Admin", 1}))
+	assert.NotEqual(t, valueKey(Permission{"Admin", 1}), valueKey(Permission{"Admin", 2}))
+}
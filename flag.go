@@ -0,0 +1,53 @@
+package mantau
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// FlagProvider decides whether a feature flag is enabled for a transformation
+type FlagProvider interface {
+	Enabled(ctx context.Context, flag string) bool
+}
+
+// FlagFunc is a function implementing FlagProvider
+type FlagFunc func(ctx context.Context, flag string) bool
+
+// Enabled will call the function
+func (f FlagFunc) Enabled(ctx context.Context, flag string) bool {
+	return f(ctx, flag)
+}
+
+// EnvFlags will create a flag provider reading the flags from environment variables.
+// The variable of a flag is the prefix followed by the upper-cased flag name e.g. "FEATURE_NEW_CHECKOUT"
+// for the "new_checkout" flag with the "FEATURE_" prefix. Any value accepted by strconv.ParseBool is valid
+func EnvFlags(prefix string) FlagProvider {
+	return FlagFunc(func(_ context.Context, flag string) bool {
+		enabled, err := strconv.ParseBool(os.Getenv(prefix + strings.ToUpper(flag)))
+
+		return err == nil && enabled
+	})
+}
+
+// FlagGate will create a field that is only emitted when the given feature flag is enabled
+// by Options.Flags. Without a flag provider the field is never emitted
+func FlagGate(flag string, field Field) Field {
+	field.flag = flag
+
+	return field
+}
+
+// gated will check if the field is hidden behind a disabled feature flag
+func (m *mantau) gated(field Field) bool {
+	if field.flag == "" {
+		return false
+	}
+
+	if m.opt.Flags == nil {
+		return true
+	}
+
+	return !m.opt.Flags.Enabled(m.context(), field.flag)
+}
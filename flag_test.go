@@ -0,0 +1,43 @@
+package mantau
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlagGate(t *testing.T) {
+	schema := Schema{
+		"username":  Field{Key: "name"},
+		"useremail": FlagGate("show_email", Field{Key: "email"}),
+		"beta":      FlagGate("beta", Const(true)),
+	}
+
+	src := User{Name: "John doe", Email: "john@doe.com"}
+
+	result, err := New().Transform(src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"username": "John doe"}, result, "Gated fields should be hidden without a flag provider")
+
+	flags := FlagFunc(func(_ context.Context, flag string) bool {
+		return flag == "show_email"
+	})
+
+	result, err = New().With(WithFlags(flags)).Transform(src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"username": "John doe", "useremail": "john@doe.com"}, result, "The result do not match")
+}
+
+func TestEnvFlags(t *testing.T) {
+	os.Setenv("MANTAU_TEST_BETA", "true")
+	defer os.Unsetenv("MANTAU_TEST_BETA")
+
+	flags := EnvFlags("MANTAU_TEST_")
+
+	assert.True(t, flags.Enabled(context.Background(), "beta"), "The flag should be enabled")
+	assert.False(t, flags.Enabled(context.Background(), "missing"), "A missing flag should be disabled")
+}
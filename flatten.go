@@ -0,0 +1,203 @@
+package mantau
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// flattened is the transformed object of a Field.Flatten field, merged into the parent once every field is mapped
+type flattened struct {
+	key    string
+	value  Result
+	policy CollisionPolicy
+}
+
+// mergeFlattened will merge the flattened objects into the result. A key already in the result
+// returns a *DuplicateKeyError unless the collision policy of the field or the options is set
+func (m *mantau) mergeFlattened(result Result, objects []flattened) error {
+	for _, object := range objects {
+		policy := object.policy
+
+		if policy == "" {
+			policy = m.opt.Collisions
+		}
+
+		for key, value := range object.value {
+			if _, ok := result[key]; ok {
+				switch policy {
+				case CollisionKeep:
+					continue
+				case CollisionOverride:
+				default:
+					err := m.fieldError(object.key, &DuplicateKeyError{Key: key, Source: object.key})
+
+					if m.collect(err) {
+						continue
+					}
+
+					return err
+				}
+			}
+
+			result[key] = value
+		}
+	}
+
+	return nil
+}
+
+// nestKeys will move the values of the dotted schema keys e.g. "address.city" into nested objects,
+// the inverse of flattening. An object of the path that is already in the result is extended,
+// while a value that is not an object or a key that is already in the nested object returns an error
+func (m *mantau) nestKeys(result Result, schema Schema) error {
+	keys := []string{}
+
+	for key := range schema {
+		if strings.Contains(key, ".") {
+			keys = append(keys, key)
+		}
+	}
+
+	// the keys are sorted so a collision always returns the same error
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, ok := result[key]
+
+		if !ok {
+			continue
+		}
+
+		delete(result, key)
+
+		if err := nestKey(result, key, value); err != nil {
+			err = m.fieldError(key, err)
+
+			if m.collect(err) {
+				continue
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nestKey will set the value under the dotted key, creating the objects of the path
+func nestKey(result Result, key string, value interface{}) error {
+	segments := strings.Split(key, ".")
+	object := result
+
+	for i, segment := range segments[:len(segments)-1] {
+		var nested Result
+
+		switch existing := object[segment].(type) {
+		case nil:
+			nested = Result{}
+		case Result:
+			// the object is copied, it could be shared e.g. by the Options.Cache
+			nested = make(Result, len(existing)+1)
+
+			for k, v := range existing {
+				nested[k] = v
+			}
+		default:
+			return fmt.Errorf("Nested key %q collides with the %T value of %q", key, existing, strings.Join(segments[:i+1], "."))
+		}
+
+		object[segment] = nested
+		object = nested
+	}
+
+	last := segments[len(segments)-1]
+
+	if _, ok := object[last]; ok {
+		return fmt.Errorf("Nested key %q collides with an existing key", key)
+	}
+
+	object[last] = value
+
+	return nil
+}
+
+// outputSchema will return the schema shaped like it's output, the dotted keys e.g. "address.city" are moved
+// into the nested schemas of their objects like nestKeys moves their values, so the helpers walking a schema
+// e.g. GenerateAccessors see the nested objects. A dotted key colliding with a key that is not a nested object
+// is kept as it is, the transformation returns an error for it
+func (s Schema) outputSchema() Schema {
+	keys := []string{}
+
+	for key, field := range s {
+		if isMapping(key, field) && strings.Contains(key, ".") {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return s
+	}
+
+	sort.Strings(keys)
+
+	result := make(Schema, len(s))
+
+	for key, field := range s {
+		result[key] = field
+	}
+
+	for _, key := range keys {
+		if nestField(result, key, s[key]) {
+			delete(result, key)
+		}
+	}
+
+	return result
+}
+
+// nestField will add the field under the dotted key into the nested schemas of the path, the schemas
+// of the path are copied. It returns false when an entry of the path is not a nested object
+func nestField(schema Schema, key string, field Field) bool {
+	segments := strings.Split(key, ".")
+	schemas := make([]Schema, len(segments))
+	schemas[0] = schema
+
+	for i, segment := range segments[:len(segments)-1] {
+		parent, ok := schemas[i][segment]
+
+		if !ok {
+			schemas[i+1] = Schema{}
+			continue
+		}
+
+		nested, isSchema := parent.Value.(Schema)
+
+		if !isSchema || !isMapping(segment, parent) || parent.shape() != "nested" {
+			return false
+		}
+
+		schemas[i+1] = make(Schema, len(nested)+1)
+
+		for k, f := range nested {
+			schemas[i+1][k] = f
+		}
+	}
+
+	last := segments[len(segments)-1]
+
+	if _, ok := schemas[len(segments)-1][last]; ok {
+		return false
+	}
+
+	schemas[len(segments)-1][last] = field
+
+	// the copied schemas are only linked once the whole path is valid
+	for i := len(segments) - 2; i >= 0; i-- {
+		parent := schemas[i][segments[i]]
+		parent.Value = schemas[i+1]
+		schemas[i][segments[i]] = parent
+	}
+
+	return true
+}
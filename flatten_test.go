@@ -0,0 +1,175 @@
+package mantau
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type SquashedUser struct {
+	Name    string      `json:"name"`
+	Address UserAddress `json:"address,squash"`
+}
+
+func TestFlatten(t *testing.T) {
+	user := User{
+		Name:    "John doe",
+		Address: UserAddress{Address: "Street", PostalCode: "1234"},
+	}
+
+	addressSchema := Schema{
+		"address": Field{Key: "address"},
+		"code":    Field{Key: "postal_code"},
+	}
+
+	result, err := New().Transform(user, Schema{
+		"name":    Field{Key: "name"},
+		"address": Field{Key: "user_address", Value: addressSchema, Flatten: true},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John doe", "address": "Street", "code": "1234"}, result, "The result do not match")
+
+	collision := Schema{
+		"code":    Field{Key: "name"},
+		"address": Field{Key: "user_address", Value: addressSchema, Flatten: true},
+	}
+
+	_, err = New().Transform(user, collision)
+
+	assert.Error(t, err, "Colliding keys should return an error")
+
+	collision["address"] = Field{Key: "user_address", Value: addressSchema, Flatten: true, Collisions: CollisionOverride}
+	result, err = New().Transform(user, collision)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"address": "Street", "code": "1234"}, result, "Flattened keys should override the parent keys")
+
+	collision["address"] = Field{Key: "user_address", Value: addressSchema, Flatten: true, Collisions: CollisionKeep}
+	result, err = New().Transform(user, collision)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"address": "Street", "code": "John doe"}, result, "Parent keys should be kept")
+
+	_, err = New().Transform(user, Schema{"name": Field{Key: "name", Flatten: true}})
+
+	assert.Error(t, err, "Flattening a primitive should return an error")
+}
+
+func TestFlattenPointer(t *testing.T) {
+	schema := Schema{
+		"name":    Field{Key: "name"},
+		"address": Field{Key: "address", Value: Schema{"code": Field{Key: "code"}}, Flatten: true},
+	}
+
+	result, err := New().Transform(NilUser{Name: "John", Address: &NilAddress{Code: "1234"}}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John", "code": "1234"}, result, "The pointed object should be flattened")
+
+	for _, m := range []*mantau{New(), New().With(WithNilNested(NilNull)), New().With(WithKeepNil(true))} {
+		result, err = m.Transform(NilUser{Name: "John"}, schema)
+
+		assert.NoError(t, err, "Should not return any error")
+		assert.Equal(t, Result{"name": "John"}, result, "A nil object should not add any key")
+	}
+}
+
+func TestFlattenTag(t *testing.T) {
+	result, err := New().Transform(SquashedUser{
+		Name:    "John doe",
+		Address: UserAddress{Address: "Street", PostalCode: "1234"},
+	}, Schema{
+		"name":    Field{Key: "name"},
+		"address": Field{Key: "address", Value: Schema{"street": Field{Key: "address"}}},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John doe", "street": "Street"}, result, "The result do not match")
+}
+
+func TestNestedOutputKeys(t *testing.T) {
+	row := map[string]interface{}{
+		"name":        "John doe",
+		"city":        "Jakarta",
+		"postal_code": "1234",
+		"street":      "Street",
+	}
+
+	result, err := New().Transform(row, Schema{
+		"name":                 Field{Key: "name"},
+		"address.city":         Field{Key: "city"},
+		"address.location.zip": Field{Key: "postal_code"},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"name": "John doe",
+		"address": Result{
+			"city":     "Jakarta",
+			"location": Result{"zip": "1234"},
+		},
+	}, result, "The result do not match")
+
+	user := User{Name: "John doe", Address: UserAddress{Address: "Street", PostalCode: "1234"}}
+
+	result, err = New().Transform(user, Schema{
+		"address":      Field{Key: "user_address", Value: Schema{"street": Field{Key: "address"}}},
+		"address.code": Field{Key: "user_address.postal_code"},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"address": Result{"street": "Street", "code": "1234"}}, result, "The nested object should be extended")
+
+	_, err = New().Transform(row, Schema{
+		"address":      Field{Key: "street"},
+		"address.city": Field{Key: "city"},
+	})
+
+	assert.EqualError(t, err, `address.city: Nested key "address.city" collides with the string value of "address"`)
+
+	assert.Equal(t, Result{
+		"name": Result{"key": "name"},
+		"address": Result{"key": "", "fields": Result{
+			"city":     Result{"key": "city"},
+			"location": Result{"key": "", "fields": Result{"zip": Result{"key": "postal_code"}}},
+		}},
+	}, Schema{
+		"name":                 Field{Key: "name"},
+		"address.city":         Field{Key: "city"},
+		"address.location.zip": Field{Key: "postal_code"},
+	}.Describe(), "The dotted keys should be described in their nested objects")
+}
+
+func TestDuplicateKeys(t *testing.T) {
+	row := map[string]interface{}{"name": "John", "full_name": "John doe"}
+	schema := Schema{"name": Field{Key: "full_name"}}.Wildcard()
+
+	_, err := New().Transform(row, schema)
+
+	var duplicate *DuplicateKeyError
+
+	assert.True(t, errors.Is(err, ErrDuplicateKey), "The error should match ErrDuplicateKey")
+	assert.True(t, errors.As(err, &duplicate), "The error should be a *DuplicateKeyError")
+	assert.Equal(t, "name", duplicate.Key)
+
+	result, err := New().With(WithSortMapKeys(true), WithCollisions(CollisionKeep)).Transform(row, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John doe"}, result, "The first value should be kept")
+
+	result, err = New().With(WithSortMapKeys(true), WithCollisions(CollisionOverride)).Transform(row, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John"}, result, "The last value should win")
+
+	metrics := map[string]interface{}{"cpu_total": 1, "cpu.total": 2}
+
+	_, err = New().With(WithSortMapKeys(true)).Transform(metrics, Schema{
+		"cpu_$1": Field{KeyPattern: regexp.MustCompile(`^cpu[_.](\w+)$`)},
+	})
+
+	assert.EqualError(t, err, `cpu_total: Output key "cpu_total" produced from "cpu_total" is already in the result`)
+}
@@ -0,0 +1,146 @@
+//go:build !mantau_core
+// +build !mantau_core
+
+package mantau
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// accessorTypes are the Go types of the values coerced with Field.As
+var accessorTypes = map[string]string{
+	"string": "string",
+	"int":    "int64",
+	"float":  "float64",
+	"bool":   "bool",
+}
+
+// GenerateAccessors will write the Go source of a <name>View type wrapping a Result transformed with the schema,
+// with a typed method for every key, so downstream code doesn't need to index the raw map.
+// Nested schemas generate their own view types, e.g. UserAddressView, read with Address and AddressList.
+// Keys without a known type return interface{}
+func GenerateAccessors(w io.Writer, pkg, name string, schema Schema) error {
+	buf := &bytes.Buffer{}
+
+	fmt.Fprintf(buf, "// Code generated by mantau. DO NOT EDIT.\n\npackage %s\n\nimport \"github.com/dwadp/mantau\"\n", pkg)
+
+	if err := generateView(buf, name, schema); err != nil {
+		return err
+	}
+
+	src, err := format.Source(buf.Bytes())
+
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(src)
+
+	return err
+}
+
+// generateView will write the view type of the schema and the view types of it's nested schemas
+func generateView(buf *bytes.Buffer, name string, schema Schema) error {
+	view := name + "View"
+	schema = schema.outputSchema()
+
+	keys := make([]string, 0, len(schema))
+
+	for key := range schema {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	fmt.Fprintf(buf, "\n// %s reads a transformed %s through typed methods\ntype %s struct {\n\tr mantau.Result\n}\n", view, name, view)
+	fmt.Fprintf(buf, "\n// New%s will wrap a transformed %s\nfunc New%s(r mantau.Result) %s {\n\treturn %s{r: r}\n}\n", view, name, view, view, view)
+	fmt.Fprintf(buf, "\n// Result will return the wrapped result\nfunc (v %s) Result() mantau.Result {\n\treturn v.r\n}\n", view)
+
+	methods := map[string]string{"Result": ""}
+	nested := []string{}
+
+	for _, key := range keys {
+		field := schema[key]
+
+		if !isMapping(key, field) || field.KeyPattern != nil || field.Flatten {
+			continue
+		}
+
+		method := accessorName(key)
+
+		if method == "" {
+			return fmt.Errorf("Cannot generate an accessor for the key %q", key)
+		}
+
+		if other, ok := methods[method]; ok {
+			return fmt.Errorf("The keys %q and %q generate the same accessor %s.%s", other, key, view, method)
+		}
+
+		methods[method] = key
+
+		if _, ok := field.Value.(Schema); ok && field.As == "" && field.Pluck == "" && field.IndexBy == "" && field.MapAs == "" {
+			if _, ok := methods[method+"List"]; ok {
+				return fmt.Errorf("The key %q generates the accessor %s.%sList twice", key, view, method)
+			}
+
+			methods[method+"List"] = key
+			nestedView := name + method + "View"
+
+			fmt.Fprintf(buf, "\n// %s will return the %q object\nfunc (v %s) %s() %s {\n\tr, _ := v.r[%q].(mantau.Result)\n\treturn New%s(r)\n}\n",
+				method, key, view, method, nestedView, key, nestedView)
+			fmt.Fprintf(buf, "\n// %sList will return the %q collection\nfunc (v %s) %sList() []%s {\n\tr, _ := v.r[%q].([]mantau.Result)\n\tviews := make([]%s, len(r))\n\n\tfor i := range r {\n\t\tviews[i] = New%s(r[i])\n\t}\n\n\treturn views\n}\n",
+				method, key, view, method, nestedView, key, nestedView, nestedView)
+
+			nested = append(nested, key)
+			continue
+		}
+
+		if typ, ok := accessorTypes[field.As]; ok {
+			fmt.Fprintf(buf, "\n// %s will return the %q value\nfunc (v %s) %s() %s {\n\tvalue, _ := v.r[%q].(%s)\n\treturn value\n}\n",
+				method, key, view, method, typ, key, typ)
+			continue
+		}
+
+		fmt.Fprintf(buf, "\n// %s will return the %q value\nfunc (v %s) %s() interface{} {\n\treturn v.r[%q]\n}\n", method, key, view, method, key)
+	}
+
+	for _, key := range nested {
+		if err := generateView(buf, name+accessorName(key), schema[key].Value.(Schema)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// accessorName will convert a result key into an exported method name, e.g. "user_id" into "UserId"
+func accessorName(key string) string {
+	name := strings.Builder{}
+	upper := true
+
+	for _, r := range key {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+
+		if name.Len() == 0 && !unicode.IsLetter(r) {
+			return ""
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+
+		name.WriteRune(r)
+	}
+
+	return name.String()
+}
@@ -0,0 +1,78 @@
+//go:build !mantau_core
+// +build !mantau_core
+
+package mantau
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateAccessors(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	err := GenerateAccessors(buf, "views", "User", Schema{
+		"email":   Field{Key: "Email", As: "string"},
+		"age":     Field{Key: "Age", As: "int"},
+		"user_id": Field{Key: "ID"},
+		"address": Field{Key: "Address", Value: Schema{
+			"postal_code": Field{Key: "PostalCode", As: "string"},
+		}},
+		"*":      Field{},
+		"secret": Drop(),
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+
+	src := buf.String()
+
+	_, err = parser.ParseFile(token.NewFileSet(), "views.go", src, 0)
+	assert.NoError(t, err, "The generated source should be valid Go")
+
+	assert.Contains(t, src, "package views")
+	assert.Contains(t, src, "type UserView struct")
+	assert.Contains(t, src, "func (v UserView) Email() string")
+	assert.Contains(t, src, "func (v UserView) Age() int64")
+	assert.Contains(t, src, "func (v UserView) UserId() interface{}")
+	assert.Contains(t, src, "func (v UserView) Address() UserAddressView")
+	assert.Contains(t, src, "func (v UserView) AddressList() []UserAddressView")
+	assert.Contains(t, src, "func (v UserAddressView) PostalCode() string")
+	assert.NotContains(t, src, "Secret()")
+}
+
+func TestGenerateAccessorsCollision(t *testing.T) {
+	err := GenerateAccessors(&bytes.Buffer{}, "views", "User", Schema{
+		"user_id": Field{Key: "ID"},
+		"userId":  Field{Key: "ID"},
+	})
+
+	assert.Error(t, err, "Should return an error when two keys generate the same accessor")
+
+	err = GenerateAccessors(&bytes.Buffer{}, "views", "User", Schema{
+		"1st": Field{Key: "First"},
+	})
+
+	assert.Error(t, err, "Should return an error when a key cannot be a method name")
+}
+
+func TestGenerateAccessorsDottedKeys(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	err := GenerateAccessors(buf, "views", "User", Schema{
+		"name":         Field{Key: "Name", As: "string"},
+		"address.city": Field{Key: "City", As: "string"},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+
+	src := buf.String()
+
+	assert.Contains(t, src, "func (v UserView) Address() UserAddressView")
+	assert.Contains(t, src, "func (v UserAddressView) City() string")
+	assert.Contains(t, src, `v.r["address"]`)
+	assert.NotContains(t, src, `"address.city"]`, "The dotted key should not be read from the result")
+}
@@ -0,0 +1,68 @@
+//go:build go1.18
+// +build go1.18
+
+package mantau
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	Page[T any] struct {
+		Items []T `json:"items"`
+		Total int `json:"total"`
+	}
+
+	Box[T any] struct {
+		Value T `json:"value"`
+	}
+)
+
+func TestGenericStructs(t *testing.T) {
+	users, err := New().Transform(Page[User]{
+		Items: []User{{Name: "John doe"}, {Name: "Jane doe"}},
+		Total: 2,
+	}, Schema{
+		"items": Field{Key: "items", Value: Schema{"username": Field{Key: "name"}}},
+		"total": Field{Key: "total"},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"items": []Result{{"username": "John doe"}, {"username": "Jane doe"}},
+		"total": 2,
+	}, users, "The result do not match")
+
+	authors, err := New().Transform(Page[*Author]{
+		Items: []*Author{{FirstName: "John"}, nil},
+		Total: 1,
+	}, Schema{
+		"items": Field{Key: "items", Value: Schema{"name": Field{Key: "first_name"}}},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"items": []Result{{"name": "John"}}}, authors, "The result do not match")
+
+	nested, err := New().Transform(Box[Page[Permission]]{
+		Value: Page[Permission]{Items: []Permission{{"Admin", 1}}, Total: 1},
+	}, Schema{
+		"page": Field{Key: "value", Value: Schema{
+			"permissions": Field{Key: "items", Pluck: "permission_name"},
+		}},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"page": Result{"permissions": []interface{}{"Admin"}}}, nested, "The result do not match")
+
+	primitive, err := New().Transform(Box[string]{Value: "hello"}, Schema{"value": Field{Key: "value"}})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"value": "hello"}, primitive, "The result do not match")
+
+	_, err = New().Transform(Box[User]{Value: User{Name: "John doe"}}, Schema{"value": Field{Key: "value"}})
+
+	assert.True(t, errors.Is(err, ErrSchemaMismatch), "A type parameter struct without a nested schema should be a mismatch")
+}
@@ -3,7 +3,7 @@ module github.com/dwadp/mantau
 go 1.14
 
 require (
-	github.com/stretchr/objx v0.3.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/stretchr/testify v1.6.1
-	gopkg.in/yaml.v3 v3.0.0-20200615113413-eeeca48fe776 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
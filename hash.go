@@ -0,0 +1,157 @@
+package mantau
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+)
+
+// Hash will return a stable digest of the schema definition, it changes whenever the output shape changes.
+// It can be used to build ETags or cache keys. Functions, like the finalize function of WithFinalize,
+// are only hashed by their presence
+func (s Schema) Hash() string {
+	h := sha256.New()
+	s.writeHash(h)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeHash will write the definition of the schema ordered by it's keys
+func (s Schema) writeHash(w io.Writer) {
+	keys := make([]string, 0, len(s))
+
+	for key := range s {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fmt.Fprintf(w, "%q{", key)
+		s[key].writeHash(w)
+		fmt.Fprint(w, "}")
+	}
+}
+
+// writeHash will write every non-zero setting of the field
+func (f Field) writeHash(w io.Writer) {
+	value := reflect.ValueOf(f)
+
+	for i := 0; i < value.NumField(); i++ {
+		name, v := value.Type().Field(i).Name, value.Field(i)
+
+		// The page of TransformPage is not a part of the definition
+		if v.IsZero() || name == "page" {
+			continue
+		}
+
+		switch {
+		case v.Kind() == reflect.Func:
+			fmt.Fprintf(w, "%s:func;", name)
+		case name == "cache":
+			fmt.Fprintf(w, "%s:%q:%v;", name, f.cache.name, f.cache.ttl)
+		case name == "pipe":
+			// the ops are known by their name and the arguments of the built-in ops
+			fmt.Fprintf(w, "%s:", name)
+
+			for _, op := range f.pipe {
+				fmt.Fprintf(w, "%q(", op.Name)
+
+				for _, param := range op.params {
+					fmt.Fprintf(w, "%s,", valueKey(param))
+				}
+
+				fmt.Fprint(w, "),")
+			}
+
+			fmt.Fprint(w, ";")
+		case name == "KeyPattern":
+			fmt.Fprintf(w, "%s:%q;", name, f.KeyPattern.String())
+		case v.Kind() == reflect.Ptr:
+			fmt.Fprintf(w, "%s:%#v;", name, v.Elem())
+		case name == "Value":
+			fmt.Fprintf(w, "%s:", name)
+			writeValueHash(w, f.Value)
+			fmt.Fprint(w, ";")
+		default:
+			fmt.Fprintf(w, "%s:%#v;", name, v)
+		}
+	}
+}
+
+// writeValueHash will write the nested schema of a field
+func writeValueHash(w io.Writer, value interface{}) {
+	switch nested := value.(type) {
+	case Schema:
+		fmt.Fprint(w, "{")
+		nested.writeHash(w)
+		fmt.Fprint(w, "}")
+	case map[string]Schema:
+		names := make([]string, 0, len(nested))
+
+		for name := range nested {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			fmt.Fprintf(w, "%q{", name)
+			nested[name].writeHash(w)
+			fmt.Fprint(w, "}")
+		}
+	default:
+		fmt.Fprintf(w, "%#v", value)
+	}
+}
+
+// callsFuncs will check if the output of the schema depends on callbacks or injected values, like Field.Transform
+// or Const fields, including the nested schemas. The hash only records their presence, two closures of the same
+// function cannot be told apart, so results of these schemas cannot be cached by the hash
+func (s Schema) callsFuncs() bool {
+	for _, field := range s {
+		if field.callsFuncs() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// callsFuncs will check if the field calls a callback or injects a value
+func (f Field) callsFuncs() bool {
+	if f.Compute != nil || f.Transform != nil || f.finalize != nil || f.inject != nil {
+		return true
+	}
+
+	for _, op := range f.pipe {
+		// the built-in ops are known by their arguments, unless an argument is a function e.g. Validate
+		if op.params == nil {
+			return true
+		}
+
+		for _, param := range op.params {
+			if reflect.ValueOf(param).Kind() == reflect.Func {
+				return true
+			}
+		}
+	}
+
+	switch nested := f.Value.(type) {
+	case Schema:
+		return nested.callsFuncs()
+	case map[string]Schema:
+		for _, schema := range nested {
+			if schema.callsFuncs() {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return f.Value != nil && reflect.ValueOf(f.Value).Kind() == reflect.Func
+}
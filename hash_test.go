@@ -0,0 +1,34 @@
+package mantau
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaHash(t *testing.T) {
+	schema := func() Schema {
+		return Schema{
+			"username": Field{Key: "name"},
+			"permissions": Field{
+				Key: "permissions",
+				Value: Schema{
+					"code": Field{Key: "permission_code"},
+					"name": Field{Key: "permission_name", Mask: "first2"},
+				},
+			},
+		}
+	}
+
+	hash := schema().Hash()
+
+	assert.Len(t, hash, 64, "The hash should be a hex encoded sha256 digest")
+	assert.Equal(t, hash, schema().Hash(), "The hash should be stable")
+
+	changed := schema()
+	changed["permissions"].Value.(Schema)["name"] = Field{Key: "permission_name"}
+
+	assert.NotEqual(t, hash, changed.Hash(), "The hash should change with a nested field")
+	assert.NotEqual(t, hash, schema().Override(Schema{"email": Field{Key: "email"}}).Hash(), "The hash should change with a new key")
+	assert.NotEqual(t, hash, schema().WithFinalize(func(r Result) (Result, error) { return r, nil }).Hash(), "The hash should change with a finalize function")
+}
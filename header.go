@@ -0,0 +1,38 @@
+package mantau
+
+import (
+	"context"
+	"strings"
+)
+
+// headersKey is the context key of the headers stored with ContextWithHeaders
+type headersKey struct{}
+
+// ContextWithHeaders will store the request headers in the context, so FromHeader fields can read them.
+// Both http.Header and gRPC metadata (metadata.MD) can be stored, header names are case insensitive
+func ContextWithHeaders(ctx context.Context, headers map[string][]string) context.Context {
+	normalized := make(map[string][]string, len(headers))
+
+	for name, values := range headers {
+		name = strings.ToLower(name)
+		normalized[name] = append(normalized[name], values...)
+	}
+
+	return context.WithValue(ctx, headersKey{}, normalized)
+}
+
+// FromHeader will create a field emitting the first value of the request header stored with ContextWithHeaders
+// in the context passed to TransformCtx e.g. "X-Request-Id". The key is omitted when the header is missing
+func FromHeader(name string) Field {
+	name = strings.ToLower(name)
+
+	return Field{inject: func(ctx context.Context) (interface{}, bool) {
+		headers, _ := ctx.Value(headersKey{}).(map[string][]string)
+
+		if values := headers[name]; len(values) > 0 {
+			return values[0], true
+		}
+
+		return nil, false
+	}}
+}
@@ -0,0 +1,23 @@
+package mantau
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromHeader(t *testing.T) {
+	schema := Schema{
+		"username":   Field{Key: "name"},
+		"request_id": FromHeader("X-Request-Id"),
+		"region":     FromHeader("x-region"),
+	}
+
+	// gRPC metadata keys are lower cased
+	ctx := ContextWithHeaders(context.Background(), map[string][]string{"x-region": {"eu-west-1"}})
+	result, err := New().TransformCtx(ctx, User{Name: "John doe"}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"username": "John doe", "region": "eu-west-1"}, result, "The result do not match")
+}
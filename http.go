@@ -0,0 +1,92 @@
+//go:build !mantau_core
+// +build !mantau_core
+
+package mantau
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// registryHandler will serve the schemas of a registry as JSON
+type registryHandler struct {
+	registry *Registry
+}
+
+// NewRegistryHandler will create an http.Handler for discovering the schemas of the given registry.
+// Without any query it lists every schema name and it's versions, with the name and version
+// query parameters it renders the described output shape of that schema, the latest version
+// is described when the version is not given
+func NewRegistryHandler(r *Registry) http.Handler {
+	return &registryHandler{registry: r}
+}
+
+// ServeHTTP will render the registered schemas as JSON
+func (h *registryHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := req.URL.Query().Get("name")
+
+	if name == "" {
+		list := Result{}
+
+		for _, name := range h.registry.Names() {
+			list[name] = h.registry.Versions(name)
+		}
+
+		h.write(w, http.StatusOK, list)
+		return
+	}
+
+	version := req.URL.Query().Get("version")
+
+	if version == "" {
+		version, _ = h.registry.Latest(name)
+	}
+
+	schema, ok := h.registry.Get(name, version)
+
+	if !ok {
+		h.write(w, http.StatusNotFound, Result{"error": "Schema not found"})
+		return
+	}
+
+	h.write(w, http.StatusOK, Result{
+		"name":    name,
+		"version": version,
+		"fields":  schema.Describe(),
+	})
+}
+
+func (h *registryHandler) write(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	json.NewEncoder(w).Encode(body)
+}
+
+// HeadersMiddleware will store the headers of every request in it's context, see ContextWithHeaders
+func HeadersMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(ContextWithHeaders(r.Context(), r.Header)))
+	})
+}
+
+// SetDeprecationHeaders will set the Deprecation header and a Warning header for every deprecated field,
+// so clients are told to move off the fields they still use
+func SetDeprecationHeaders(h http.Header, deprecations []Deprecation) {
+	if len(deprecations) == 0 {
+		return
+	}
+
+	h.Set("Deprecation", "true")
+
+	for _, d := range deprecations {
+		h.Add("Warning", fmt.Sprintf("299 - %q", fmt.Sprintf("%s is deprecated: %s", d.Path, d.Message)))
+	}
+}
@@ -0,0 +1,120 @@
+//go:build !mantau_core
+// +build !mantau_core
+
+package mantau
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryHandler(t *testing.T) {
+	r := NewRegistry()
+
+	r.Register("user", "v1", Schema{
+		"username": Field{Key: "name"},
+	})
+	r.Register("user", "v2", Schema{
+		"username": Field{Key: "name"},
+		"address": Field{
+			Key: "user_address",
+			Value: Schema{
+				"code": Field{Key: "postal_code"},
+			},
+		},
+	})
+
+	handler := NewRegistryHandler(r)
+
+	t.Run("ListSchemas", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		var body map[string]interface{}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, map[string]interface{}{"user": []interface{}{"v1", "v2"}}, body)
+	})
+
+	t.Run("DescribeSchema", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?name=user&version=v2", nil))
+
+		var body map[string]interface{}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, map[string]interface{}{
+			"username": map[string]interface{}{"key": "name"},
+			"address": map[string]interface{}{
+				"key": "user_address",
+				"fields": map[string]interface{}{
+					"code": map[string]interface{}{"key": "postal_code"},
+				},
+			},
+		}, body["fields"])
+	})
+
+	t.Run("UnknownSchema", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?name=user&version=v3", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("LatestSchema", func(t *testing.T) {
+		r.Register("user", "v10", Schema{"name": Field{Key: "name"}})
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?name=user", nil))
+
+		var body map[string]interface{}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, "v10", body["version"], "The latest version should be described")
+		assert.Equal(t, map[string]interface{}{"name": map[string]interface{}{"key": "name"}}, body["fields"])
+
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?name=missing", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestHeadersMiddleware(t *testing.T) {
+	schema := Schema{
+		"username":   Field{Key: "name"},
+		"request_id": FromHeader("X-Request-Id"),
+	}
+
+	var result interface{}
+	var err error
+
+	handler := HeadersMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, err = New().TransformCtx(r.Context(), User{Name: "John doe"}, schema)
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Request-Id", "abc-123")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"username": "John doe", "request_id": "abc-123"}, result, "The result do not match")
+}
+
+func TestSetDeprecationHeaders(t *testing.T) {
+	h := http.Header{}
+	SetDeprecationHeaders(h, []Deprecation{
+		{Path: "address.zip", Message: "use postal_code"},
+		{Path: "username", Message: "use name"},
+	})
+
+	assert.Equal(t, "true", h.Get("Deprecation"))
+	assert.Equal(t, []string{`299 - "address.zip is deprecated: use postal_code"`, `299 - "username is deprecated: use name"`}, h.Values("Warning"))
+}
@@ -0,0 +1,134 @@
+package mantau
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// included stores the shared entities of TransformIncluded
+type included struct {
+	// ids are the generated ids of entities without an "id" key, by source pointer
+	ids map[includeKey]string
+
+	// next is the last generated id
+	next int
+
+	// entities are the included entities by type and id
+	entities map[includeKey]Result
+
+	// sources are the source elements of the objects transformed from collections, by result pointer.
+	// Elements that are not transformed into an object e.g. nil pointers are dropped from the collection,
+	// so an object is not always at the index of it's source element
+	sources map[uintptr]interface{}
+}
+
+// includeKey identifies an included entity by it's type and either it's id or source pointer
+type includeKey struct {
+	typ     string
+	id      string
+	pointer uintptr
+}
+
+// TransformIncluded works like Transform but replaces the nested entities of fields with Field.Include set
+// by a {"type": ..., "id": ...} reference, and returns every referenced entity once in an "included" collection
+// next to the "data", similar to JSON:API compound documents.
+// The id of an entity is it's "id" key, when it doesn't have one an id is generated for every source pointer.
+// Shared pointers are transformed once, see Options.Memoize
+func (m *mantau) TransformIncluded(src interface{}, schema Schema) (Result, error) {
+	c := m.withState()
+	opt := *c.opt
+	opt.Memoize = true
+	c.opt = &opt
+	c.state.included = &included{
+		ids:      map[includeKey]string{},
+		entities: map[includeKey]Result{},
+		sources:  map[uintptr]interface{}{},
+	}
+
+	data, err := c.run(src, schema)
+
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]Result, 0, len(c.state.included.entities))
+
+	for _, entity := range c.state.included.entities {
+		entities = append(entities, entity)
+	}
+
+	sort.SliceStable(entities, func(i, j int) bool {
+		if entities[i]["type"] != entities[j]["type"] {
+			return entities[i]["type"].(string) < entities[j]["type"].(string)
+		}
+
+		return entities[i]["id"].(string) < entities[j]["id"].(string)
+	})
+
+	return Result{"data": data, "included": entities}, nil
+}
+
+// include will replace a transformed entity or collection of entities by their references
+func (m *mantau) include(typ string, src, value interface{}) interface{} {
+	if m.state == nil || m.state.included == nil || typ == "" {
+		return value
+	}
+
+	switch v := value.(type) {
+	case Result:
+		return m.includeEntity(typ, src, v)
+	case []Result:
+		refs := make([]Result, len(v))
+
+		for i, entity := range v {
+			refs[i] = m.includeEntity(typ, m.state.included.sources[reflect.ValueOf(entity).Pointer()], entity)
+		}
+
+		return refs
+	}
+
+	return value
+}
+
+// includeSource will record the source element of an object transformed from a collection
+func (m *mantau) includeSource(entity Result, src interface{}) {
+	if m.state != nil && m.state.included != nil {
+		m.state.included.sources[reflect.ValueOf(entity).Pointer()] = src
+	}
+}
+
+// includeEntity will add the entity to the included collection and return it's reference
+func (m *mantau) includeEntity(typ string, src interface{}, entity Result) Result {
+	inc := m.state.included
+	id := ""
+
+	if v, ok := entity["id"]; ok {
+		id = fmt.Sprint(v)
+	} else {
+		key := includeKey{typ: typ}
+
+		if src != nil && reflect.TypeOf(src).Kind() == reflect.Ptr {
+			key.pointer = reflect.ValueOf(src).Pointer()
+			id = inc.ids[key]
+		}
+
+		if id == "" {
+			inc.next++
+			id = strconv.Itoa(inc.next)
+
+			if key.pointer != 0 {
+				inc.ids[key] = id
+			}
+		}
+	}
+
+	key := includeKey{typ: typ, id: id}
+
+	if _, ok := inc.entities[key]; !ok {
+		inc.entities[key] = Result{"type": typ, "id": id, "attributes": entity}
+	}
+
+	return Result{"type": typ, "id": id}
+}
@@ -0,0 +1,105 @@
+package mantau
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformIncluded(t *testing.T) {
+	author := &Author{FirstName: "John", LastName: "Doe"}
+	books := []Book{
+		{Title: "First", Author: author},
+		{Title: "Second", Author: author},
+		{Title: "Third", Author: &Author{FirstName: "Jane", LastName: "Doe"}},
+	}
+
+	schema := Schema{
+		"title": Field{Key: "title"},
+		"author": Field{
+			Key:     "author",
+			Include: "authors",
+			Value: Schema{
+				"name": Field{Key: "first_name"},
+			},
+		},
+	}
+
+	result, err := New().TransformIncluded(books, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"data": []Result{
+			{"title": "First", "author": Result{"type": "authors", "id": "1"}},
+			{"title": "Second", "author": Result{"type": "authors", "id": "1"}},
+			{"title": "Third", "author": Result{"type": "authors", "id": "2"}},
+		},
+		"included": []Result{
+			{"type": "authors", "id": "1", "attributes": Result{"name": "John"}},
+			{"type": "authors", "id": "2", "attributes": Result{"name": "Jane"}},
+		},
+	}, result, "The result do not match")
+
+	plain, err := New().Transform(books, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John"}, plain.([]Result)[0]["author"], "Transform should keep the nested entity")
+}
+
+func TestTransformIncludedWithID(t *testing.T) {
+	result, err := New().TransformIncluded(User{
+		Permissions: []Permission{{"Admin", 1}, {"Customer", 2}, {"Admin", 1}},
+	}, Schema{
+		"permissions": Field{
+			Key:     "permissions",
+			Include: "permissions",
+			Value: Schema{
+				"id":   Field{Key: "permission_code"},
+				"name": Field{Key: "permission_name"},
+			},
+		},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"data": Result{
+			"permissions": []Result{
+				{"type": "permissions", "id": "1"},
+				{"type": "permissions", "id": "2"},
+				{"type": "permissions", "id": "1"},
+			},
+		},
+		"included": []Result{
+			{"type": "permissions", "id": "1", "attributes": Result{"id": 1, "name": "Admin"}},
+			{"type": "permissions", "id": "2", "attributes": Result{"id": 2, "name": "Customer"}},
+		},
+	}, result, "The result do not match")
+}
+
+func TestTransformIncludedNilElement(t *testing.T) {
+	type Anthology struct {
+		Authors []*Author `json:"authors"`
+		Main    *Author   `json:"main"`
+	}
+
+	john := &Author{FirstName: "John"}
+	jane := &Author{FirstName: "Jane"}
+	author := Schema{"name": Field{Key: "first_name"}}
+
+	result, err := New().TransformIncluded(Anthology{
+		Authors: []*Author{nil, john, jane},
+		Main:    john,
+	}, Schema{
+		"authors": Field{Key: "authors", Include: "authors", Value: author},
+		"main":    Field{Key: "main", Include: "authors", Value: author},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+
+	data := result["data"].(Result)
+	authors := data["authors"].([]Result)
+
+	assert.Len(t, authors, 2, "The nil element should be dropped")
+	assert.Equal(t, authors[0], data["main"], "The main author should reference the same entity as the collection")
+	assert.Len(t, result["included"], 2, "Every author should be included once")
+}
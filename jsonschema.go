@@ -0,0 +1,332 @@
+package mantau
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// jsonSchema is the supported subset of a JSON Schema document
+type jsonSchema struct {
+	Type                 interface{}            `json:"type"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties json.RawMessage        `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	Enum                 []interface{}          `json:"enum"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	MinLength            *int                   `json:"minLength"`
+	MaxLength            *int                   `json:"maxLength"`
+	MinItems             *int                   `json:"minItems"`
+	MaxItems             *int                   `json:"maxItems"`
+	Pattern              string                 `json:"pattern"`
+
+	pattern *regexp.Regexp
+
+	// closed rejects the keys missing from the properties, additional validates them when it's a schema
+	closed     bool
+	additional *jsonSchema
+}
+
+// unsupportedKeywords are the keywords constraining values that are not supported, they are rejected instead of being ignored
+var unsupportedKeywords = []string{
+	"$ref", "$dynamicRef", "$recursiveRef", "oneOf", "anyOf", "allOf", "not", "if", "then", "else",
+	"const", "exclusiveMinimum", "exclusiveMaximum", "multipleOf", "uniqueItems", "contains", "minContains",
+	"maxContains", "prefixItems", "additionalItems", "unevaluatedItems", "unevaluatedProperties", "patternProperties",
+	"propertyNames", "minProperties", "maxProperties", "dependencies", "dependentRequired", "dependentSchemas",
+}
+
+// UnmarshalJSON will decode a schema, returning an error for the unsupported keywords
+func (s *jsonSchema) UnmarshalJSON(data []byte) error {
+	var keywords map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &keywords); err != nil {
+		return err
+	}
+
+	for _, keyword := range unsupportedKeywords {
+		if _, ok := keywords[keyword]; ok {
+			return fmt.Errorf("the keyword %q is not supported", keyword)
+		}
+	}
+
+	if items, ok := keywords["items"]; ok && bytes.HasPrefix(bytes.TrimSpace(items), []byte("[")) {
+		return errors.New(`the keyword "items" is only supported with a schema`)
+	}
+
+	// the type has no UnmarshalJSON method, so it's decoded without coming back here
+	type plain jsonSchema
+
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+// ValidateAgainst will validate the result against a JSON Schema document, so the transformed output
+// can be checked against a published contract in tests and canary checks. The keywords type, properties,
+// required, additionalProperties, items, enum, minimum, maximum, minLength, maxLength, minItems, maxItems
+// and pattern are supported. A schema using another keyword constraining values e.g. $ref, oneOf or const returns
+// an error, since ignoring it would accept results violating the contract, annotations like description are ignored.
+// Every violation is returned in a *MultiError matching ErrContractViolation, the path of the result itself is "$"
+func (r Result) ValidateAgainst(jsonSchema []byte) error {
+	schema, err := parseJSONSchema(jsonSchema)
+
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(r)
+
+	if err != nil {
+		return err
+	}
+
+	var doc interface{}
+
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return err
+	}
+
+	errs := schema.validate("", doc, nil)
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		return pathLess(errs[i].Path, errs[j].Path)
+	})
+
+	return &MultiError{Errors: errs}
+}
+
+// parseJSONSchema will decode a JSON Schema document and compile it's patterns
+func parseJSONSchema(doc []byte) (*jsonSchema, error) {
+	schema := &jsonSchema{}
+
+	if err := json.NewDecoder(bytes.NewReader(doc)).Decode(schema); err != nil {
+		return nil, fmt.Errorf("Invalid JSON schema: %v", err)
+	}
+
+	if err := schema.compile(); err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// compile will compile the patterns of the schema and it's subschemas
+func (s *jsonSchema) compile() error {
+	if err := s.compileAdditional(); err != nil {
+		return err
+	}
+
+	if s.Pattern != "" {
+		pattern, err := regexp.Compile(s.Pattern)
+
+		if err != nil {
+			return fmt.Errorf("Invalid JSON schema pattern %q: %v", s.Pattern, err)
+		}
+
+		s.pattern = pattern
+	}
+
+	for _, property := range s.Properties {
+		if property == nil {
+			continue
+		}
+
+		if err := property.compile(); err != nil {
+			return err
+		}
+	}
+
+	if s.Items != nil {
+		return s.Items.compile()
+	}
+
+	return nil
+}
+
+// compileAdditional will decode the additionalProperties keyword, it's a boolean or a schema
+func (s *jsonSchema) compileAdditional() error {
+	switch raw := bytes.TrimSpace(s.AdditionalProperties); {
+	case len(raw) == 0, bytes.Equal(raw, []byte("true")):
+		return nil
+	case bytes.Equal(raw, []byte("false")):
+		s.closed = true
+		return nil
+	}
+
+	s.additional = &jsonSchema{}
+
+	if err := json.Unmarshal(s.AdditionalProperties, s.additional); err != nil {
+		return fmt.Errorf("Invalid JSON schema: %v", err)
+	}
+
+	return s.additional.compile()
+}
+
+// validate will append a FieldError for every violation of the value at the given path
+func (s *jsonSchema) validate(path string, value interface{}, errs []*FieldError) []*FieldError {
+	violation := func(format string, args ...interface{}) {
+		p := path
+
+		if p == "" {
+			p = "$"
+		}
+
+		errs = append(errs, &FieldError{Path: p, Err: fmt.Errorf("%w: "+format, append([]interface{}{ErrContractViolation}, args...)...)})
+	}
+
+	typ := jsonType(value)
+
+	if types := s.types(); len(types) > 0 && !matchesType(typ, types) {
+		violation("expected %v, got %s", joinTypes(types), typ)
+		return errs
+	}
+
+	if len(s.Enum) > 0 && !s.inEnum(value) {
+		violation("%v is not one of the allowed values", value)
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := len([]rune(v))
+
+		if s.MinLength != nil && length < *s.MinLength {
+			violation("length %d is shorter than %d", length, *s.MinLength)
+		}
+
+		if s.MaxLength != nil && length > *s.MaxLength {
+			violation("length %d is longer than %d", length, *s.MaxLength)
+		}
+
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			violation("%q does not match %q", v, s.Pattern)
+		}
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			violation("%v is less than %v", v, *s.Minimum)
+		}
+
+		if s.Maximum != nil && v > *s.Maximum {
+			violation("%v is greater than %v", v, *s.Maximum)
+		}
+	case []interface{}:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			violation("%d items are fewer than %d", len(v), *s.MinItems)
+		}
+
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			violation("%d items are more than %d", len(v), *s.MaxItems)
+		}
+
+		if s.Items != nil {
+			for i, item := range v {
+				errs = s.Items.validate(path+"["+strconv.Itoa(i)+"]", item, errs)
+			}
+		}
+	case map[string]interface{}:
+		for _, key := range s.Required {
+			if _, ok := v[key]; !ok {
+				errs = append(errs, &FieldError{Path: joinPath(path, key), Err: fmt.Errorf("%w: required key is missing", ErrContractViolation)})
+			}
+		}
+
+		for key, item := range v {
+			property, ok := s.Properties[key]
+
+			if !ok {
+				if s.closed {
+					errs = append(errs, &FieldError{Path: joinPath(path, key), Err: fmt.Errorf("%w: additional key is not allowed", ErrContractViolation)})
+				}
+
+				property = s.additional
+			}
+
+			if property != nil {
+				errs = property.validate(joinPath(path, key), item, errs)
+			}
+		}
+	}
+
+	return errs
+}
+
+// types will return the allowed types of the schema, the type keyword is a string or a list of strings
+func (s *jsonSchema) types() []string {
+	switch t := s.Type.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		types := make([]string, 0, len(t))
+
+		for _, item := range t {
+			if name, ok := item.(string); ok {
+				types = append(types, name)
+			}
+		}
+
+		return types
+	}
+
+	return nil
+}
+
+// inEnum will check if the value is one of the enum values
+func (s *jsonSchema) inEnum(value interface{}) bool {
+	for _, allowed := range s.Enum {
+		if equalValues(allowed, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// jsonType will return the JSON Schema type of a decoded JSON value
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	}
+
+	return "object"
+}
+
+// matchesType will check if the JSON type is one of the allowed types, integers are numbers too
+func matchesType(typ string, types []string) bool {
+	for _, allowed := range types {
+		if allowed == typ || (allowed == "number" && typ == "integer") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// joinTypes will describe the allowed types
+func joinTypes(types []string) string {
+	if len(types) == 1 {
+		return types[0]
+	}
+
+	return fmt.Sprintf("one of %v", types)
+}
@@ -0,0 +1,121 @@
+package mantau
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const userContract = `{
+	"type": "object",
+	"required": ["id", "email", "tags"],
+	"additionalProperties": false,
+	"properties": {
+		"id": {"type": "integer", "minimum": 1},
+		"email": {"type": "string", "pattern": "@"},
+		"role": {"enum": ["admin", "member"]},
+		"address": {"type": ["object", "null"], "properties": {"code": {"type": "string", "maxLength": 5}}},
+		"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2}
+	}
+}`
+
+func TestValidateAgainst(t *testing.T) {
+	valid := Result{
+		"id":      1,
+		"email":   "john@example.com",
+		"role":    "admin",
+		"address": nil,
+		"tags":    []string{"a", "b"},
+	}
+
+	assert.NoError(t, valid.ValidateAgainst([]byte(userContract)), "Should not return any error")
+
+	invalid := Result{
+		"id":      0,
+		"email":   "john",
+		"role":    "owner",
+		"address": Result{"code": "1234567"},
+		"tags":    []interface{}{"a", 1, "c"},
+		"extra":   true,
+	}
+
+	err := invalid.ValidateAgainst([]byte(userContract))
+
+	assert.Error(t, err, "Should return an error")
+	assert.True(t, errors.Is(err, ErrContractViolation), "Should be a contract violation")
+
+	var multi *MultiError
+
+	assert.True(t, errors.As(err, &multi), "Should be a *MultiError")
+
+	paths := []string{}
+
+	for _, e := range multi.Errors {
+		paths = append(paths, e.Path)
+	}
+
+	assert.Equal(t, []string{"address.code", "email", "extra", "id", "role", "tags", "tags[1]"}, paths)
+}
+
+func TestValidateAgainstRoot(t *testing.T) {
+	err := Result{}.ValidateAgainst([]byte(`{"type": "array"}`))
+
+	assert.EqualError(t, err, "$: Contract violation: expected array, got object")
+
+	err = Result{}.ValidateAgainst([]byte(`{"type": `))
+
+	assert.Error(t, err, "Should return an error for an invalid schema")
+
+	err = Result{}.ValidateAgainst([]byte(`{"type": "object", "properties": {"id": {"oneOf": [{"type": "integer"}, {"type": "string"}]}}}`))
+
+	assert.EqualError(t, err, `Invalid JSON schema: the keyword "oneOf" is not supported`)
+
+	err = Result{}.ValidateAgainst([]byte(`{"type": "array", "items": {"$ref": "#/definitions/tag"}}`))
+
+	assert.EqualError(t, err, `Invalid JSON schema: the keyword "$ref" is not supported`)
+}
+
+func TestValidateAgainstUnsupportedKeywords(t *testing.T) {
+	keywords := []string{
+		`"not": {"type": "string"}`,
+		`"const": 1`,
+		`"exclusiveMinimum": 0`,
+		`"exclusiveMaximum": 10`,
+		`"multipleOf": 2`,
+		`"uniqueItems": true`,
+		`"patternProperties": {"^x": {"type": "string"}}`,
+		`"if": {"type": "string"}, "then": {"minLength": 1}, "else": {"minimum": 0}`,
+	}
+
+	for _, keyword := range keywords {
+		err := Result{}.ValidateAgainst([]byte(`{"type": "object", "properties": {"id": {` + keyword + `}}}`))
+
+		assert.Error(t, err, "Should return an error for %s", keyword)
+		assert.Contains(t, err.Error(), "is not supported", "Should describe the unsupported keyword")
+	}
+
+	err := Result{}.ValidateAgainst([]byte(`{"type": "array", "items": [{"type": "string"}]}`))
+
+	assert.EqualError(t, err, `Invalid JSON schema: the keyword "items" is only supported with a schema`)
+
+	err = Result{}.ValidateAgainst([]byte(`{"type": "object", "description": "A user", "title": "User"}`))
+
+	assert.NoError(t, err, "Annotations should be ignored")
+}
+
+func TestValidateAgainstAdditionalSchema(t *testing.T) {
+	schema := []byte(`{
+		"type": "object",
+		"properties": {"id": {"type": "integer"}},
+		"additionalProperties": {"type": "string"}
+	}`)
+
+	err := Result{"id": 1, "name": "John", "nickname": "Johnny"}.ValidateAgainst(schema)
+
+	assert.NoError(t, err, "Additional keys matching the schema should be valid")
+
+	err = Result{"id": 1, "age": 30}.ValidateAgainst(schema)
+
+	assert.EqualError(t, err, "age: Contract violation: expected string, got integer")
+}
@@ -0,0 +1,104 @@
+package mantau
+
+import (
+	"fmt"
+	"sync"
+)
+
+// LazyValue is a source value computed only when a schema field reads it, see Lazy
+type LazyValue struct {
+	once  sync.Once
+	fn    func() (interface{}, error)
+	value interface{}
+	err   error
+}
+
+// Lazy will create a source value computed the first time it's read by a schema field, e.g. a count from
+// another service. It's not computed when no field reads it, the field is gated by a flag or it's redacted.
+// The lazy values of an object read by the schema are computed concurrently
+func Lazy(fn func() (interface{}, error)) *LazyValue {
+	return &LazyValue{fn: fn}
+}
+
+// Resolve will compute the value once, it's safe for concurrent use. A panic of the function is returned
+// as the error of the value, lazy values are computed in their own goroutines where it couldn't be recovered
+func (l *LazyValue) Resolve() (interface{}, error) {
+	l.once.Do(func() {
+		defer func() {
+			if r := recover(); r != nil {
+				l.value, l.err = nil, fmt.Errorf("Lazy value panicked: %v", r)
+			}
+		}()
+
+		l.value, l.err = l.fn()
+	})
+
+	return l.value, l.err
+}
+
+// resolve will compute the value when it's a lazy value
+func resolve(value interface{}) (interface{}, error) {
+	if lazy, ok := value.(*LazyValue); ok && lazy != nil {
+		return lazy.Resolve()
+	}
+
+	return value, nil
+}
+
+// resolveLazy will concurrently compute the lazy values of an object read by the schema.
+// Their errors are returned when the fields are mapped
+func (m *mantau) resolveLazy(keys []string, values []interface{}, schema Schema) {
+	pending := []*LazyValue{}
+
+	for i, value := range values {
+		if lazy, ok := value.(*LazyValue); ok && lazy != nil && keys[i] != "" && m.selects(schema, keys[i]) {
+			pending = append(pending, lazy)
+		}
+	}
+
+	if len(pending) < 2 {
+		return
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(pending))
+
+	for _, lazy := range pending {
+		go func(lazy *LazyValue) {
+			defer wg.Done()
+			lazy.Resolve()
+		}(lazy)
+	}
+
+	wg.Wait()
+}
+
+// selects will check if a source key is read by a field of the schema that is not gated or redacted
+func (m *mantau) selects(schema Schema, key string) bool {
+	if schema.drops(key) {
+		return false
+	}
+
+	for name, field := range schema {
+		if field.isReserved() || m.gated(field) {
+			continue
+		}
+
+		if redact, _ := m.checkClearance(name, field); redact {
+			continue
+		}
+
+		switch {
+		case name == wildcardKey:
+			return true
+		case field.KeyPattern != nil:
+			if field.KeyPattern.MatchString(key) {
+				return true
+			}
+		case field.Key == key && m.readsKey(field):
+			return true
+		}
+	}
+
+	return false
+}
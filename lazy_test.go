@@ -0,0 +1,95 @@
+package mantau
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazy(t *testing.T) {
+	var calls int32
+
+	count := func(n int) *LazyValue {
+		return Lazy(func() (interface{}, error) {
+			atomic.AddInt32(&calls, 1)
+			return n, nil
+		})
+	}
+
+	src := map[string]interface{}{
+		"name":      "John",
+		"followers": count(10),
+		"following": count(20),
+		"posts":     count(30),
+	}
+
+	result, err := New().With(WithFlags(FlagFunc(func(ctx context.Context, flag string) bool {
+		return false
+	}))).Transform(src, Schema{
+		"name":      Field{Key: "name"},
+		"followers": Field{Key: "followers"},
+		"posts":     FlagGate("posts", Field{Key: "posts"}),
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John", "followers": 10}, result, "The result do not match")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "Only the selected lazy value should be computed")
+}
+
+func TestLazyConcurrent(t *testing.T) {
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+
+	wait := func(value int) *LazyValue {
+		return Lazy(func() (interface{}, error) {
+			started <- struct{}{}
+
+			select {
+			case <-release:
+				return value, nil
+			case <-time.After(time.Second):
+				return nil, errors.New("Not resolved concurrently")
+			}
+		})
+	}
+
+	go func() {
+		<-started
+		<-started
+		close(release)
+	}()
+
+	result, err := New().Transform(map[string]interface{}{"a": wait(1), "b": wait(2)}, Schema{
+		"a": Field{Key: "a"},
+		"b": Field{Key: "b"},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"a": 1, "b": 2}, result, "The result do not match")
+}
+
+func TestLazyError(t *testing.T) {
+	errCount := errors.New("Count unavailable")
+
+	_, err := New().Transform(map[string]interface{}{
+		"count": Lazy(func() (interface{}, error) { return nil, errCount }),
+	}, Schema{"count": Field{Key: "count"}})
+
+	assert.True(t, errors.Is(err, errCount), "Should return the lazy value error")
+	assert.EqualError(t, err, "count: Count unavailable")
+}
+
+func TestLazyPanic(t *testing.T) {
+	src := map[string]interface{}{
+		"count":  Lazy(func() (interface{}, error) { panic("connection lost") }),
+		"orders": Lazy(func() (interface{}, error) { return 3, nil }),
+	}
+
+	_, err := New().With(WithSortMapKeys(true)).Transform(src, Schema{"count": Field{Key: "count"}, "orders": Field{Key: "orders"}})
+
+	assert.EqualError(t, err, "count: Lazy value panicked: connection lost", "The panic should be the error of the field")
+}
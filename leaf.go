@@ -0,0 +1,55 @@
+package mantau
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+)
+
+var (
+	ipType           = reflect.TypeOf(net.IP{})
+	hardwareAddrType = reflect.TypeOf(net.HardwareAddr{})
+	urlType          = reflect.TypeOf(url.URL{})
+)
+
+// isLeafType will check if the type is emitted as a string rather than transformed,
+// these are net.IP, net.HardwareAddr, url.URL and 16 bytes UUID types like uuid.UUID
+func isLeafType(t reflect.Type) bool {
+	switch t {
+	case ipType, hardwareAddrType, urlType:
+		return true
+	}
+
+	return t.Kind() == reflect.Array &&
+		t.Len() == 16 &&
+		t.Elem().Kind() == reflect.Uint8 &&
+		t.Name() == "UUID" &&
+		t.Implements(reflect.TypeOf((*fmt.Stringer)(nil)).Elem())
+}
+
+// leafValue will return the canonical string form of a leaf value,
+// a nil or empty net.IP or net.HardwareAddr is nil like other nil values
+func (m *mantau) leafValue(src interface{}) (interface{}, bool) {
+	if m.opt.RawLeafTypes {
+		return nil, false
+	}
+
+	value := m.getValue(src)
+
+	if !isLeafType(value.Type()) {
+		return nil, false
+	}
+
+	if (value.Type() == ipType || value.Type() == hardwareAddrType) && value.Len() == 0 {
+		return nil, true
+	}
+
+	if value.Type() == urlType {
+		u := value.Interface().(url.URL)
+
+		return u.String(), true
+	}
+
+	return value.Interface().(fmt.Stringer).String(), true
+}
@@ -0,0 +1,76 @@
+package mantau
+
+import (
+	"encoding/hex"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// UUID mimics uuid.UUID without depending on it
+type UUID [16]byte
+
+func (u UUID) String() string {
+	return hex.EncodeToString(u[:4]) + "-" + hex.EncodeToString(u[4:6]) + "-" + hex.EncodeToString(u[6:8]) +
+		"-" + hex.EncodeToString(u[8:10]) + "-" + hex.EncodeToString(u[10:])
+}
+
+type Device struct {
+	ID       UUID             `json:"id"`
+	IP       net.IP           `json:"ip"`
+	MAC      net.HardwareAddr `json:"mac"`
+	Endpoint url.URL          `json:"endpoint"`
+	Callback *url.URL         `json:"callback"`
+}
+
+func TestLeafTypes(t *testing.T) {
+	mac, _ := net.ParseMAC("00:00:5e:00:53:01")
+	endpoint, _ := url.Parse("https://example.com/devices?id=1")
+
+	device := Device{
+		ID:       UUID{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00},
+		IP:       net.ParseIP("192.168.1.1"),
+		MAC:      mac,
+		Endpoint: *endpoint,
+		Callback: endpoint,
+	}
+
+	schema := Schema{
+		"id":       Field{Key: "id"},
+		"ip":       Field{Key: "ip"},
+		"mac":      Field{Key: "mac"},
+		"endpoint": Field{Key: "endpoint"},
+		"callback": Field{Key: "callback"},
+	}
+
+	result, err := New().Transform(device, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"id":       "123e4567-e89b-12d3-a456-426614174000",
+		"ip":       "192.168.1.1",
+		"mac":      "00:00:5e:00:53:01",
+		"endpoint": "https://example.com/devices?id=1",
+		"callback": "https://example.com/devices?id=1",
+	}, result, "The result do not match")
+
+	result, err = New().Transform(Device{}, Schema{"ip": Field{Key: "ip"}, "mac": Field{Key: "mac"}})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{}, result, "Nil addresses should be omitted like other nil values")
+
+	result, err = New().With(WithKeepNil(true)).Transform(Device{}, Schema{"ip": Field{Key: "ip"}, "mac": Field{Key: "mac"}})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"ip": nil, "mac": nil}, result, "Nil addresses should be kept as nil")
+
+	m := New()
+	m.SetOpt(&Options{Hook: "json", RawLeafTypes: true})
+
+	result, err = m.Transform(device, Schema{"id": Field{Key: "id"}})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.NotEqual(t, Result{"id": "123e4567-e89b-12d3-a456-426614174000"}, result, "Leaf types should not be converted when disabled")
+}
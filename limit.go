@@ -0,0 +1,160 @@
+package mantau
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// truncatedKey is added to an object when fields were omitted with LimitTruncate
+const truncatedKey = "_truncated"
+
+// checkSize will add the estimated size of a mapped value to the size of the result. When the size exceeds
+// Options.MaxResultBytes it returns an ErrResultTooLarge error, or false with LimitTruncate
+// to omit the value and mark the object as truncated
+func (m *mantau) checkSize(result Result, v Value) (bool, error) {
+	if m.state == nil || m.opt.MaxResultBytes <= 0 || (v.IsEmpty() && v.flatten == nil) {
+		return true, nil
+	}
+
+	// The key, it's quotes, the colon and the separator
+	size := len(v.Key) + 4
+
+	switch v.Value.(type) {
+	case Result, []Result:
+		// Nested objects are counted and truncated by their own fields
+		m.state.size += size
+		return true, nil
+	}
+
+	if m.state.truncated {
+		result[truncatedKey] = true
+		return false, nil
+	}
+
+	size += estimateSize(v.Value)
+	m.state.size += size
+
+	if m.state.size <= m.opt.MaxResultBytes {
+		return true, nil
+	}
+
+	if m.opt.ResultLimit == LimitTruncate {
+		m.state.truncated = true
+		result[truncatedKey] = true
+
+		return false, nil
+	}
+
+	return false, fmt.Errorf("%w: more than %d bytes", ErrResultTooLarge, m.opt.MaxResultBytes)
+}
+
+// reuseSize will add the estimated size of a result reused from the cache or the memoization to the size
+// of the result. It returns false when the result doesn't fit, the value must then be transformed again
+// so the limit truncates it's fields or returns the error
+func (m *mantau) reuseSize(value interface{}) bool {
+	if m.state == nil || m.opt.MaxResultBytes <= 0 {
+		return true
+	}
+
+	if m.state.truncated {
+		return false
+	}
+
+	size := resultSize(value)
+
+	if m.state.size+size > m.opt.MaxResultBytes {
+		return false
+	}
+
+	m.state.size += size
+
+	return true
+}
+
+// resultSize will estimate the size of a transformed value the way checkSize counts it's fields
+func resultSize(value interface{}) int {
+	switch v := value.(type) {
+	case Result:
+		size := 0
+
+		for key, field := range v {
+			size += len(key) + 4 + resultSize(field)
+		}
+
+		return size
+	case []Result:
+		size := 0
+
+		for _, item := range v {
+			size += resultSize(item)
+		}
+
+		return size
+	}
+
+	return estimateSize(value)
+}
+
+// isTruncated will check if the result exceeded Options.MaxResultBytes with LimitTruncate,
+// the remaining elements of collections are then omitted
+func (m *mantau) isTruncated() bool {
+	return m.state != nil && m.state.truncated
+}
+
+// estimateSize will estimate the size of the JSON encoded value
+func estimateSize(value interface{}) int {
+	if value == nil {
+		return 4
+	}
+
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.String:
+		return len(v.String()) + 2
+	case reflect.Bool:
+		return len(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return len(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return len(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		return len(strconv.FormatFloat(v.Float(), 'g', -1, 64))
+	case reflect.Slice, reflect.Array:
+		// The brackets and the commas
+		size := 2 + separators(v.Len())
+
+		for i := 0; i < v.Len(); i++ {
+			size += estimateSize(v.Index(i).Interface())
+		}
+
+		return size
+	case reflect.Map:
+		// The braces, the commas and the quotes and colon of every key
+		size := 2 + separators(v.Len())
+
+		for _, key := range v.MapKeys() {
+			size += len(fmt.Sprint(key.Interface())) + 3 + estimateSize(v.MapIndex(key).Interface())
+		}
+
+		return size
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return 4
+		}
+
+		return estimateSize(v.Elem().Interface())
+	}
+
+	return len(fmt.Sprint(value))
+}
+
+// separators will return the number of commas between n elements
+func separators(n int) int {
+	if n == 0 {
+		return 0
+	}
+
+	return n - 1
+}
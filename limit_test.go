@@ -0,0 +1,104 @@
+package mantau
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateSize(t *testing.T) {
+	values := []interface{}{
+		"hello", 12345, -1, uint8(7), 1.5, true, nil, []string{"a", "b"},
+		map[string]interface{}{"a": 1}, []int{},
+	}
+
+	for _, v := range values {
+		encoded, _ := json.Marshal(v)
+
+		assert.Equal(t, len(encoded), estimateSize(v), "The estimated size of %#v do not match", v)
+	}
+}
+
+func TestMaxResultBytes(t *testing.T) {
+	permissions := make([]Permission, 100)
+
+	for i := range permissions {
+		permissions[i] = Permission{"Permission", i}
+	}
+
+	src := User{Name: "John doe", Permissions: permissions}
+	schema := Schema{
+		"permissions": Field{Key: "permissions", Value: Schema{
+			"name": Field{Key: "permission_name"},
+		}},
+	}
+
+	_, err := New().With(WithMaxResultBytes(200, LimitError)).Transform(src, schema)
+
+	assert.True(t, errors.Is(err, ErrResultTooLarge), "Should return a result too large error")
+
+	_, err = New().With(WithMaxResultBytes(200, LimitError), WithCollectErrors(true)).Transform(src, schema)
+
+	assert.True(t, errors.Is(err, ErrResultTooLarge), "The error should not be collected")
+
+	result, err := New().With(WithMaxResultBytes(200, LimitTruncate)).Transform(src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+
+	items := result.(Result)["permissions"].([]Result)
+
+	assert.True(t, len(items) < 100, "The result should be truncated")
+	assert.Equal(t, true, items[len(items)-1][truncatedKey], "The truncated objects should be marked")
+
+	result, err = New().With(WithMaxResultBytes(10000, LimitError)).Transform(src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Len(t, result.(Result)["permissions"], 100, "The result should not be truncated within the limit")
+}
+
+func TestMaxResultBytesReused(t *testing.T) {
+	users := make([]CachedUser, 50)
+
+	for i := range users {
+		users[i] = CachedUser{ID: 1, Name: "John doe"}
+	}
+
+	schema := Schema{"id": Field{Key: "id"}, "name": Field{Key: "name"}}
+
+	_, err := New().With(WithCache(mapCache{}), WithMaxResultBytes(200, LimitError)).Transform(users, schema)
+
+	assert.True(t, errors.Is(err, ErrResultTooLarge), "The cached objects should be counted")
+
+	cache := mapCache{}
+	result, err := New().With(WithCache(cache), WithMaxResultBytes(200, LimitTruncate)).Transform(users, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+
+	items := result.([]Result)
+
+	assert.True(t, len(items) < 50, "The result should be truncated")
+	assert.Equal(t, true, items[len(items)-1][truncatedKey], "The truncated objects should be marked")
+
+	for _, cached := range cache {
+		assert.Nil(t, cached[truncatedKey], "The truncated objects should not be cached")
+	}
+
+	author := &Author{FirstName: "John", LastName: "Doe"}
+	books := make([]Book, 50)
+
+	for i := range books {
+		books[i] = Book{Title: "Book", Author: author}
+	}
+
+	schema = Schema{"author": Field{Key: "author", Value: Schema{"name": Field{Key: "first_name"}}}}
+
+	_, err = New().With(WithMemoize(true), WithMaxResultBytes(800, LimitError)).Transform(books, schema)
+
+	assert.True(t, errors.Is(err, ErrResultTooLarge), "The memoized objects should be counted")
+
+	_, err = New().With(WithMemoize(true), WithMaxResultBytes(100000, LimitError)).Transform(books, schema)
+
+	assert.NoError(t, err, "Should not return any error within the limit")
+}
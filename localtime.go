@@ -0,0 +1,218 @@
+package mantau
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeFormatter formats dates for a locale, Options.TimeFormatter can be set to plug in a full CLDR implementation
+type TimeFormatter interface {
+	// FormatDate will format the date of the time in the locale e.g. "es" or "es-MX", with the given style.
+	// An error matching ErrUnknownLocale falls back to Options.Locale and then "en"
+	FormatDate(t time.Time, locale string, style DateStyle) (string, error)
+}
+
+// localeKey is the context key of the locale set by ContextWithLocale
+type localeKey struct{}
+
+// ContextWithLocale will return a copy of the context carrying the locale of the caller e.g. "es",
+// a locale passed to TransformCtx takes precedence over Options.Locale
+func ContextWithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// locales will return the locales tried to format a date, the locale of the context, the options and "en".
+// The locale of the context usually comes from the caller, an unknown locale falls back to the next one
+func (m *mantau) locales() []string {
+	locales := make([]string, 0, 3)
+
+	for _, locale := range []string{m.contextLocale(), m.opt.Locale, "en"} {
+		if locale != "" && (len(locales) == 0 || locales[len(locales)-1] != locale) {
+			locales = append(locales, locale)
+		}
+	}
+
+	return locales
+}
+
+// contextLocale will return the locale set by ContextWithLocale, if any
+func (m *mantau) contextLocale() string {
+	locale, _ := m.context().Value(localeKey{}).(string)
+
+	return locale
+}
+
+// formatDate will format a time.Time value as a localized date with the formatter of the options
+func (m *mantau) formatDate(value interface{}, style DateStyle) (interface{}, error) {
+	var t time.Time
+
+	switch v := value.(type) {
+	case time.Time:
+		t = v
+	case *time.Time:
+		t = *v
+	default:
+		return nil, fmt.Errorf("Cannot format %T as a localized date", value)
+	}
+
+	formatter := m.opt.TimeFormatter
+
+	if formatter == nil {
+		formatter = cldrFormatter{}
+	}
+
+	var err error
+
+	for _, locale := range m.locales() {
+		var formatted string
+
+		if formatted, err = formatter.FormatDate(t, locale, style); !errors.Is(err, ErrUnknownLocale) {
+			return formatted, err
+		}
+	}
+
+	return nil, err
+}
+
+// dateLocale is the data of a locale known by the built-in formatter
+type dateLocale struct {
+	months      []string
+	shortMonths []string
+	days        []string
+
+	// patterns are the CLDR date patterns of every style
+	patterns map[DateStyle]string
+}
+
+// dateLocales are the locales known by the built-in formatter, by their language
+var dateLocales = map[string]dateLocale{
+	"en": {
+		months:      []string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		shortMonths: []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		days:        []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		patterns:    map[DateStyle]string{DateShort: "M/d/yy", DateMedium: "MMM d, y", DateLong: "MMMM d, y", DateFull: "EEEE, MMMM d, y"},
+	},
+	"es": {
+		months:      []string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		shortMonths: []string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		days:        []string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		patterns:    map[DateStyle]string{DateShort: "d/M/yy", DateMedium: "d MMM y", DateLong: "d 'de' MMMM 'de' y", DateFull: "EEEE, d 'de' MMMM 'de' y"},
+	},
+	"fr": {
+		months:      []string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		shortMonths: []string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		days:        []string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		patterns:    map[DateStyle]string{DateShort: "dd/MM/y", DateMedium: "d MMM y", DateLong: "d MMMM y", DateFull: "EEEE d MMMM y"},
+	},
+	"de": {
+		months:      []string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		shortMonths: []string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		days:        []string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		patterns:    map[DateStyle]string{DateShort: "dd.MM.yy", DateMedium: "dd.MM.y", DateLong: "d. MMMM y", DateFull: "EEEE, d. MMMM y"},
+	},
+	"id": {
+		months:      []string{"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"},
+		shortMonths: []string{"Jan", "Feb", "Mar", "Apr", "Mei", "Jun", "Jul", "Agu", "Sep", "Okt", "Nov", "Des"},
+		days:        []string{"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu"},
+		patterns:    map[DateStyle]string{DateShort: "dd/MM/yy", DateMedium: "d MMM y", DateLong: "d MMMM y", DateFull: "EEEE, dd MMMM y"},
+	},
+}
+
+// cldrFormatter is the built-in TimeFormatter, it knows the CLDR date patterns of a few locales.
+// A regional locale e.g. "es-MX" uses the patterns of it's language
+type cldrFormatter struct{}
+
+// FormatDate will format the date with the CLDR pattern of the locale and style
+func (cldrFormatter) FormatDate(t time.Time, locale string, style DateStyle) (string, error) {
+	language := strings.ToLower(locale)
+
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+
+	data, ok := dateLocales[language]
+
+	if !ok {
+		return "", fmt.Errorf("%w: cannot format dates in the locale %q", ErrUnknownLocale, locale)
+	}
+
+	pattern, ok := data.patterns[style]
+
+	if !ok {
+		return "", fmt.Errorf("Unknown date style %q", style)
+	}
+
+	return data.format(t, pattern), nil
+}
+
+// format will format the time with a CLDR date pattern, the letters d, M, y and E are fields
+// and the text between single quotes is written as it is
+func (l dateLocale) format(t time.Time, pattern string) string {
+	b := strings.Builder{}
+	runes := []rune(pattern)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		if r == '\'' {
+			end := i + 1
+
+			for end < len(runes) && runes[end] != '\'' {
+				end++
+			}
+
+			b.WriteString(string(runes[i+1 : end]))
+			i = end + 1
+			continue
+		}
+
+		n := 1
+
+		for i+n < len(runes) && runes[i+n] == r {
+			n++
+		}
+
+		switch r {
+		case 'd':
+			b.WriteString(padded(t.Day(), n))
+		case 'M':
+			switch {
+			case n >= 4:
+				b.WriteString(l.months[t.Month()-1])
+			case n == 3:
+				b.WriteString(l.shortMonths[t.Month()-1])
+			default:
+				b.WriteString(padded(int(t.Month()), n))
+			}
+		case 'y':
+			if n == 2 {
+				b.WriteString(padded(t.Year()%100, 2))
+			} else {
+				b.WriteString(strconv.Itoa(t.Year()))
+			}
+		case 'E':
+			b.WriteString(l.days[t.Weekday()])
+		default:
+			b.WriteString(strings.Repeat(string(r), n))
+		}
+
+		i += n
+	}
+
+	return b.String()
+}
+
+// padded will format the number with at least the given number of digits
+func padded(n, digits int) string {
+	s := strconv.Itoa(n)
+
+	for len(s) < digits {
+		s = "0" + s
+	}
+
+	return s
+}
@@ -0,0 +1,104 @@
+package mantau
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Event struct {
+	Name     string     `json:"name"`
+	StartsAt time.Time  `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
+
+func TestLocalizedDate(t *testing.T) {
+	m := New()
+	at := time.Date(2019, 12, 13, 20, 0, 0, 0, time.UTC)
+	event := Event{Name: "Launch", StartsAt: at, EndsAt: &at}
+
+	schema := Schema{
+		"starts_at": Field{Key: "starts_at", Date: DateLong},
+		"ends_at":   Field{Key: "ends_at", Date: DateFull},
+	}
+
+	result, err := m.Transform(event, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"starts_at": "December 13, 2019", "ends_at": "Friday, December 13, 2019"}, result)
+
+	result, err = m.With(WithLocale("es")).Transform(event, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"starts_at": "13 de diciembre de 2019", "ends_at": "viernes, 13 de diciembre de 2019"}, result)
+
+	result, err = m.With(WithLocale("es")).TransformCtx(ContextWithLocale(context.Background(), "de-AT"), event, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"starts_at": "13. Dezember 2019", "ends_at": "Freitag, 13. Dezember 2019"}, result, "The locale of the context should take precedence")
+
+	result, err = m.With(WithLocale("es")).TransformCtx(ContextWithLocale(context.Background(), "ja"), event, schema)
+
+	assert.NoError(t, err, "An unknown locale should not fail the transformation")
+	assert.Equal(t, Result{"starts_at": "13 de diciembre de 2019", "ends_at": "viernes, 13 de diciembre de 2019"}, result, "An unknown locale should fall back to the locale of the options")
+
+	result, err = m.With(WithLocale("ja")).Transform(event, Schema{"starts_at": Field{Key: "starts_at", Date: DateLong}})
+
+	assert.NoError(t, err, "An unknown locale should not fail the transformation")
+	assert.Equal(t, Result{"starts_at": "December 13, 2019"}, result, "An unknown locale should fall back to en")
+
+	_, err = cldrFormatter{}.FormatDate(at, "ja", DateLong)
+
+	assert.True(t, errors.Is(err, ErrUnknownLocale), "The error should match ErrUnknownLocale")
+
+	_, err = m.Transform(event, Schema{"name": Field{Key: "name", Date: DateLong}})
+
+	assert.EqualError(t, err, "name: Cannot format string as a localized date")
+}
+
+func TestDateStyles(t *testing.T) {
+	at := time.Date(2019, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		locale string
+		style  DateStyle
+		want   string
+	}{
+		{"en", DateShort, "3/5/19"},
+		{"en", DateMedium, "Mar 5, 2019"},
+		{"es", DateMedium, "5 mar 2019"},
+		{"fr", DateShort, "05/03/2019"},
+		{"fr", DateLong, "5 mars 2019"},
+		{"de", DateMedium, "05.03.2019"},
+		{"id", DateFull, "Selasa, 05 Maret 2019"},
+	}
+
+	for _, c := range cases {
+		formatted, err := cldrFormatter{}.FormatDate(at, c.locale, c.style)
+
+		assert.NoError(t, err, "Should not return any error")
+		assert.Equal(t, c.want, formatted, fmt.Sprintf("The %s date of %q do not match", c.style, c.locale))
+	}
+}
+
+type upperFormatter struct{}
+
+func (upperFormatter) FormatDate(t time.Time, locale string, style DateStyle) (string, error) {
+	return fmt.Sprintf("%s:%s:%s", locale, style, t.Format("2006-01-02")), nil
+}
+
+func TestTimeFormatter(t *testing.T) {
+	m := New()
+	m.SetOpt(&Options{Hook: "mantau", Locale: "pt-BR", TimeFormatter: upperFormatter{}})
+
+	result, err := m.Transform(struct {
+		At time.Time `mantau:"at,date=short"`
+	}{time.Date(2019, 12, 13, 0, 0, 0, 0, time.UTC)}, Schema{"at": Field{Key: "at"}})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"at": "pt-BR:short:2019-12-13"}, result)
+}
@@ -1,15 +1,26 @@
 package mantau
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"reflect"
+	"regexp"
+	"sort"
+	"sync"
 	"time"
 )
 
 type (
 	// Mantau type
 	mantau struct {
+		// mu guards opt, every transformation reads the options once when it starts
+		mu  *sync.RWMutex
 		opt *Options
+
+		// state is only set on the copy of an instance used for a single transformation
+		state *state
 	}
 
 	// Mantau options
@@ -17,6 +28,144 @@ type (
 		// Hook with determine how mantau take individual field and transform it
 		// Based on the given schema
 		Hook string
+
+		// NamespaceSeparator enables namespaced keys for map sources e.g. rows of a SQL join
+		// with prefixed columns like "u_name". With "_" as the separator, Field.Key "u.name" matches
+		// the "u_name" key, and Field.Key "u" with a nested schema groups every "u_" key into an object
+		NamespaceSeparator string
+
+		// LargeInts determines how integers outside of the range JavaScript can represent
+		// exactly (above 2^53 - 1) are emitted, use LargeIntString to emit them as strings
+		LargeInts LargeIntPolicy
+
+		// EmptyCollections determines how a field with an empty slice or array is emitted
+		EmptyCollections EmptyPolicy
+
+		// NonFinite determines how NaN and infinite floats are emitted, encoding/json cannot encode them
+		NonFinite NonFinitePolicy
+
+		// NilNested determines how a nil object of a nested schema, or a nil object in the middle
+		// of a dotted key e.g. "address.code", is emitted
+		NilNested NilPolicy
+
+		// RawLeafTypes disables emitting net.IP, net.HardwareAddr, url.URL and uuid.UUID values
+		// as their canonical string form
+		RawLeafTypes bool
+
+		// CollectErrors will continue the transformation when a field fails
+		// and return every field error at once as a *MultiError
+		CollectErrors bool
+
+		// MaxErrors stops a transformation collecting errors once there are more errors than the limit,
+		// the *MultiError then has Aborted set. When it's zero, every error is collected
+		MaxErrors int
+
+		// MaxDepth limits how deep nested values are transformed, every nested field and collection element is a level.
+		// It keeps deeply nested or cyclic data from using unbounded memory. The levels are split across goroutine
+		// stacks, so even a large limit cannot exhaust the stack of a goroutine.
+		// When it's zero, DefaultMaxDepth is used
+		MaxDepth int
+
+		// MaxResultBytes limits the estimated size of the JSON encoded result, protecting against
+		// unbounded nested expansions. When it's zero, the size is not limited
+		MaxResultBytes int
+
+		// ResultLimit determines what happens when a result exceeds MaxResultBytes
+		ResultLimit LimitPolicy
+
+		// Mismatch determines what happens when a field has a nested schema but the source value
+		// is a primitive or vice versa. By default an error is returned
+		Mismatch MismatchPolicy
+
+		// Flags decides which fields created with FlagGate are emitted
+		Flags FlagProvider
+
+		// Cache stores the transformed objects of source values implementing CacheKeyer, by their key,
+		// the hash of the schema and the options changing the output like Clearance or KeepNil. Cached objects must not
+		// depend on the feature flags, the Naming strategy or the TimeFormatter. Objects transformed with schemas calling
+		// callbacks like Field.Transform or injecting values like Const are not cached, neither is anything with a SecretScanner
+		Cache Cache
+
+		// Clearance is the most sensitive classification of the fields the caller may see.
+		// When it's empty, classifications are not enforced
+		Clearance Classification
+
+		// Enforcement determines what happens with a field classified above the clearance
+		Enforcement EnforcementPolicy
+
+		// DefaultCountryCode is the calling code of national phone numbers normalized with NormalizePhone e.g. "62"
+		DefaultCountryCode string
+
+		// OnDeprecated is called with the context of the transformation every time a deprecated field is emitted
+		OnDeprecated func(ctx context.Context, d Deprecation)
+
+		// OnError is called with the detailed error of an error body shaped by Respond, the body itself only
+		// has a generic message. The error is written to the standard logger when it's not set
+		OnError func(ctx context.Context, err error)
+
+		// TimeKeyLayout is the layout of time.Time map keys, defaults to time.RFC3339
+		TimeKeyLayout string
+
+		// Naming derives the key of struct fields without a tag from their Go field name e.g. SnakeCase.
+		// When it's nil, a struct field without a tag returns an error
+		Naming NamingStrategy
+
+		// Memoize will transform a pointer referenced from several places of the source once
+		// per transformation and reuse the result, e.g. an author shared by several books
+		Memoize bool
+
+		// VerifySource will hash the source before and after the transformation and return an ErrSourceMutated
+		// error when it changed, e.g. when a Field.Transform callback modifies the input. It's meant for tests
+		VerifySource bool
+
+		// SharedSchemaCache will cache the schema fields matching the fields of every struct type by the hash of the schema,
+		// in a cache of DefaultCompiledCacheSize schemas shared by every instance. Instances transforming the same types
+		// with equal schemas, e.g. the schemas built for every tenant, match them once. The schema is hashed once per
+		// transformation. It's not used with a Naming strategy
+		SharedSchemaCache bool
+
+		// KeepNil will emit the keys of nil source values as nil instead of omitting them,
+		// the keys of a Field.OmitZero field are still omitted
+		KeepNil bool
+
+		// KeepZero will transform a pointer to a zero value e.g. an empty struct instead of omitting it
+		KeepZero bool
+
+		// SortMapKeys will transform the keys of map sources in sorted order instead of the random order of maps,
+		// so callbacks like Field.Transform are called in the same order on every run
+		SortMapKeys bool
+
+		// Unsupported is the behavior when a source value is a func or a channel, which cannot be transformed
+		Unsupported UnsupportedPolicy
+
+		// Locale is the locale of the dates formatted with Field.Date e.g. "es", defaults to "en".
+		// A locale passed to TransformCtx with ContextWithLocale takes precedence
+		Locale string
+
+		// TimeFormatter formats the dates of Field.Date, the built-in formatter knows the "en", "es", "fr",
+		// "de" and "id" locales
+		TimeFormatter TimeFormatter
+
+		// Registry stores the schemas and the tenant overlays used by TransformTenant
+		Registry *Registry
+
+		// ValueCache stores the Transform results of the fields created with Field.Cache. When it's nil,
+		// an in-memory cache of DefaultValueCacheSize values shared by every instance is used
+		ValueCache ValueCache
+
+		// SecretScanner inspects every emitted string for secrets like API keys or tokens, as a safety net
+		// against leaking credentials. Every value and key is scanned once, the objects changed by a schema
+		// post-processor are scanned again. Results are never read from Options.Cache with a scanner.
+		// When it's nil, values are not scanned
+		SecretScanner SecretScanner
+
+		// Secrets determines what happens when the SecretScanner finds a secret
+		Secrets SecretPolicy
+
+		// Collisions determines what happens when a field produces an output key that is already in the result,
+		// e.g. a wildcard key named like a renamed field or two KeyPattern fields expanding into the same key.
+		// By default a *DuplicateKeyError is returned
+		Collisions CollisionPolicy
 	}
 )
 
@@ -29,8 +178,139 @@ type (
 		// The result mapped key
 		Key string
 
-		// Value could be nil or a schema
+		// Compute will emit a value derived from the whole source object e.g. a full name built from
+		// the first and last names. It's only called when Key is empty
+		Compute func(src interface{}) (interface{}, error)
+
+		// Value could be nil, a schema, a func(parent interface{}) Schema
+		// that chooses the nested schema based on the source value containing the field
+		// or a map[string]Schema choosing the schema for every key of a map source
 		Value interface{}
+
+		// As will coerce the transformed value into "string", "int", "float" or "bool"
+		As string
+
+		// OmitZero will omit the key when the transformed value is a zero value
+		OmitZero bool
+
+		// Required will return a *RequiredFieldError when the source value is missing or nil, instead of omitting the key
+		Required bool
+
+		// Classification is the sensitivity of the field, it's enforced with Options.Clearance
+		Classification Classification
+
+		// Deprecated is the deprecation message of the field, emitting the field is reported
+		// to Options.OnDeprecated and by TransformDeprecations
+		Deprecated string
+
+		// Transform will change the transformed value e.g. format a price, before it's normalized, coerced or masked.
+		// It's not called for nil values
+		Transform func(value interface{}) (interface{}, error)
+
+		// Normalize will normalize the transformed value, before it's coerced or masked
+		Normalize Normalizer
+
+		// Convert will convert a numeric value from a unit into another e.g. bytes into MB, see Convert
+		Convert *Conversion
+
+		// Date will format a time.Time value as a localized date e.g. "13 de diciembre de 2019"
+		// with DateLong in the "es" locale, see Options.Locale
+		Date DateStyle
+
+		// Round will round a float value to a number of decimal places before it's coerced, see Round and RoundEven
+		Round *Rounding
+
+		// Mask will hide the characters of the transformed value, could be "all", "firstN" or "lastN" e.g. "last4"
+		Mask string
+
+		// LargeInts overrides Options.LargeInts for this field
+		LargeInts LargeIntPolicy
+
+		// EmptyCollections overrides Options.EmptyCollections for this field
+		EmptyCollections EmptyPolicy
+
+		// NonFinite overrides Options.NonFinite for this field
+		NonFinite NonFinitePolicy
+
+		// NilNested overrides Options.NilNested for this field
+		NilNested NilPolicy
+
+		// Pick will emit a single element of a collection instead of the whole collection, see First and Last
+		Pick Position
+
+		// Pluck will reduce a collection to a flat list of a single attribute of it's elements,
+		// Value is used as the nested schema of the attribute
+		Pluck string
+
+		// KeyPattern matches every source key of a map matching the pattern instead of Key.
+		// The schema key is the template of the output key, it can use the capture groups e.g. "cpu_$1"
+		KeyPattern *regexp.Regexp
+
+		// IndexBy will emit a collection as a Result keyed by the given attribute of it's elements
+		IndexBy string
+
+		// Include is the type of the nested entity, TransformIncluded replaces the entity by a reference
+		// and adds it to the included collection
+		Include string
+
+		// Flatten will merge the keys of the transformed nested object into the parent object.
+		// A key already in the parent returns an error, unless Collisions is set
+		Flatten bool
+
+		// Collisions determines what happens when a flattened key is already in the parent object,
+		// it overrides Options.Collisions for this field
+		Collisions CollisionPolicy
+
+		// MaxElements will truncate a collection to it's first MaxElements elements
+		MaxElements int
+
+		// Truncate will shorten a text to a maximum number of characters, see Truncate
+		Truncate *Truncation
+
+		// MarkTruncated will add a "<key>_truncated" key set to true when the collection or the text is truncated
+		MarkTruncated bool
+
+		// MapAs will emit a map source as a list of key and value objects (MapEntries),
+		// with it's keys and values swapped (MapInvert) or as a list of time and value objects (MapTimeSeries)
+		MapAs MapMode
+
+		// SampleRate will include the field for a fraction of the transformed objects e.g. 0.1 for 10%,
+		// zero includes it for every object. The objects are sampled by a hash of their SampleBy value,
+		// so the same object is always sampled the same way
+		SampleRate float64
+
+		// SampleBy is the source key of the object hashed to sample it, defaults to "id"
+		SampleBy string
+
+		// XML places the key in the output of an XMLEncoder, as an attribute or a wrapped list
+		XML XMLPlacement
+
+		// tombstone marks the field to be removed when the schema is used as an override patch
+		tombstone bool
+
+		// finalize is the schema post-processor stored under the finalizeKey entry
+		finalize func(Result) (Result, error)
+
+		// inject resolves the value of a Const or FromContext field
+		inject func(context.Context) (interface{}, bool)
+
+		// flag is the feature flag of a FlagGate field
+		flag string
+
+		// page is the page of the collection transformed with TransformPage
+		page *pageWindow
+
+		// cache is the cache declaration of a field created with Field.Cache
+		cache *fieldCache
+
+		// pipe are the ops of a field created with Field.Pipe
+		pipe []ValueOp
+
+		// money is the currency key of a Money field
+		money string
+
+		// drop marks the source key of the schema key to be dropped, see Drop
+		drop bool
 	}
 
 	// A value will store the schema field name and corresponding value after it's being transformed
@@ -40,6 +320,34 @@ type (
 
 		// Value will store the transformed value
 		Value interface{}
+
+		// extra stores additional keys emitted by the field next to it's own key
+		extra Result
+
+		// flatten stores the transformed object of a Field.Flatten field
+		flatten *flattened
+
+		// keepNil marks a nil value kept in the result with Options.KeepNil
+		keepNil bool
+	}
+
+	// XMLPlacement is how a key is written by an XMLEncoder, by default it's a child element named after the key
+	XMLPlacement struct {
+		// Name overrides the element or attribute name
+		Name string
+
+		// Attr will write the value as an attribute of the parent element
+		Attr bool
+
+		// Item will wrap the elements of a collection into a single element, every element is named Item
+		// e.g. <tags><tag>a</tag><tag>b</tag></tags>. Without it the element is repeated for every element
+		Item string
+	}
+
+	// entry is a single key and value of a map source
+	entry struct {
+		key   string
+		value interface{}
 	}
 
 	// Result will store the final result of the data after it's being transformed
@@ -47,6 +355,57 @@ type (
 
 	// Kind is just a string type aliase for this package
 	Kind string
+
+	// LargeIntPolicy is how integers above 2^53 - 1 are emitted
+	LargeIntPolicy string
+
+	// EmptyPolicy is how an empty collection is emitted
+	EmptyPolicy string
+
+	// NonFinitePolicy is how NaN and infinite floats are emitted
+	NonFinitePolicy string
+
+	// NilPolicy is how a nil nested object is emitted
+	NilPolicy string
+
+	// Position is an element of a collection
+	Position string
+
+	// MapMode is the shape a map source is emitted as
+	MapMode string
+
+	// Normalizer is how a contact value is normalized
+	Normalizer string
+
+	// RoundingMode is how a float half way between two rounded values is rounded
+	RoundingMode string
+
+	// LimitPolicy is the behavior when a result exceeds Options.MaxResultBytes
+	LimitPolicy string
+
+	// CollisionPolicy is the behavior when a flattened key is already in the parent object
+	CollisionPolicy string
+
+	// Classification is the sensitivity of a field
+	Classification string
+
+	// EnforcementPolicy is the behavior when a field is classified above the clearance
+	EnforcementPolicy string
+
+	// MismatchPolicy is the behavior when a nested schema doesn't match the source value
+	MismatchPolicy string
+
+	// UnsupportedPolicy is the behavior when a source value is a func or a channel
+	UnsupportedPolicy string
+
+	// Outcome is what happened to a field traced with TransformTrace or Explain
+	Outcome string
+
+	// DateStyle is the length of a localized date
+	DateStyle string
+
+	// SecretPolicy is the behavior when an emitted value looks like a secret
+	SecretPolicy string
 )
 
 // Data kinds
@@ -60,37 +419,293 @@ var (
 	Nil     Kind = "nil"
 )
 
+// Mismatch policies
+var (
+	// MismatchError will return an ErrSchemaMismatch error, this is the default policy
+	MismatchError MismatchPolicy = "error"
+
+	// MismatchOmit will omit the key from the result
+	MismatchOmit MismatchPolicy = "omit"
+
+	// MismatchRaw will use the source value as it is
+	MismatchRaw MismatchPolicy = "raw"
+)
+
+// Unsupported value policies
+var (
+	// UnsupportedSkip will omit the key of a func or a channel, this is the default policy
+	UnsupportedSkip UnsupportedPolicy = "skip"
+
+	// UnsupportedError will return an ErrUnsupportedType error
+	UnsupportedError UnsupportedPolicy = "error"
+)
+
+// Explain outcomes
+var (
+	// OutcomeEmitted is a field emitted in the result
+	OutcomeEmitted Outcome = "emitted"
+
+	// OutcomeRedacted is a field emitted as Redacted because it's classified above the clearance
+	OutcomeRedacted Outcome = "redacted"
+
+	// OutcomeSampledOut is a field left out by Field.SampleRate
+	OutcomeSampledOut Outcome = "sampled_out"
+
+	// OutcomeOmitted is a field omitted because it's value is empty or doesn't match the nested schema
+	OutcomeOmitted Outcome = "omitted"
+
+	// OutcomeRaw is a field emitted as it is because it doesn't match the nested schema, see MismatchRaw
+	OutcomeRaw Outcome = "raw"
+)
+
+// Classifications, from the least to the most sensitive
+var (
+	ClassPublic Classification = "public"
+	ClassPII    Classification = "pii"
+	ClassSecret Classification = "secret"
+)
+
+// Enforcement policies
+var (
+	// EnforceError will return an ErrClassified error, this is the default policy
+	EnforceError EnforcementPolicy = "error"
+
+	// EnforceRedact will emit Redacted instead of the value
+	EnforceRedact EnforcementPolicy = "redact"
+)
+
+// Normalizers
+var (
+	// NormalizeEmail will trim and lower case an email
+	NormalizeEmail Normalizer = "email"
+
+	// NormalizePhone will format a phone number in E.164 e.g. "+15551234567", see Options.DefaultCountryCode
+	NormalizePhone Normalizer = "phone"
+)
+
+// Secret policies
+var (
+	// SecretError will return an ErrSecretDetected error, this is the default policy
+	SecretError SecretPolicy = "error"
+
+	// SecretRedact will emit Redacted instead of the value
+	SecretRedact SecretPolicy = "redact"
+)
+
+// Date styles
+var (
+	// DateShort is a numeric date e.g. "12/13/19"
+	DateShort DateStyle = "short"
+
+	// DateMedium is a date with an abbreviated month e.g. "Dec 13, 2019"
+	DateMedium DateStyle = "medium"
+
+	// DateLong is a date with the month name e.g. "December 13, 2019"
+	DateLong DateStyle = "long"
+
+	// DateFull is a date with the weekday and the month name e.g. "Friday, December 13, 2019"
+	DateFull DateStyle = "full"
+)
+
+// Rounding modes
+var (
+	// RoundHalfUp will round half away from zero e.g. 2.5 into 3, this is the default mode
+	RoundHalfUp RoundingMode = "half_up"
+
+	// RoundHalfEven will round half to the nearest even digit e.g. 2.5 into 2, also known as banker's rounding
+	RoundHalfEven RoundingMode = "half_even"
+)
+
+// Result size limit policies
+var (
+	// LimitError will return an ErrResultTooLarge error, this is the default policy
+	LimitError LimitPolicy = "error"
+
+	// LimitTruncate will omit the remaining fields and add a "_truncated" key to the objects missing fields
+	LimitTruncate LimitPolicy = "truncate"
+)
+
+// Output key collision policies
+var (
+	// CollisionError will return an error, this is the default policy
+	CollisionError CollisionPolicy = "error"
+
+	// CollisionOverride will replace the value already in the result, e.g. the parent value with the flattened value
+	CollisionOverride CollisionPolicy = "override"
+
+	// CollisionKeep will keep the value already in the result
+	CollisionKeep CollisionPolicy = "keep"
+)
+
+// Large integer policies
+var (
+	// LargeIntNumber will emit large integers as numbers, this is the default policy
+	LargeIntNumber LargeIntPolicy = "number"
+
+	// LargeIntString will emit large integers as strings so they survive JSON decoding in JavaScript
+	LargeIntString LargeIntPolicy = "string"
+)
+
+// Non-finite float policies
+var (
+	// NonFiniteKeep will emit NaN and infinite floats as they are, this is the default policy
+	NonFiniteKeep NonFinitePolicy = "keep"
+
+	// NonFiniteNil will emit NaN and infinite floats as nil e.g. null
+	NonFiniteNil NonFinitePolicy = "nil"
+
+	// NonFiniteString will emit NaN and infinite floats as "NaN", "+Inf" or "-Inf"
+	NonFiniteString NonFinitePolicy = "string"
+
+	// NonFiniteError will return an ErrNonFinite error
+	NonFiniteError NonFinitePolicy = "error"
+)
+
+// Nil nested object policies
+var (
+	// NilOmit will omit the key of a nil nested object, this is the default policy
+	NilOmit NilPolicy = "omit"
+
+	// NilNull will emit a nil nested object as null
+	NilNull NilPolicy = "null"
+)
+
+// Empty collection policies
+var (
+	// EmptyArray will emit an empty collection as it is e.g. [], this is the default policy
+	EmptyArray EmptyPolicy = "array"
+
+	// EmptyNil will emit an empty collection as a nil collection e.g. null
+	EmptyNil EmptyPolicy = "nil"
+
+	// EmptyOmit will omit the key of an empty collection
+	EmptyOmit EmptyPolicy = "omit"
+)
+
+// Collection positions
+var (
+	FirstElement Position = "first"
+	LastElement  Position = "last"
+)
+
+// Map modes
+var (
+	// MapEntries will emit a map as a list of {"key": key, "value": value} objects sorted by key
+	MapEntries MapMode = "entries"
+
+	// MapInvert will emit a map with it's keys and values swapped
+	MapInvert MapMode = "invert"
+
+	// MapTimeSeries will emit a map keyed by time.Time as a list of {"time": key, "value": value} objects
+	// in chronological order, the keys are formatted with Options.TimeKeyLayout
+	MapTimeSeries MapMode = "timeseries"
+)
+
+// DefaultMaxDepth is the maximum depth of nested values when Options.MaxDepth is not set
+const DefaultMaxDepth = 100
+
+var (
+	// ErrSchemaMismatch is returned when a nested schema doesn't match the source value
+	ErrSchemaMismatch = errors.New("Schema mismatch")
+
+	// ErrMaxDepth is returned when the source value is nested deeper than the maximum depth
+	ErrMaxDepth = errors.New("Maximum depth exceeded")
+
+	// ErrTooManyErrors is matched by a *MultiError when the transformation stopped after more than Options.MaxErrors errors
+	ErrTooManyErrors = errors.New("Too many errors")
+
+	// ErrResultTooLarge is returned when the estimated size of a result exceeds Options.MaxResultBytes
+	ErrResultTooLarge = errors.New("Result too large")
+
+	// ErrClassified is returned when a field is classified above the clearance
+	ErrClassified = errors.New("Classified field")
+
+	// ErrNonFinite is returned for NaN and infinite floats with NonFiniteError
+	ErrNonFinite = errors.New("Non-finite float")
+
+	// ErrCoercion is matched by a *CoercionError when a value cannot be coerced with Field.As
+	ErrCoercion = errors.New("Coercion failed")
+
+	// ErrContractViolation is returned by Result.ValidateAgainst when the result doesn't match the JSON schema
+	ErrContractViolation = errors.New("Contract violation")
+
+	// ErrRequiredField is matched by a *RequiredFieldError, returned when a required source value is missing
+	ErrRequiredField = errors.New("Required field")
+
+	// ErrUnsupportedType is returned with UnsupportedError when a source value is a func or a channel
+	ErrUnsupportedType = errors.New("Unsupported type")
+
+	// ErrSourceMutated is returned with Options.VerifySource when the transformation changed the source
+	ErrSourceMutated = errors.New("Source mutated")
+
+	// ErrDuplicateKey is returned when two fields produce the same output key, see Options.Collisions
+	ErrDuplicateKey = errors.New("Duplicate output key")
+
+	// ErrInvalidSchema is matched by the problems reported by ValidateSchema
+	ErrInvalidSchema = errors.New("Invalid schema")
+
+	// ErrSecretDetected is returned with SecretError when an emitted value looks like a secret
+	ErrSecretDetected = errors.New("Secret detected")
+
+	// ErrUnknownLocale is returned by a TimeFormatter for the locales it cannot format dates in
+	ErrUnknownLocale = errors.New("Unknown locale")
+)
+
 // IsEmpty will check if the Key or Value field is empty
-// This will prevent an empty value result being added to the mapped result
+// This will prevent an empty value result being added to the mapped result, unless a nil value is kept with Options.KeepNil
 func (v *Value) IsEmpty() bool {
 	if v.Key == "" {
 		return true
 	}
 
-	if v.Value == nil {
+	if v.Value == nil && !v.keepNil {
 		return true
 	}
 
 	return false
 }
 
-// Create a new mantau instance and set the default options
+// assignTo will add the value and it's additional keys to the result, an empty value is not added
+func (v *Value) assignTo(result Result) {
+	for key, value := range v.extra {
+		result[key] = value
+	}
+
+	if v.IsEmpty() {
+		return
+	}
+
+	result[v.Key] = v.Value
+}
+
+// Create a new mantau instance and set the default options.
+// An instance is safe for concurrent use
 func New() *mantau {
 	return &mantau{
+		mu: &sync.RWMutex{},
 		opt: &Options{
 			Hook: "json",
 		},
 	}
 }
 
-// SetOpt will override the default options with the given options
+// SetOpt will override the default options with the given options.
+// It's safe to call SetOpt while other goroutines are transforming data,
+// transformations which already started keep using the previous options
 func (m *mantau) SetOpt(opt *Options) {
-	m.opt = opt
+	copied := *opt
+
+	m.mu.Lock()
+	m.opt = &copied
+	m.mu.Unlock()
 }
 
 // Transform data with the given schema
+//
+// When Options.CollectErrors is set, field errors don't stop the transformation. The partial result
+// is returned together with a *MultiError describing every failed field
 func (m *mantau) Transform(src interface{}, schema Schema) (interface{}, error) {
-	return m.serialize(src, schema)
+	return m.withState().run(src, schema)
 }
 
 // Get the input data kind based on given value
@@ -123,6 +738,8 @@ func (m *mantau) shouldSkipTransform(src interface{}) bool {
 	switch value.(type) {
 	case time.Time:
 		return true
+	case json.Number:
+		return true
 	case string:
 		return true
 	case bool:
@@ -194,7 +811,7 @@ func (m *mantau) getPtrValue(src interface{}) interface{} {
 		return nil
 	}
 
-	if reflect.ValueOf(value.Interface()).IsZero() {
+	if !m.opt.KeepZero && reflect.ValueOf(value.Interface()).IsZero() {
 		return nil
 	}
 
@@ -213,67 +830,423 @@ func (m *mantau) transformMap(src interface{}, schema Schema) (Result, error) {
 		return nil, nil
 	}
 
-	result := Result{}
-	value := m.getValue(src)
+	result := make(Result, len(schema))
+	flat := []flattened{}
+	features := schema.features()
+	entries := m.mapEntries(src)
 
-	for _, val := range value.MapKeys() {
-		v, err := m.mapWithSchema(
-			val.String(),
-			value.MapIndex(val).Interface(),
-			schema,
-		)
+	if m.opt.NamespaceSeparator != "" {
+		entries = m.namespaceEntries(entries, schema)
+	}
 
-		if err != nil {
+	if features.paths {
+		entries = m.pathEntries(entries, schema)
+	}
+
+	if m.opt.SortMapKeys {
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].key < entries[j].key
+		})
+	}
+	names := make([]string, len(entries))
+	values := make([]interface{}, len(entries))
+
+	for i, e := range entries {
+		names[i], values[i] = e.key, e.value
+	}
+
+	if m.state != nil {
+		m.visitObject(schema, names)
+	}
+
+	m.resolveLazy(names, values, schema)
+
+	if features.required {
+		if err := m.checkRequired(names, values, schema); err != nil {
 			return nil, err
 		}
+	}
 
-		if v.IsEmpty() {
+	for _, e := range entries {
+		if schema.drops(e.key) {
 			continue
 		}
 
-		result[v.Key] = v.Value
+		if err := m.mapInto(result, &flat, e.key, Field{}, e.value, src, schema); err != nil {
+			return nil, err
+		}
+
+		if features.patterns {
+			if err := m.mapPatterns(result, &flat, e.key, e.value, src, schema); err != nil {
+				return nil, err
+			}
+		}
+
+		if features.wildcard {
+			if err := m.mapWildcard(result, &flat, e.key, e.value, src, schema); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	return result, nil
+	return m.completeObject(result, flat, names, src, schema, features)
+}
+
+// mapEntries will collect the keys and values of a map
+func (m *mantau) mapEntries(src interface{}) []entry {
+	value := m.getValue(src)
+	keys := value.MapKeys()
+	entries := make([]entry, len(keys))
+
+	for i, key := range keys {
+		entries[i] = entry{key: formatMapKey(key, m.opt.TimeKeyLayout), value: value.MapIndex(key).Interface()}
+	}
+
+	return entries
+}
+
+// formatMapKey will convert a map key into a string, so maps with interface{} keys like the ones decoded by yaml.v2
+// can be transformed. time.Time keys are formatted with the layout, or time.RFC3339 when it's empty
+func formatMapKey(key reflect.Value, layout string) string {
+	if key.Kind() == reflect.String {
+		return key.String()
+	}
+
+	if t, ok := key.Interface().(time.Time); ok {
+		if layout == "" {
+			layout = time.RFC3339
+		}
+
+		return t.Format(layout)
+	}
+
+	return fmt.Sprint(key.Interface())
 }
 
 // mapWithSchema will iterates the given schema and find the corresponding data based on the given value
 // and return mantau.Value as the final result
 func (m *mantau) mapWithSchema(field string, value interface{}, schema Schema) (Value, error) {
+	return m.mapWithTag(field, Field{}, value, nil, schema)
+}
+
+// mapWithTag works like mapWithSchema but also applies the field behavior declared on a struct tag.
+// The parent is the source value containing the field, it's used to choose a conditional nested schema
+func (m *mantau) mapWithTag(field string, tag Field, value, parent interface{}, schema Schema) (Value, error) {
 	for key, val := range schema {
-		if val.Key == field {
-			schemaValue := schema
+		if val.Key == field && m.readsKey(val) {
+			v, err := m.mapField(key, field, val.withTag(tag), value, parent, schema)
 
-			if s, ok := val.Value.(Schema); ok {
-				schemaValue = s
+			if err != nil {
+				return Value{}, m.fieldError(key, err)
 			}
 
-			v, err := m.transformValue(value, schemaValue)
+			return v, nil
+		}
+	}
+
+	return Value{}, nil
+}
+
+// mapInto works like mapWithTag but maps the source field with every schema field matching it
+// and adds the values to the result, so a source field can be emitted under several keys.
+// Flattened objects are added to flat instead, they are merged after every field is mapped
+func (m *mantau) mapInto(result Result, flat *[]flattened, field string, tag Field, value, parent interface{}, schema Schema) error {
+	for key, val := range schema {
+		if val.Key != field || !m.readsKey(val) {
+			continue
+		}
+
+		if err := m.mapOne(result, flat, key, field, val.withTag(tag), value, parent, schema); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mapOne will map the source field with a single schema field and add the value to the result
+func (m *mantau) mapOne(result Result, flat *[]flattened, key, sourceKey string, field Field, value, parent interface{}, schema Schema) error {
+	v, err := m.mapField(key, sourceKey, field, value, parent, schema)
+
+	if err != nil {
+		err = m.fieldError(key, err)
+
+		if m.collect(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if ok, err := m.checkSize(result, v); !ok {
+		return err
+	}
+
+	if v.flatten != nil {
+		*flat = append(*flat, *v.flatten)
+	}
+
+	if !v.IsEmpty() {
+		if ok, err := m.checkCollision(result, key, v.Key, sourceKey); !ok {
+			return err
+		}
+	}
+
+	if v.Value, err = m.scanSecrets(v.Value); err != nil {
+		err = m.fieldError(key, err)
+
+		if m.collect(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	v.assignTo(result)
+
+	return nil
+}
+
+// checkCollision will check if a value can be added to the result under the output key, following Options.Collisions
+// when the key is already in the result. It returns false with the error to return, or nil when the value is skipped
+func (m *mantau) checkCollision(result Result, key, outputKey, sourceKey string) (bool, error) {
+	if _, ok := result[outputKey]; !ok {
+		return true, nil
+	}
+
+	switch m.opt.Collisions {
+	case CollisionKeep:
+		return false, nil
+	case CollisionOverride:
+		return true, nil
+	}
+
+	err := m.fieldError(key, &DuplicateKeyError{Key: outputKey, Source: sourceKey})
+
+	if m.collect(err) {
+		return false, nil
+	}
+
+	return false, err
+}
+
+// mapField will transform the value of a source field matched by the schema field under the given key
+func (m *mantau) mapField(key, sourceKey string, field Field, value, parent interface{}, schema Schema) (Value, error) {
+	redact, err := m.checkClearance(key, field)
+
+	if err != nil {
+		return Value{}, err
+	}
+
+	if redact {
+		m.record(key, sourceKey, field, value, OutcomeRedacted)
+		return Value{Key: key, Value: Redacted}, nil
+	}
+
+	if sampled, err := m.sampled(field, parent); err != nil || !sampled {
+		if err == nil {
+			m.record(key, sourceKey, field, value, OutcomeSampledOut)
+		}
+
+		return Value{}, err
+	}
+
+	value, err = resolve(value)
+
+	if err != nil {
+		return Value{}, err
+	}
+
+	if unsupported(value) {
+		if m.opt.Unsupported == UnsupportedError {
+			return Value{}, fmt.Errorf("%w: %s %s cannot be transformed", ErrUnsupportedType, sourceKey, reflect.TypeOf(value))
+		}
+
+		return Value{}, nil
+	}
+
+	if err := m.checkMismatch(field, value); err != nil {
+		switch m.opt.Mismatch {
+		case MismatchOmit:
+			m.record(key, sourceKey, field, value, OutcomeOmitted)
+			return Value{}, nil
+		case MismatchRaw:
+			m.record(key, sourceKey, field, value, OutcomeRaw)
+			return Value{Key: key, Value: m.getValue(value).Interface()}, nil
+		}
+
+		return Value{}, fmt.Errorf("%w: field %q (%s) %s", ErrSchemaMismatch, key, sourceKey, err.Error())
+	}
+
+	m.use(key, sourceKey)
+
+	source := value
+
+	schemaValue := m.nestedSchema(field, parent, schema)
+	truncated := false
+
+	if field.Pick != "" {
+		value = m.pick(value, field.Pick)
+	}
+
+	if field.page != nil {
+		value = m.paginate(value, field.page)
+	}
+
+	if field.MaxElements > 0 {
+		value, truncated = m.truncate(value, field.MaxElements)
+	}
+
+	m.enter(key, sourceKey)
+	v, err := m.transformField(field, value, parent, schemaValue)
+	m.leave()
+
+	if err != nil {
+		return Value{}, err
+	}
+
+	v = m.include(field.Include, value, v)
+
+	v, ok, err := m.applyField(field, v)
+
+	if err != nil {
+		return Value{}, err
+	}
+
+	if !ok {
+		m.record(key, sourceKey, field, source, OutcomeOmitted)
+		return Value{}, nil
+	}
+
+	m.record(key, sourceKey, field, source, OutcomeEmitted)
+
+	if field.Truncate != nil {
+		var cut bool
+		v, cut = truncateText(v, field.Truncate)
+		truncated = truncated || cut
+	}
+
+	if v == nil && m.keepsNil(field, schemaValue) {
+		v = null
+	}
+
+	m.deprecated(key, field)
+
+	if field.Flatten {
+		object, ok := v.(Result)
+
+		if !ok && v != nil {
+			return Value{}, fmt.Errorf("Cannot flatten %T, only objects can be flattened", v)
+		}
+
+		return Value{flatten: &flattened{key: key, value: object, policy: field.Collisions}}, nil
+	}
+
+	result := Value{Key: key, Value: m.withProvenance(sourceKey, source, v), keepNil: m.opt.KeepNil}
+
+	if truncated && field.MarkTruncated {
+		result.extra = Result{key + "_truncated": true}
+	}
+
+	return result, nil
+}
+
+// transformField will transform the value of a field with it's nested schema
+func (m *mantau) transformField(field Field, value, parent interface{}, schema Schema) (interface{}, error) {
+	if field.money != "" {
+		return m.transformMoney(field.money, value, parent)
+	}
+
+	if schemas, ok := field.Value.(map[string]Schema); ok {
+		return m.transformKeyed(value, schemas)
+	}
+
+	if field.MapAs != "" {
+		return m.transformMapAs(field.MapAs, value, schema)
+	}
+
+	if field.Pluck != "" {
+		return m.transformPluck(field, value)
+	}
+
+	if field.IndexBy != "" {
+		return m.transformIndexBy(field.IndexBy, value, schema)
+	}
+
+	return m.transformValue(value, schema)
+}
+
+// transformKeyed will transform every value of a map with the schema registered for it's formatted key,
+// keys without a schema are omitted
+func (m *mantau) transformKeyed(src interface{}, schemas map[string]Schema) (interface{}, error) {
+	if src == nil {
+		return nil, nil
+	}
+
+	value := m.getValue(src)
+
+	if value.Kind() != reflect.Map {
+		return nil, fmt.Errorf("%w: keyed schemas require a map but the source value is %T", ErrSchemaMismatch, src)
+	}
+
+	result := Result{}
+
+	for _, e := range m.mapEntries(src) {
+		schema, ok := schemas[e.key]
+
+		if !ok {
+			continue
+		}
+
+		m.enter(e.key, e.key)
+		v, err := m.transformValue(e.value, schema)
+		m.leave()
+
+		if err != nil {
+			return nil, err
+		}
 
+		if v == nil {
+			continue
+		}
+
+		// keys like 1 and "1" of an interface{} keyed map are formatted into the same key
+		if ok, err := m.checkCollision(result, e.key, e.key, e.key); !ok {
 			if err != nil {
-				return Value{}, err
+				return nil, err
 			}
 
-			return Value{Key: key, Value: v}, nil
+			continue
 		}
+
+		result[e.key] = v
 	}
 
-	return Value{}, nil
+	return result, nil
+}
+
+// nestedSchema will determine the schema used to transform the value of a field.
+// When the field has no nested schema, the current schema is used
+func (m *mantau) nestedSchema(field Field, parent interface{}, schema Schema) Schema {
+	switch value := field.Value.(type) {
+	case Schema:
+		return value
+	case func(parent interface{}) Schema:
+		if nested := value(parent); nested != nil {
+			return nested
+		}
+	}
+
+	return schema
 }
 
 // tagLookup is used specifically for struct
 // tagLookup will find the struct tag on a struct field
 // the tag is used to map the struct value with the schema
-func (m *mantau) tagLookup(t reflect.Type, fieldName string) (string, error) {
-	field, ok := t.FieldByName(fieldName)
-
-	if !ok {
-		return "", errors.New("Cannot find the field")
-	}
+func (m *mantau) tagLookup(t reflect.Type, i int) (string, error) {
+	tag := structTags(t, m.opt.Hook)[i]
 
-	tag, ok := field.Tag.Lookup(m.opt.Hook)
-
-	if tag == "" || !ok {
+	if tag == "" {
 		return "", errors.New("Cannot find tag")
 	}
 
@@ -293,51 +1266,84 @@ func (m *mantau) serialize(src interface{}, schema Schema) (interface{}, error)
 		return nil, nil
 	}
 
-	switch kind {
-	case Struct:
-		return m.transformStruct(src, schema)
-	case Slice:
-		return m.transformCollections(src, schema)
-	case Array:
-		return m.transformCollections(src, schema)
-	case Map:
-		return m.transformMap(src, schema)
-	}
+	return m.cached(src, schema, func() (interface{}, error) {
+		switch kind {
+		case Struct:
+			return m.transformStruct(src, schema)
+		case Slice:
+			return m.transformCollections(src, schema)
+		case Array:
+			return m.transformCollections(src, schema)
+		case Map:
+			return m.transformMap(src, schema)
+		}
 
-	return nil, nil
+		return nil, nil
+	})
 }
 
 // transformValue will check for individual value after it's being transformed,
 // if the given value contains nested data structure it will determine which process to take
 // to get the final result
 func (m *mantau) transformValue(src interface{}, schema Schema) (interface{}, error) {
+	src, err := resolve(src)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if src == nil || (m.getKind(src) == Pointer && reflect.ValueOf(src).IsNil()) {
+		return nil, nil
+	}
+
+	if leaf, ok := m.leafValue(src); ok {
+		return leaf, nil
+	}
 
 	// Check if the value cannot be transformed. If so, then just return it
 	if m.shouldSkipTransform(src) {
 		return m.getValue(src).Interface(), nil
 	}
 
-	kind := m.getKind(src)
+	if err := m.checkDepth(); err != nil {
+		return nil, err
+	}
+
+	if m.stackFull() {
+		return m.onNewStack(func() (interface{}, error) {
+			return m.transformValue(src, schema)
+		})
+	}
+
+	return m.cached(src, schema, func() (interface{}, error) {
+		kind := m.getKind(src)
 
-	switch kind {
-	case Struct:
-		return m.transformStruct(src, schema)
-	case Slice:
-		return m.transformCollections(src, schema)
-	case Array:
-		return m.transformCollections(src, schema)
-	case Map:
-		return m.transformMap(src, schema)
-	case Pointer:
-		value := m.getPtrValue(src)
-
-		return m.transformValue(
-			value,
-			schema,
-		)
-	}
-
-	return nil, nil
+		switch kind {
+		case Struct:
+			return m.transformStruct(src, schema)
+		case Slice:
+			if elements, ok := src.([]interface{}); ok {
+				return m.transformInterfaces(elements, schema)
+			}
+
+			return m.transformCollections(src, schema)
+		case Array:
+			return m.transformCollections(src, schema)
+		case Map:
+			return m.transformMap(src, schema)
+		case Pointer:
+			return m.memoize(src, schema, func() (interface{}, error) {
+				value := m.getPtrValue(src)
+
+				return m.transformValue(
+					value,
+					schema,
+				)
+			})
+		}
+
+		return nil, nil
+	})
 }
 
 // transformCollections will take an array or slice as an input and transform
@@ -350,8 +1356,12 @@ func (m *mantau) transformCollections(src interface{}, schema Schema) ([]Result,
 	result := make([]Result, 0)
 	value := m.getValue(src)
 
-	for i := 0; i < value.Len(); i++ {
-		v, err := m.transformValue(value.Index(i).Interface(), schema)
+	for i := 0; i < value.Len() && !m.isTruncated(); i++ {
+		element := value.Index(i).Interface()
+
+		m.enterIndex(i)
+		v, err := m.transformValue(element, schema)
+		m.leave()
 
 		if err != nil {
 			return nil, err
@@ -363,6 +1373,7 @@ func (m *mantau) transformCollections(src interface{}, schema Schema) ([]Result,
 			continue
 		}
 
+		m.includeSource(res, element)
 		result = append(result, res)
 	}
 
@@ -376,29 +1387,132 @@ func (m *mantau) transformStruct(src interface{}, schema Schema) (Result, error)
 		return nil, nil
 	}
 
-	result := Result{}
+	result := make(Result, len(schema))
+	flat := []flattened{}
+	features := schema.features()
 	value := m.getValue(src)
 	dataType := m.getType(src)
+	tags := loadTags(dataType, m.opt.Hook)
+	fields := len(tags.names)
+	// the cached names are shared, the full slice expression makes appending to them copy
+	names := tags.names[:fields:fields]
 
-	for i := 0; i < value.NumField(); i++ {
-		tag, err := m.tagLookup(value.Type(), dataType.Field(i).Name)
+	if !tags.tagged {
+		names = make([]string, fields)
 
-		if err != nil {
+		for i := range names {
+			tag, err := m.structFieldName(dataType, i)
+
+			if err != nil {
+				return nil, err
+			}
+
+			names[i], _ = parseTag(tag)
+		}
+	}
+
+	values := make([]interface{}, value.NumField())
+
+	for i := range values {
+		if names[i] != "" {
+			values[i] = value.Field(i).Interface()
+		}
+	}
+
+	if features.paths {
+		entries := make([]entry, 0, fields)
+
+		for i, name := range names {
+			if name != "" {
+				entries = append(entries, entry{key: name, value: values[i]})
+			}
+		}
+
+		// the dotted keys are added after the fields, so the fields keep their index
+		for _, e := range m.pathEntries(entries, schema)[len(entries):] {
+			names, values = append(names, e.key), append(values, e.value)
+		}
+	}
+
+	m.visitObject(schema, names)
+
+	m.resolveLazy(names, values, schema)
+
+	if features.required {
+		if err := m.checkRequired(names, values, schema); err != nil {
 			return nil, err
 		}
+	}
 
-		v, err := m.mapWithSchema(tag, value.Field(i).Interface(), schema)
+	compiled := m.compiled(dataType, names[:fields], schema)
 
-		if err != nil {
+	for i := 0; i < value.NumField(); i++ {
+		if names[i] == "" {
+			continue
+		}
+
+		if compiled != nil {
+			if err := m.mapCompiled(result, &flat, compiled.fields[i], tags.options[i], values[i], src, schema); err != nil {
+				return nil, err
+			}
+		} else if err := m.mapInto(result, &flat, names[i], tags.options[i], values[i], src, schema); err != nil {
 			return nil, err
 		}
 
-		if v.IsEmpty() {
+		if !features.wildcard {
 			continue
 		}
 
-		result[v.Key] = v.Value
+		if err := m.mapWildcard(result, &flat, names[i], values[i], src, schema); err != nil {
+			return nil, err
+		}
 	}
 
-	return result, nil
+	for i := fields; i < len(names); i++ {
+		if err := m.mapInto(result, &flat, names[i], Field{}, values[i], src, schema); err != nil {
+			return nil, err
+		}
+	}
+
+	return m.completeObject(result, flat, names, src, schema, features)
+}
+
+// completeObject will add the default, computed, flattened and injected values to the object mapped from
+// the source keys, then nest the dotted keys and finalize it. Only the passes the schema needs are run
+func (m *mantau) completeObject(result Result, flat []flattened, keys []string, src interface{}, schema Schema, features features) (Result, error) {
+	if features.defaults {
+		if err := m.defaultFields(result, &flat, keys, src, schema); err != nil {
+			return nil, err
+		}
+	}
+
+	if features.computed {
+		if err := m.computeFields(result, &flat, src, schema); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.mergeFlattened(result, flat); err != nil {
+		return nil, err
+	}
+
+	if features.injected {
+		if err := m.injectFields(result, schema); err != nil {
+			return nil, err
+		}
+	}
+
+	if features.dotted {
+		if err := m.nestKeys(result, schema); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := schema.finalizeResult(result)
+
+	if err != nil || m.opt.SecretScanner == nil {
+		return result, err
+	}
+
+	return m.scannedObject(result, schema)
 }
@@ -0,0 +1,12 @@
+module github.com/dwadp/mantau/mantaupb
+
+go 1.14
+
+require (
+	github.com/dwadp/mantau v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.6.1
+	google.golang.org/protobuf v1.27.1
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/dwadp/mantau => ../
@@ -0,0 +1,157 @@
+// Package mantaupb derives mantau schemas from protobuf message descriptors,
+// so gRPC backed services get a presentation layer starting point
+package mantaupb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dwadp/mantau"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// timestampName is the full name of the google.protobuf.Timestamp well known type
+const timestampName = "google.protobuf.Timestamp"
+
+// SchemaFromProto will build a default schema from a message descriptor. The keys of the schema are the
+// json_name of the fields and they read the proto field names, which are the keys of MessageToMap and the
+// json tags of the structs generated by protoc-gen-go. Nested messages, repeated messages and maps get
+// their own nested schema, recursive messages reuse the schema of their parent
+func SchemaFromProto(md protoreflect.MessageDescriptor) mantau.Schema {
+	return (&builder{schemas: map[protoreflect.FullName]mantau.Schema{}, building: map[protoreflect.FullName]bool{}}).schema(md)
+}
+
+// builder builds the schema of every message once
+type builder struct {
+	schemas  map[protoreflect.FullName]mantau.Schema
+	building map[protoreflect.FullName]bool
+}
+
+// schema will build the schema of a message
+func (b *builder) schema(md protoreflect.MessageDescriptor) mantau.Schema {
+	if schema, ok := b.schemas[md.FullName()]; ok {
+		return schema
+	}
+
+	schema := mantau.Schema{}
+	b.schemas[md.FullName()] = schema
+	b.building[md.FullName()] = true
+
+	fields := md.Fields()
+
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		field := mantau.Field{Key: string(fd.Name())}
+
+		switch {
+		case fd.IsMap():
+			field.Value = mantau.Schema{"*": mantau.Field{Value: b.value(fd.MapValue())}}
+		default:
+			field.Value = b.value(fd)
+		}
+
+		schema[fd.JSONName()] = field
+	}
+
+	delete(b.building, md.FullName())
+
+	return schema
+}
+
+// value will return the nested schema of a message field, fields of other kinds don't have a nested schema
+func (b *builder) value(fd protoreflect.FieldDescriptor) interface{} {
+	if fd.Kind() != protoreflect.MessageKind && fd.Kind() != protoreflect.GroupKind {
+		return nil
+	}
+
+	md := fd.Message()
+
+	if md.FullName() == timestampName {
+		return nil
+	}
+
+	// A recursive message is still being built, it's resolved when the field is transformed
+	if b.building[md.FullName()] {
+		return func(parent interface{}) mantau.Schema {
+			return b.schemas[md.FullName()]
+		}
+	}
+
+	return b.schema(md)
+}
+
+// MessageToMap will convert a message into a map keyed by the proto field names, so it can be transformed
+// with a schema built by SchemaFromProto. Enums are converted into their names, google.protobuf.Timestamp
+// into a time.Time and unset message and oneof fields are omitted
+func MessageToMap(msg proto.Message) map[string]interface{} {
+	return messageToMap(msg.ProtoReflect())
+}
+
+// messageToMap will convert a message into a map
+func messageToMap(msg protoreflect.Message) map[string]interface{} {
+	result := map[string]interface{}{}
+	fields := msg.Descriptor().Fields()
+
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+
+		if fd.HasPresence() && !msg.Has(fd) {
+			continue
+		}
+
+		result[string(fd.Name())] = fieldValue(fd, msg.Get(fd))
+	}
+
+	return result
+}
+
+// fieldValue will convert the value of a field
+func fieldValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	switch {
+	case fd.IsList():
+		list := v.List()
+		values := make([]interface{}, list.Len())
+
+		for i := range values {
+			values[i] = singularValue(fd, list.Get(i))
+		}
+
+		return values
+	case fd.IsMap():
+		values := map[string]interface{}{}
+
+		v.Map().Range(func(key protoreflect.MapKey, value protoreflect.Value) bool {
+			values[fmt.Sprint(key.Interface())] = singularValue(fd.MapValue(), value)
+			return true
+		})
+
+		return values
+	}
+
+	return singularValue(fd, v)
+}
+
+// singularValue will convert a single value of a field
+func singularValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if value := fd.Enum().Values().ByNumber(v.Enum()); value != nil {
+			return string(value.Name())
+		}
+
+		return int32(v.Enum())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		msg := v.Message()
+
+		if msg.Descriptor().FullName() == timestampName {
+			fields := msg.Descriptor().Fields()
+
+			return time.Unix(msg.Get(fields.ByName("seconds")).Int(), msg.Get(fields.ByName("nanos")).Int()).UTC()
+		}
+
+		return messageToMap(msg)
+	}
+
+	return v.Interface()
+}
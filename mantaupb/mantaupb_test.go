@@ -0,0 +1,137 @@
+package mantaupb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dwadp/mantau"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func field(name, jsonName string, number int32, kind descriptorpb.FieldDescriptorProto_Type, typeName string, repeated bool) *descriptorpb.FieldDescriptorProto {
+	label := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+
+	if repeated {
+		label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	}
+
+	f := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		JsonName: proto.String(jsonName),
+		Number:   proto.Int32(number),
+		Label:    label.Enum(),
+		Type:     kind.Enum(),
+	}
+
+	if typeName != "" {
+		f.TypeName = proto.String(typeName)
+	}
+
+	return f
+}
+
+func userDescriptor(t *testing.T) protoreflect.MessageDescriptor {
+	str := descriptorpb.FieldDescriptorProto_TYPE_STRING
+	msg := descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("user.proto"),
+		Package:    proto.String("test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto"},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Status"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("STATUS_UNKNOWN"), Number: proto.Int32(0)},
+				{Name: proto.String("STATUS_ACTIVE"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name:  proto.String("Address"),
+				Field: []*descriptorpb.FieldDescriptorProto{field("postal_code", "postalCode", 1, str, "", false)},
+			},
+			{
+				Name: proto.String("User"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("user_name", "userName", 1, str, "", false),
+					field("status", "status", 2, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".test.Status", false),
+					field("address", "address", 3, msg, ".test.Address", false),
+					field("tags", "tags", 4, str, "", true),
+					field("labels", "labels", 5, msg, ".test.User.LabelsEntry", true),
+					field("manager", "manager", 6, msg, ".test.User", false),
+					field("created_at", "createdAt", 7, msg, ".google.protobuf.Timestamp", false),
+				},
+				NestedType: []*descriptorpb.DescriptorProto{{
+					Name: proto.String("LabelsEntry"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						field("key", "key", 1, str, "", false),
+						field("value", "value", 2, str, "", false),
+					},
+					Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+				}},
+			},
+		},
+	}
+
+	fd, err := protodesc.NewFile(file, protoregistry.GlobalFiles)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return fd.Messages().ByName("User")
+}
+
+func TestSchemaFromProto(t *testing.T) {
+	md := userDescriptor(t)
+	schema := SchemaFromProto(md)
+
+	assert.Equal(t, mantau.Field{Key: "user_name"}, schema["userName"])
+	assert.Equal(t, mantau.Field{Key: "status"}, schema["status"])
+	assert.Equal(t, mantau.Field{Key: "created_at"}, schema["createdAt"])
+	assert.Equal(t, mantau.Field{Key: "address", Value: mantau.Schema{"postalCode": mantau.Field{Key: "postal_code"}}}, schema["address"])
+	assert.Equal(t, mantau.Field{Key: "labels", Value: mantau.Schema{"*": mantau.Field{}}}, schema["labels"])
+	assert.IsType(t, func(interface{}) mantau.Schema { return nil }, schema["manager"].Value, "A recursive message should be resolved lazily")
+
+	user := dynamicpb.NewMessage(md)
+	fields := md.Fields()
+	address := dynamicpb.NewMessage(fields.ByName("address").Message())
+	manager := dynamicpb.NewMessage(md)
+
+	address.Set(address.Descriptor().Fields().ByName("postal_code"), protoreflect.ValueOfString("12345"))
+	manager.Set(fields.ByName("user_name"), protoreflect.ValueOfString("Jane"))
+
+	user.Set(fields.ByName("user_name"), protoreflect.ValueOfString("John"))
+	user.Set(fields.ByName("status"), protoreflect.ValueOfEnum(1))
+	user.Set(fields.ByName("address"), protoreflect.ValueOfMessage(address))
+	user.Set(fields.ByName("manager"), protoreflect.ValueOfMessage(manager))
+	user.Set(fields.ByName("created_at"), protoreflect.ValueOfMessage(timestamppb.New(time.Unix(1600000000, 0)).ProtoReflect()))
+	user.Mutable(fields.ByName("tags")).List().Append(protoreflect.ValueOfString("admin"))
+	user.Mutable(fields.ByName("labels")).Map().Set(protoreflect.ValueOfString("team").MapKey(), protoreflect.ValueOfString("core"))
+
+	result, err := mantau.New().Transform(MessageToMap(user), schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, mantau.Result{
+		"userName":  "John",
+		"status":    "STATUS_ACTIVE",
+		"address":   mantau.Result{"postalCode": "12345"},
+		"tags":      []interface{}{"admin"},
+		"labels":    mantau.Result{"team": "core"},
+		"createdAt": time.Unix(1600000000, 0).UTC(),
+		"manager": mantau.Result{
+			"userName": "Jane",
+			"status":   "STATUS_UNKNOWN",
+			"tags":     []interface{}{},
+			"labels":   mantau.Result{},
+		},
+	}, result, "The result do not match")
+}
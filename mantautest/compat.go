@@ -0,0 +1,18 @@
+package mantautest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dwadp/mantau"
+)
+
+// RequireCompatible will stop the test when the new schema removes or retypes an output key of the old schema,
+// so schema evolution rules are enforced by the test suite. Adding keys is compatible
+func RequireCompatible(t testing.TB, oldSchema, newSchema mantau.Schema) {
+	t.Helper()
+
+	if changes := oldSchema.BreakingChanges(newSchema); len(changes) > 0 {
+		t.Fatalf("The schema has breaking changes:\n%s", strings.Join(changes, "\n"))
+	}
+}
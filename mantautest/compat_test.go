@@ -0,0 +1,43 @@
+package mantautest
+
+import (
+	"testing"
+
+	"github.com/dwadp/mantau"
+)
+
+func TestRequireCompatible(t *testing.T) {
+	v1 := mantau.Schema{
+		"title": mantau.Field{Key: "name"},
+		"cost":  mantau.Field{Key: "price", As: "string"},
+		"store": mantau.Field{Key: "store", Value: mantau.Schema{
+			"name": mantau.Field{Key: "name"},
+			"code": mantau.Field{Key: "code"},
+		}},
+	}
+
+	compatible := v1.Override(mantau.Schema{
+		"title": mantau.Field{Key: "product_name"},
+		"stock": mantau.Field{Key: "stock"},
+	})
+
+	RequireCompatible(t, v1, compatible)
+
+	cases := map[string]mantau.Schema{
+		"removed": v1.Override(mantau.Schema{"title": mantau.Tombstone()}),
+		"retyped": v1.Override(mantau.Schema{"cost": mantau.Field{Key: "price", As: "float"}}),
+		"nested": v1.Override(mantau.Schema{"store": mantau.Field{Key: "store", Value: mantau.Schema{
+			"name": mantau.Field{Key: "name"},
+		}}}),
+		"shape": v1.Override(mantau.Schema{"store": mantau.Field{Key: "store", As: "string"}}),
+	}
+
+	for name, schema := range cases {
+		r := &recorder{}
+		RequireCompatible(r, v1, schema)
+
+		if !r.failed {
+			t.Errorf("RequireCompatible should fail when a key is %s", name)
+		}
+	}
+}
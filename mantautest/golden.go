@@ -0,0 +1,113 @@
+package mantautest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dwadp/mantau"
+)
+
+// UpdateFlag is the flag rewriting golden files with the current output e.g. go test ./... -update.
+// Golden doesn't define it, so it doesn't collide with the flag of other golden file helpers. It's honored
+// when the test binary defines it e.g. var update = flag.Bool("update", false, "update golden files")
+const UpdateFlag = "update"
+
+// UpdateEnv is the environment variable rewriting golden files with the current output when it's set to a true value
+// e.g. MANTAU_UPDATE_GOLDEN=1 go test ./... It works whether the update flag is defined or not
+const UpdateEnv = "MANTAU_UPDATE_GOLDEN"
+
+// Golden will transform the source with the given schema and compare the JSON encoded result
+// with the content of the golden file. Run the tests with -update or MANTAU_UPDATE_GOLDEN=1 to create or rewrite the golden file
+func Golden(t testing.TB, m Transformer, src interface{}, schema mantau.Schema, path string) bool {
+	t.Helper()
+
+	got, err := m.Transform(src, schema)
+
+	if err != nil {
+		t.Errorf("Transform returned an error: %v", err)
+		return false
+	}
+
+	// Map keys are sorted by the json encoder so the output is deterministic
+	output, err := json.MarshalIndent(got, "", "  ")
+
+	if err != nil {
+		t.Errorf("Cannot encode the result: %v", err)
+		return false
+	}
+
+	output = append(output, '\n')
+
+	if updateGolden() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Errorf("Cannot create the golden file directory: %v", err)
+			return false
+		}
+
+		if err := ioutil.WriteFile(path, output, 0644); err != nil {
+			t.Errorf("Cannot write the golden file: %v", err)
+			return false
+		}
+
+		return true
+	}
+
+	want, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		t.Errorf("Cannot read the golden file, run the tests with -%s or %s=1 to create it: %v", UpdateFlag, UpdateEnv, err)
+		return false
+	}
+
+	if !bytes.Equal(want, output) {
+		t.Errorf("The result do not match the golden file %s\n%s", path, diff(string(want), string(output)))
+		return false
+	}
+
+	return true
+}
+
+// updateGolden will check if the golden files should be rewritten, see UpdateFlag and UpdateEnv
+func updateGolden() bool {
+	if f := flag.Lookup(UpdateFlag); f != nil {
+		if update, _ := strconv.ParseBool(f.Value.String()); update {
+			return true
+		}
+	}
+
+	update, _ := strconv.ParseBool(os.Getenv(UpdateEnv))
+
+	return update
+}
+
+// diff will describe the first line where the golden content and the output differ
+func diff(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+
+		if w != g {
+			return fmt.Sprintf("first difference at line %d:\nwant: %s\n got: %s", i+1, w, g)
+		}
+	}
+
+	return ""
+}
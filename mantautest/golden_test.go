@@ -0,0 +1,80 @@
+package mantautest
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/dwadp/mantau"
+)
+
+// update is defined like the flag of other golden file helpers, Golden must not define it again
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGolden(t *testing.T) {
+	m := mantau.New()
+
+	schema := mantau.Schema{
+		"title": mantau.Field{Key: "name"},
+		"cost":  mantau.Field{Key: "price"},
+	}
+
+	Golden(t, m, []product{{Name: "Apple", Price: 5}, {Name: "Orange", Price: 2.5}}, schema, "testdata/products.golden.json")
+
+	// the mismatches below would be written into the golden files
+	if updateGolden() {
+		return
+	}
+
+	rec := &recorder{TB: t}
+
+	if Golden(rec, m, product{Name: "Lemon", Price: 1}, schema, "testdata/products.golden.json") {
+		t.Error("Mismatched output should fail the golden comparison")
+	}
+
+	rec = &recorder{TB: t}
+
+	if Golden(rec, m, product{Name: "Lemon", Price: 1}, schema, "testdata/missing.golden.json") {
+		t.Error("Missing golden file should fail the golden comparison")
+	}
+}
+
+func TestGoldenUpdate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golden")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "lemon.golden.json")
+	schema := mantau.Schema{"title": mantau.Field{Key: "name"}}
+
+	os.Setenv(UpdateEnv, "1")
+	updated := Golden(t, mantau.New(), product{Name: "Lemon"}, schema, path)
+	os.Unsetenv(UpdateEnv)
+
+	if !updated {
+		t.Fatal("The golden file should be written")
+	}
+
+	if !Golden(t, mantau.New(), product{Name: "Lemon"}, schema, path) {
+		t.Error("The written golden file should match the output")
+	}
+
+	flag.Set(UpdateFlag, "true")
+	updated = Golden(t, mantau.New(), product{Name: "Lime"}, schema, path)
+	flag.Set(UpdateFlag, strconv.FormatBool(*update))
+
+	if !updated {
+		t.Fatal("The golden file should be written with the update flag")
+	}
+
+	if !Golden(t, mantau.New(), product{Name: "Lime"}, schema, path) {
+		t.Error("The golden file written with the update flag should match the output")
+	}
+}
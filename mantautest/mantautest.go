@@ -0,0 +1,39 @@
+// Package mantautest provides helpers for testing mantau schemas
+package mantautest
+
+import (
+	"testing"
+
+	"github.com/dwadp/mantau"
+)
+
+// Transformer is implemented by a mantau instance
+type Transformer interface {
+	Transform(src interface{}, schema mantau.Schema) (interface{}, error)
+}
+
+// AssertTransforms will transform the source with the given schema and fail the test
+// when the transformation returns an error or the result is not equal to want.
+// Numbers are compared by their value, see mantau.Result.Equal
+func AssertTransforms(t testing.TB, m Transformer, src interface{}, schema mantau.Schema, want interface{}) bool {
+	t.Helper()
+
+	got, err := m.Transform(src, schema)
+
+	if err != nil {
+		t.Errorf("Transform returned an error: %v", err)
+		return false
+	}
+
+	if !equal(got, want) {
+		t.Errorf("The result do not match\nwant: %#v\n got: %#v", want, got)
+		return false
+	}
+
+	return true
+}
+
+// equal will compare the transformed value with the wanted value using mantau.Result.Equal
+func equal(got, want interface{}) bool {
+	return mantau.Result{"": got}.Equal(mantau.Result{"": want})
+}
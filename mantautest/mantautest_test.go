@@ -0,0 +1,56 @@
+package mantautest
+
+import (
+	"testing"
+
+	"github.com/dwadp/mantau"
+)
+
+type product struct {
+	Name  string  `json:"name"`
+	Price float64 `json:"price"`
+}
+
+// recorder records failures instead of failing the running test
+type recorder struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failed = true
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.failed = true
+}
+
+func TestAssertTransforms(t *testing.T) {
+	m := mantau.New()
+
+	schema := mantau.Schema{
+		"title": mantau.Field{Key: "name"},
+		"cost":  mantau.Field{Key: "price"},
+	}
+
+	AssertTransforms(t, m, product{Name: "Apple", Price: 5}, schema, mantau.Result{
+		"title": "Apple",
+		"cost":  5,
+	})
+
+	AssertTransforms(t, m, []product{{Name: "Apple", Price: 5}}, schema, []mantau.Result{
+		{"title": "Apple", "cost": 5},
+	})
+
+	rec := &recorder{TB: t}
+
+	if AssertTransforms(rec, m, product{Name: "Apple", Price: 5}, schema, mantau.Result{"title": "Orange"}) {
+		t.Error("Mismatched result should fail the assertion")
+	}
+
+	if !rec.failed {
+		t.Error("Mismatched result should report an error")
+	}
+}
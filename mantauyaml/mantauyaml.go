@@ -0,0 +1,59 @@
+// Package mantauyaml loads mantau schemas from YAML, so transformation mappings can be defined
+// alongside the rest of a service configuration
+package mantauyaml
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/dwadp/mantau"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadSchemaYAML will decode a schema stored as YAML. The format is the format of mantau.LoadSchemaJSON,
+// every field is a mapping of it's options in snake case with a nested schema under "fields"
+//
+//	username:
+//	  key: name
+//	user_permissions:
+//	  key: permissions
+//	  fields:
+//	    code: {key: permission_code, as: string}
+func LoadSchemaYAML(data []byte) (mantau.Schema, error) {
+	var doc interface{}
+
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("Invalid schema: %v", err)
+	}
+
+	// an empty document is an empty schema
+	if doc == nil {
+		return mantau.Schema{}, nil
+	}
+
+	encoded, err := json.Marshal(doc)
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid schema: %v", err)
+	}
+
+	return mantau.LoadSchemaJSON(encoded)
+}
+
+// MarshalSchemaYAML will encode the schema as YAML, so it can be loaded with LoadSchemaYAML.
+// Fields with functions e.g. Transform or Const cannot be encoded and return an error
+func MarshalSchemaYAML(schema mantau.Schema) ([]byte, error) {
+	encoded, err := json.Marshal(schema)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+
+	if err := json.Unmarshal(encoded, &doc); err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(doc)
+}
@@ -0,0 +1,69 @@
+package mantauyaml
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/dwadp/mantau"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadSchemaYAML(t *testing.T) {
+	schema, err := LoadSchemaYAML([]byte(`
+username:
+  key: name
+email:
+  key: email
+  normalize: email
+  mask: first2
+  omit_zero: true
+price:
+  key: price
+  round: {places: 2, mode: half_even}
+  as: string
+user_permissions:
+  key: permissions
+  fields:
+    code: {key: permission_code, as: int}
+"*": {}
+`))
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, mantau.Schema{
+		"username": mantau.Field{Key: "name"},
+		"email":    mantau.Field{Key: "email", Normalize: mantau.NormalizeEmail, Mask: "first2", OmitZero: true},
+		"price":    mantau.Field{Key: "price", Round: mantau.RoundEven(2), As: "string"},
+		"user_permissions": mantau.Field{
+			Key:   "permissions",
+			Value: mantau.Schema{"code": mantau.Field{Key: "permission_code", As: "int"}},
+		},
+		"*": {},
+	}, schema, "The schema do not match")
+
+	_, err = LoadSchemaYAML([]byte("email:\n  key: email\n  masks: all\n"))
+
+	assert.EqualError(t, err, `Invalid schema: json: unknown field "masks"`)
+
+	_, err = LoadSchemaYAML([]byte("email: [key"))
+
+	assert.Error(t, err, "Invalid YAML should return an error")
+}
+
+func TestMarshalSchemaYAML(t *testing.T) {
+	schema := mantau.Schema{
+		"title":  mantau.Field{Key: "title", Truncate: mantau.Truncate(80, "..."), MarkTruncated: true},
+		"size":   mantau.Field{Key: "size", Convert: mantau.Convert("bytes", "MB"), Round: mantau.Round(1)},
+		"cpu_$1": mantau.Field{KeyPattern: regexp.MustCompile(`^cpu_(\d+)$`)},
+		"author": mantau.First("authors", mantau.Schema{"name": mantau.Field{Key: "name", Classification: mantau.ClassPII}}),
+		"phone":  mantau.Tombstone(),
+	}
+
+	encoded, err := MarshalSchemaYAML(schema)
+
+	assert.NoError(t, err, "Should not return any error")
+
+	loaded, err := LoadSchemaYAML(encoded)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, schema.Hash(), loaded.Hash(), "The schema should round trip")
+}
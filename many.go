@@ -0,0 +1,95 @@
+package mantau
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ManyExplanation describes which source supplied every key of a result merged by ExplainMany
+type ManyExplanation struct {
+	// Result is the merged result
+	Result Result `json:"result"`
+
+	// Keys are the provenances of the output keys ordered by key
+	Keys []KeyProvenance `json:"keys"`
+}
+
+// KeyProvenance describes the source supplying an output key of a merged result
+type KeyProvenance struct {
+	// Key is the output key
+	Key string `json:"key"`
+
+	// Source is the index of the source supplying the value
+	Source int `json:"source"`
+
+	// Conflicts are the indexes of the earlier sources with a different value, replaced by the value of Source
+	Conflicts []int `json:"conflicts,omitempty"`
+}
+
+// TransformMany will transform every source with the schema and merge the objects in order, the key of a later
+// source replaces the key of an earlier one, e.g. an entity read from the database and a partial update from a cache.
+// A nil source is skipped
+func (m *mantau) TransformMany(schema Schema, sources ...interface{}) (Result, error) {
+	result, _, err := m.merge(schema, sources, false)
+
+	return result, err
+}
+
+// ExplainMany works like TransformMany but also returns which source supplied every output key and which
+// earlier sources it replaced with a different value, so conflicts between the sources can be debugged
+func (m *mantau) ExplainMany(schema Schema, sources ...interface{}) (*ManyExplanation, error) {
+	result, provenance, err := m.merge(schema, sources, true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]KeyProvenance, 0, len(provenance))
+
+	for _, p := range provenance {
+		keys = append(keys, *p)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Key < keys[j].Key
+	})
+
+	return &ManyExplanation{Result: result, Keys: keys}, nil
+}
+
+// merge will transform and merge the sources, recording the provenance of every key when explain is set
+func (m *mantau) merge(schema Schema, sources []interface{}, explain bool) (Result, map[string]*KeyProvenance, error) {
+	result := Result{}
+	var provenance map[string]*KeyProvenance
+
+	if explain {
+		provenance = map[string]*KeyProvenance{}
+	}
+
+	for i, src := range sources {
+		object, err := m.transformObject(src, schema)
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("Source %d: %w", i, err)
+		}
+
+		for key, value := range object {
+			if explain {
+				p, ok := provenance[key]
+
+				if !ok {
+					p = &KeyProvenance{Key: key}
+					provenance[key] = p
+				} else if !equalValues(result[key], value) {
+					p.Conflicts = append(p.Conflicts, p.Source)
+				}
+
+				p.Source = i
+			}
+
+			result[key] = value
+		}
+	}
+
+	return result, provenance, nil
+}
@@ -0,0 +1,44 @@
+package mantau
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformMany(t *testing.T) {
+	schema := Schema{
+		"username":  Field{Key: "name"},
+		"useremail": Field{Key: "email"},
+		"phone":     Field{Key: "phone"},
+	}
+
+	stored := User{Name: "John doe", Email: "john@example.com", Phone: "555"}
+	cached := map[string]interface{}{"name": "John doe", "email": "john@example.org"}
+	update := map[string]interface{}{"phone": "556"}
+
+	result, err := New().TransformMany(schema, stored, nil, cached, update)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"username": "John doe", "useremail": "john@example.org", "phone": "556"}, result, "The later sources should replace the keys")
+
+	explanation, err := New().ExplainMany(schema, stored, nil, cached, update)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, result, explanation.Result, "The result should be the merged result")
+	assert.Equal(t, []KeyProvenance{
+		{Key: "phone", Source: 3, Conflicts: []int{0}},
+		{Key: "useremail", Source: 2, Conflicts: []int{0}},
+		{Key: "username", Source: 2},
+	}, explanation.Keys, "The provenance do not match")
+
+	encoded, err := json.Marshal(explanation.Keys[0])
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, `{"key":"phone","source":3,"conflicts":[0]}`, string(encoded))
+
+	_, err = New().TransformMany(schema, stored, []User{stored})
+
+	assert.EqualError(t, err, "Source 1: Only objects can be compared or merged")
+}
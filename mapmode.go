@@ -0,0 +1,120 @@
+package mantau
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// transformMapAs will emit a map source with the given map mode.
+// Map values are transformed with the given schema
+func (m *mantau) transformMapAs(mode MapMode, src interface{}, schema Schema) (interface{}, error) {
+	if src == nil {
+		return nil, nil
+	}
+
+	if mode == MapTimeSeries {
+		return m.transformTimeSeries(src, schema)
+	}
+
+	entries := m.mapEntries(src)
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].key < entries[j].key
+	})
+
+	switch mode {
+	case MapEntries:
+		result := make([]Result, 0, len(entries))
+
+		for _, e := range entries {
+			m.enter(e.key, e.key)
+			v, err := m.transformValue(e.value, schema)
+			m.leave()
+
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, Result{"key": e.key, "value": v})
+		}
+
+		return result, nil
+	case MapInvert:
+		result := Result{}
+
+		for _, e := range entries {
+			if !m.shouldSkipTransform(e.value) {
+				return nil, fmt.Errorf("Cannot invert the map, the value of %q is %T", e.key, e.value)
+			}
+
+			key := queryString(m.getValue(e.value).Interface())
+
+			// keys sharing a value follow Options.Collisions, in the order of the keys
+			if ok, err := m.checkCollision(result, key, key, e.key); !ok {
+				if err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+
+			result[key] = e.key
+		}
+
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("Unknown map mode %q", mode)
+}
+
+// transformTimeSeries will emit a map keyed by time.Time as a list of time and value objects in chronological order
+func (m *mantau) transformTimeSeries(src interface{}, schema Schema) (interface{}, error) {
+	value := m.getValue(src)
+	keys := value.MapKeys()
+	times := make([]time.Time, len(keys))
+
+	for i, key := range keys {
+		t, ok := key.Interface().(time.Time)
+
+		if !ok {
+			return nil, fmt.Errorf("Cannot emit a time series, the key %v is %s", key.Interface(), key.Type())
+		}
+
+		times[i] = t
+	}
+
+	sort.Sort(byTime{keys, times})
+
+	result := make([]Result, 0, len(keys))
+
+	for _, key := range keys {
+		formatted := formatMapKey(key, m.opt.TimeKeyLayout)
+
+		m.enter(formatted, formatted)
+		v, err := m.transformValue(value.MapIndex(key).Interface(), schema)
+		m.leave()
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, Result{"time": formatted, "value": v})
+	}
+
+	return result, nil
+}
+
+// byTime sorts map keys by their time
+type byTime struct {
+	keys  []reflect.Value
+	times []time.Time
+}
+
+func (b byTime) Len() int           { return len(b.keys) }
+func (b byTime) Less(i, j int) bool { return b.times[i].Before(b.times[j]) }
+func (b byTime) Swap(i, j int) {
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+	b.times[i], b.times[j] = b.times[j], b.times[i]
+}
@@ -0,0 +1,142 @@
+package mantau
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapAs(t *testing.T) {
+	m := New()
+
+	data := map[string]interface{}{
+		"attributes": map[string]interface{}{"color": "red", "size": "XL"},
+		"codes":      map[string]int{"admin": 0, "customer": 1},
+		"dimensions": map[string]interface{}{
+			"box": map[string]interface{}{"width": 10, "height": 20},
+		},
+	}
+
+	result, err := m.Transform(data, Schema{
+		"attributes": Field{Key: "attributes", MapAs: MapEntries},
+		"roles":      Field{Key: "codes", MapAs: MapInvert},
+		"dimensions": Field{
+			Key:   "dimensions",
+			MapAs: MapEntries,
+			Value: Schema{"w": Field{Key: "width"}},
+		},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"attributes": []Result{
+			{"key": "color", "value": "red"},
+			{"key": "size", "value": "XL"},
+		},
+		"roles": Result{"0": "admin", "1": "customer"},
+		"dimensions": []Result{
+			{"key": "box", "value": Result{"w": 10}},
+		},
+	}, result, "The result do not match")
+
+	_, err = m.Transform(data, Schema{
+		"dimensions": Field{Key: "dimensions", MapAs: MapInvert},
+	})
+
+	assert.Error(t, err, "Inverting nested values should return error")
+}
+
+func TestMapInvertDuplicates(t *testing.T) {
+	data := map[string]interface{}{"codes": map[string]int{"admin": 0, "root": 0, "customer": 1}}
+	schema := Schema{"roles": Field{Key: "codes", MapAs: MapInvert}}
+
+	_, err := New().Transform(data, schema)
+
+	assert.True(t, errors.Is(err, ErrDuplicateKey), "Keys sharing a value should return an error")
+	assert.EqualError(t, err, `roles.0: Output key "0" produced from "root" is already in the result`)
+
+	result, err := New().With(WithCollisions(CollisionKeep)).Transform(data, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"roles": Result{"0": "admin", "1": "customer"}}, result, "The first key should be kept")
+
+	result, err = New().With(WithCollisions(CollisionOverride)).Transform(data, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"roles": Result{"0": "root", "1": "customer"}}, result, "The last key should override")
+}
+
+func TestTimeMapKeys(t *testing.T) {
+	day := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	data := map[string]interface{}{
+		"metrics": map[time.Time]int{
+			day.Add(24 * time.Hour): 7,
+			day:                     5,
+		},
+	}
+
+	result, err := New().Transform(data, Schema{
+		"metrics": Field{Key: "metrics", Value: Schema{"*": Field{}}},
+		"series":  Field{Key: "metrics", MapAs: MapTimeSeries},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"metrics": Result{"2021-03-01T00:00:00Z": 5, "2021-03-02T00:00:00Z": 7},
+		"series": []Result{
+			{"time": "2021-03-01T00:00:00Z", "value": 5},
+			{"time": "2021-03-02T00:00:00Z", "value": 7},
+		},
+	}, result, "The result do not match")
+
+	result, err = New().With(WithTimeKeyLayout("2006-01-02")).Transform(data, Schema{
+		"series": Field{Key: "metrics", MapAs: MapTimeSeries},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"series": []Result{
+			{"time": "2021-03-01", "value": 5},
+			{"time": "2021-03-02", "value": 7},
+		},
+	}, result, "The result do not match")
+
+	_, err = New().Transform(map[string]interface{}{"codes": map[string]int{"a": 1}}, Schema{
+		"codes": Field{Key: "codes", MapAs: MapTimeSeries},
+	})
+
+	assert.Error(t, err, "Should return an error when the keys are not times")
+}
+
+func TestSortMapKeys(t *testing.T) {
+	src := map[string]interface{}{}
+
+	for i := 0; i < 20; i++ {
+		src[fmt.Sprintf("key%02d", i)] = i
+	}
+
+	order := func(m *mantau) []interface{} {
+		seen := []interface{}{}
+
+		_, err := m.Transform(src, Schema{"*": Field{Transform: func(value interface{}) (interface{}, error) {
+			seen = append(seen, value)
+			return value, nil
+		}}})
+
+		assert.NoError(t, err, "Should not return any error")
+
+		return seen
+	}
+
+	sorted := order(New().With(WithSortMapKeys(true)))
+
+	for i, value := range sorted {
+		assert.Equal(t, i, value, "The keys should be transformed in sorted order")
+	}
+
+	assert.Len(t, sorted, 20)
+}
@@ -0,0 +1,336 @@
+package mantau
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// fieldDefinition is the encoded form of a Field, used to store schemas in configuration files
+type fieldDefinition struct {
+	Key              string                                 `json:"key,omitempty"`
+	Fields           map[string]*fieldDefinition            `json:"fields,omitempty"`
+	Keys             map[string]map[string]*fieldDefinition `json:"keys,omitempty"`
+	As               string                                 `json:"as,omitempty"`
+	OmitZero         bool                                   `json:"omit_zero,omitempty"`
+	Required         bool                                   `json:"required,omitempty"`
+	Classification   Classification                         `json:"class,omitempty"`
+	Deprecated       string                                 `json:"deprecated,omitempty"`
+	Normalize        Normalizer                             `json:"normalize,omitempty"`
+	Convert          *conversionDefinition                  `json:"convert,omitempty"`
+	Date             DateStyle                              `json:"date,omitempty"`
+	Round            *roundingDefinition                    `json:"round,omitempty"`
+	Mask             string                                 `json:"mask,omitempty"`
+	LargeInts        LargeIntPolicy                         `json:"large_ints,omitempty"`
+	EmptyCollections EmptyPolicy                            `json:"empty_collections,omitempty"`
+	NonFinite        NonFinitePolicy                        `json:"non_finite,omitempty"`
+	NilNested        NilPolicy                              `json:"nil_nested,omitempty"`
+	Pick             Position                               `json:"pick,omitempty"`
+	Pluck            string                                 `json:"pluck,omitempty"`
+	KeyPattern       string                                 `json:"key_pattern,omitempty"`
+	IndexBy          string                                 `json:"index_by,omitempty"`
+	Include          string                                 `json:"include,omitempty"`
+	Flatten          bool                                   `json:"flatten,omitempty"`
+	Collisions       CollisionPolicy                        `json:"collisions,omitempty"`
+	MaxElements      int                                    `json:"max_elements,omitempty"`
+	Truncate         *truncationDefinition                  `json:"truncate,omitempty"`
+	MarkTruncated    bool                                   `json:"mark_truncated,omitempty"`
+	MapAs            MapMode                                `json:"map_as,omitempty"`
+	SampleRate       float64                                `json:"sample_rate,omitempty"`
+	SampleBy         string                                 `json:"sample_by,omitempty"`
+	XML              *xmlDefinition                         `json:"xml,omitempty"`
+	Money            string                                 `json:"money,omitempty"`
+	Flag             string                                 `json:"flag,omitempty"`
+	Drop             bool                                   `json:"drop,omitempty"`
+	Tombstone        bool                                   `json:"tombstone,omitempty"`
+}
+
+// conversionDefinition is the encoded form of a Conversion
+type conversionDefinition struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// roundingDefinition is the encoded form of a Rounding
+type roundingDefinition struct {
+	Places int          `json:"places"`
+	Mode   RoundingMode `json:"mode,omitempty"`
+}
+
+// truncationDefinition is the encoded form of a Truncation
+type truncationDefinition struct {
+	Length   int    `json:"length"`
+	Ellipsis string `json:"ellipsis,omitempty"`
+}
+
+// xmlDefinition is the encoded form of an XMLPlacement
+type xmlDefinition struct {
+	Name string `json:"name,omitempty"`
+	Attr bool   `json:"attr,omitempty"`
+	Item string `json:"item,omitempty"`
+}
+
+// LoadSchemaJSON will decode a schema stored as JSON, e.g. in a configuration file, see Schema.MarshalJSON.
+// Every field is an object of it's options named in snake case, a nested schema is stored under "fields"
+// and the schemas of a map per key under "keys". Unknown options return an error
+func LoadSchemaJSON(data []byte) (Schema, error) {
+	schema := Schema{}
+
+	if err := schema.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// MarshalJSON will encode the schema, so it can be stored in a configuration file and loaded with LoadSchemaJSON.
+// Fields with a function e.g. Transform, Compute or Const cannot be encoded and return an error
+func (s Schema) MarshalJSON() ([]byte, error) {
+	definitions, err := s.definitions("")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(definitions)
+}
+
+// UnmarshalJSON will decode a schema encoded with MarshalJSON, replacing the entries of the schema
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	definitions := map[string]*fieldDefinition{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&definitions); err != nil {
+		return fmt.Errorf("Invalid schema: %v", err)
+	}
+
+	schema, err := schemaOf("", definitions)
+
+	if err != nil {
+		return err
+	}
+
+	*s = schema
+
+	return nil
+}
+
+// definitions will convert the fields of the schema into their encoded form
+func (s Schema) definitions(path string) (map[string]*fieldDefinition, error) {
+	definitions := make(map[string]*fieldDefinition, len(s))
+
+	for key, field := range s {
+		definition, err := field.definition(joinPath(path, key))
+
+		if err != nil {
+			return nil, err
+		}
+
+		definitions[key] = definition
+	}
+
+	return definitions, nil
+}
+
+// definition will convert the field into it's encoded form
+func (f Field) definition(path string) (*fieldDefinition, error) {
+	if f.Compute != nil || f.Transform != nil || f.inject != nil || f.finalize != nil || f.pipe != nil {
+		return nil, fmt.Errorf("Cannot encode the field %q, functions cannot be encoded", path)
+	}
+
+	if f.cache != nil {
+		return nil, fmt.Errorf("Cannot encode the field %q, cached fields cannot be encoded", path)
+	}
+
+	d := &fieldDefinition{
+		Key:              f.Key,
+		As:               f.As,
+		OmitZero:         f.OmitZero,
+		Required:         f.Required,
+		Classification:   f.Classification,
+		Deprecated:       f.Deprecated,
+		Normalize:        f.Normalize,
+		Date:             f.Date,
+		Mask:             f.Mask,
+		LargeInts:        f.LargeInts,
+		EmptyCollections: f.EmptyCollections,
+		NonFinite:        f.NonFinite,
+		NilNested:        f.NilNested,
+		Pick:             f.Pick,
+		Pluck:            f.Pluck,
+		IndexBy:          f.IndexBy,
+		Include:          f.Include,
+		Flatten:          f.Flatten,
+		Collisions:       f.Collisions,
+		MaxElements:      f.MaxElements,
+		MarkTruncated:    f.MarkTruncated,
+		MapAs:            f.MapAs,
+		SampleRate:       f.SampleRate,
+		SampleBy:         f.SampleBy,
+		Money:            f.money,
+		Flag:             f.flag,
+		Drop:             f.drop,
+		Tombstone:        f.tombstone,
+	}
+
+	if f.Convert != nil {
+		d.Convert = &conversionDefinition{From: f.Convert.From, To: f.Convert.To}
+	}
+
+	if f.Round != nil {
+		d.Round = &roundingDefinition{Places: f.Round.Places, Mode: f.Round.Mode}
+	}
+
+	if f.Truncate != nil {
+		d.Truncate = &truncationDefinition{Length: f.Truncate.Length, Ellipsis: f.Truncate.Ellipsis}
+	}
+
+	if f.KeyPattern != nil {
+		d.KeyPattern = f.KeyPattern.String()
+	}
+
+	if f.XML != (XMLPlacement{}) {
+		d.XML = &xmlDefinition{Name: f.XML.Name, Attr: f.XML.Attr, Item: f.XML.Item}
+	}
+
+	var err error
+
+	switch nested := f.Value.(type) {
+	case nil:
+	case Schema:
+		d.Fields, err = nested.definitions(path)
+	case map[string]Schema:
+		d.Keys = make(map[string]map[string]*fieldDefinition, len(nested))
+
+		for name, schema := range nested {
+			if d.Keys[name], err = schema.definitions(path + "[" + name + "]"); err != nil {
+				break
+			}
+		}
+	default:
+		err = fmt.Errorf("Cannot encode the field %q, a %T nested schema cannot be encoded", path, f.Value)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// schemaOf will convert encoded fields into a schema
+func schemaOf(path string, definitions map[string]*fieldDefinition) (Schema, error) {
+	schema := make(Schema, len(definitions))
+
+	for key, definition := range definitions {
+		if key == finalizeKey {
+			return nil, fmt.Errorf("Invalid schema: the key %q is reserved", joinPath(path, key))
+		}
+
+		if definition == nil {
+			schema[key] = Field{}
+			continue
+		}
+
+		field, err := definition.field(joinPath(path, key))
+
+		if err != nil {
+			return nil, err
+		}
+
+		schema[key] = field
+	}
+
+	return schema, nil
+}
+
+// field will convert the encoded field into a Field
+func (d *fieldDefinition) field(path string) (Field, error) {
+	f := Field{
+		Key:              d.Key,
+		As:               d.As,
+		OmitZero:         d.OmitZero,
+		Required:         d.Required,
+		Classification:   d.Classification,
+		Deprecated:       d.Deprecated,
+		Normalize:        d.Normalize,
+		Date:             d.Date,
+		Mask:             d.Mask,
+		LargeInts:        d.LargeInts,
+		EmptyCollections: d.EmptyCollections,
+		NonFinite:        d.NonFinite,
+		NilNested:        d.NilNested,
+		Pick:             d.Pick,
+		Pluck:            d.Pluck,
+		IndexBy:          d.IndexBy,
+		Include:          d.Include,
+		Flatten:          d.Flatten,
+		Collisions:       d.Collisions,
+		MaxElements:      d.MaxElements,
+		MarkTruncated:    d.MarkTruncated,
+		MapAs:            d.MapAs,
+		SampleRate:       d.SampleRate,
+		SampleBy:         d.SampleBy,
+		money:            d.Money,
+		flag:             d.Flag,
+		drop:             d.Drop,
+		tombstone:        d.Tombstone,
+	}
+
+	if d.Convert != nil {
+		f.Convert = Convert(d.Convert.From, d.Convert.To)
+	}
+
+	if d.Round != nil {
+		f.Round = &Rounding{Places: d.Round.Places, Mode: d.Round.Mode}
+	}
+
+	if d.Truncate != nil {
+		f.Truncate = Truncate(d.Truncate.Length, d.Truncate.Ellipsis)
+	}
+
+	if d.XML != nil {
+		f.XML = XMLPlacement{Name: d.XML.Name, Attr: d.XML.Attr, Item: d.XML.Item}
+	}
+
+	if d.KeyPattern != "" {
+		pattern, err := regexp.Compile(d.KeyPattern)
+
+		if err != nil {
+			return Field{}, fmt.Errorf("Invalid key pattern of the field %q: %v", path, err)
+		}
+
+		f.KeyPattern = pattern
+	}
+
+	switch {
+	case d.Fields != nil && d.Keys != nil:
+		return Field{}, fmt.Errorf("The field %q cannot have both fields and keys", path)
+	case d.Fields != nil:
+		nested, err := schemaOf(path, d.Fields)
+
+		if err != nil {
+			return Field{}, err
+		}
+
+		f.Value = nested
+	case d.Keys != nil:
+		keyed := make(map[string]Schema, len(d.Keys))
+
+		for name, definitions := range d.Keys {
+			nested, err := schemaOf(path+"["+name+"]", definitions)
+
+			if err != nil {
+				return Field{}, err
+			}
+
+			keyed[name] = nested
+		}
+
+		f.Value = keyed
+	}
+
+	return f, nil
+}
@@ -0,0 +1,72 @@
+package mantau
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadSchemaJSON(t *testing.T) {
+	schema, err := LoadSchemaJSON([]byte(`{
+		"username": {"key": "name"},
+		"email": {"key": "email", "normalize": "email", "mask": "first2", "omit_zero": true},
+		"user_permissions": {
+			"key": "permissions",
+			"fields": {"code": {"key": "permission_code", "as": "string"}}
+		},
+		"phone": {"drop": true}
+	}`))
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Schema{
+		"username": Field{Key: "name"},
+		"email":    Field{Key: "email", Normalize: NormalizeEmail, Mask: "first2", OmitZero: true},
+		"user_permissions": Field{
+			Key:   "permissions",
+			Value: Schema{"code": Field{Key: "permission_code", As: "string"}},
+		},
+		"phone": Drop(),
+	}, schema, "The schema do not match")
+
+	_, err = LoadSchemaJSON([]byte(`{"email": {"key": "email", "mask_": "all"}}`))
+
+	assert.EqualError(t, err, `Invalid schema: json: unknown field "mask_"`)
+
+	_, err = LoadSchemaJSON([]byte(`{"cpu": {"key_pattern": "(cpu"}}`))
+
+	assert.Error(t, err, "An invalid key pattern should return an error")
+}
+
+func TestSchemaMarshalJSON(t *testing.T) {
+	schema := Schema{
+		"price":    Field{Key: "price", Round: RoundEven(2), As: "string", Convert: Convert("cents", "dollars")},
+		"title":    Field{Key: "title", Truncate: Truncate(80, "..."), MarkTruncated: true, Date: DateLong},
+		"cpu_$1":   Field{KeyPattern: regexp.MustCompile(`^cpu_(\d+)$`)},
+		"total":    Money("total", "currency"),
+		"beta":     FlagGate("beta", Field{Key: "beta", XML: XMLPlacement{Attr: true}}),
+		"phone":    Tombstone(),
+		"*":        Field{},
+		"author":   First("authors", Schema{"name": Field{Key: "name", Classification: ClassPII}}),
+		"settings": Field{Key: "settings", Value: map[string]Schema{"billing": {"plan": Field{Key: "plan"}}}},
+	}
+
+	encoded, err := json.Marshal(schema)
+
+	assert.NoError(t, err, "Should not return any error")
+
+	loaded, err := LoadSchemaJSON(encoded)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, schema.Hash(), loaded.Hash(), "The schema should round trip")
+
+	_, err = json.Marshal(Schema{
+		"user": Field{Key: "user", Value: Schema{"upper": Field{Key: "name", Transform: func(v interface{}) (interface{}, error) {
+			return v, nil
+		}}}},
+	})
+
+	assert.Error(t, err, "A function should not be encoded")
+	assert.Contains(t, err.Error(), `Cannot encode the field "user.upper", functions cannot be encoded`)
+}
@@ -0,0 +1,48 @@
+package mantau
+
+import "reflect"
+
+// memoKey identifies a pointer transformed with a schema
+type memoKey struct {
+	pointer uintptr
+	typ     reflect.Type
+	schema  uintptr
+}
+
+// memoize will transform the value of a pointer once per schema and reuse the result
+// every time the same pointer is found again in the same transformation. The reused results are
+// counted against Options.MaxResultBytes
+func (m *mantau) memoize(src interface{}, schema Schema, fn func() (interface{}, error)) (interface{}, error) {
+	if m.state == nil || !m.opt.Memoize {
+		return fn()
+	}
+
+	key := memoKey{
+		pointer: reflect.ValueOf(src).Pointer(),
+		typ:     reflect.TypeOf(src),
+		schema:  reflect.ValueOf(schema).Pointer(),
+	}
+
+	if result, ok := m.state.memo[key]; ok {
+		if m.reuseSize(result) {
+			return result, nil
+		}
+
+		// The result doesn't fit in Options.MaxResultBytes, transform it again to truncate it
+		return fn()
+	}
+
+	result, err := fn()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if m.state.memo == nil {
+		m.state.memo = map[memoKey]interface{}{}
+	}
+
+	m.state.memo[key] = result
+
+	return result, nil
+}
@@ -0,0 +1,49 @@
+package mantau
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoize(t *testing.T) {
+	calls := 0
+	author := &Author{FirstName: "John", LastName: "Doe"}
+	books := []Book{
+		{Title: "First", Author: author},
+		{Title: "Second", Author: author},
+		{Title: "Third", Author: &Author{FirstName: "Jane", LastName: "Doe"}},
+	}
+
+	schema := Schema{
+		"title": Field{Key: "title"},
+		"author": Field{
+			Key: "author",
+			Value: Schema{
+				"name": Field{Key: "first_name"},
+			}.WithFinalize(func(r Result) (Result, error) {
+				calls++
+				return r, nil
+			}),
+		},
+	}
+
+	want := []Result{
+		{"title": "First", "author": Result{"name": "John"}},
+		{"title": "Second", "author": Result{"name": "John"}},
+		{"title": "Third", "author": Result{"name": "Jane"}},
+	}
+
+	result, err := New().Transform(books, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, want, result, "The result do not match")
+	assert.Equal(t, 3, calls, "Every author should be transformed without memoization")
+
+	calls = 0
+	result, err = New().With(WithMemoize(true)).Transform(books, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, want, result, "The result do not match")
+	assert.Equal(t, 2, calls, "The shared author should be transformed once")
+}
@@ -0,0 +1,103 @@
+package mantau
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// currencyExponents are the number of minor unit digits of the currencies that don't use 2 digits
+var currencyExponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// Money will create a field emitting an integer amount of minor units (e.g. cents) and the currency
+// read from the sibling currency key as {"amount": "12.34", "currency": "USD"}. The amount is a decimal
+// string with the number of digits of the currency, so it's not rounded by JSON decoders
+func Money(amountKey, currencyKey string) Field {
+	return Field{Key: amountKey, money: currencyKey}
+}
+
+// transformMoney will read the currency of a Money field from the parent and format the amount
+func (m *mantau) transformMoney(currencyKey string, amount, parent interface{}) (interface{}, error) {
+	if isNilValue(amount) {
+		return nil, nil
+	}
+
+	sibling, err := m.sibling(parent, currencyKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	currency, ok := sibling.(string)
+
+	if !ok {
+		return nil, fmt.Errorf("Cannot find the %q currency", currencyKey)
+	}
+
+	currency = strings.ToUpper(currency)
+	formatted, err := formatMinorUnits(m.getValue(amount), currency)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return Result{"amount": formatted, "currency": currency}, nil
+}
+
+// formatMinorUnits will format an integer amount of minor units as a decimal string
+func formatMinorUnits(amount reflect.Value, currency string) (string, error) {
+	exponent, ok := currencyExponents[currency]
+
+	if !ok {
+		exponent = 2
+	}
+
+	var digits string
+	negative := false
+
+	switch {
+	case isSigned(amount.Kind()):
+		n := amount.Int()
+		negative = n < 0
+		digits = strings.TrimPrefix(strconv.FormatInt(n, 10), "-")
+	case isUnsigned(amount.Kind()):
+		digits = strconv.FormatUint(amount.Uint(), 10)
+	default:
+		return "", fmt.Errorf("Money amounts should be integer minor units, got %s", amount.Type())
+	}
+
+	if exponent > 0 {
+		if len(digits) <= exponent {
+			digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+		}
+
+		digits = digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+	}
+
+	if negative {
+		digits = "-" + digits
+	}
+
+	return digits, nil
+}
+
+// sibling will read a value of the parent source value by it's key, without recording it in the state
+func (m *mantau) sibling(parent interface{}, key string) (interface{}, error) {
+	lookup := *m
+	lookup.state = nil
+
+	value, err := lookup.transformValue(parent, Schema{"value": Field{Key: key}})
+
+	if err != nil {
+		return nil, err
+	}
+
+	object, _ := value.(Result)
+
+	return object["value"], nil
+}
@@ -0,0 +1,42 @@
+package mantau
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Price struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func TestMoney(t *testing.T) {
+	cases := []struct {
+		price Price
+		want  Result
+	}{
+		{Price{1234, "usd"}, Result{"amount": "12.34", "currency": "USD"}},
+		{Price{5, "EUR"}, Result{"amount": "0.05", "currency": "EUR"}},
+		{Price{-150, "EUR"}, Result{"amount": "-1.50", "currency": "EUR"}},
+		{Price{1234, "JPY"}, Result{"amount": "1234", "currency": "JPY"}},
+		{Price{1234, "KWD"}, Result{"amount": "1.234", "currency": "KWD"}},
+	}
+
+	schema := Schema{"price": Money("amount", "currency")}
+
+	for _, c := range cases {
+		result, err := New().Transform(c.price, schema)
+
+		assert.NoError(t, err, "Should not return any error")
+		assert.Equal(t, Result{"price": c.want}, result, "The result do not match")
+	}
+
+	_, err := New().Transform(map[string]interface{}{"amount": 1.5, "currency": "USD"}, schema)
+
+	assert.Error(t, err, "Non-integer amounts should return an error")
+
+	_, err = New().Transform(map[string]interface{}{"amount": 150}, schema)
+
+	assert.Error(t, err, "Missing currency should return an error")
+}
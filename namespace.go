@@ -0,0 +1,56 @@
+package mantau
+
+import "strings"
+
+// namespaceEntries will add the namespaced entries requested by the schema to the map entries.
+// A dotted key like "u.name" is resolved from the "u_name" entry, while a key with a nested schema
+// that doesn't exist in the map groups every entry prefixed by it into an object with the prefix stripped
+func (m *mantau) namespaceEntries(entries []entry, schema Schema) []entry {
+	sep := m.opt.NamespaceSeparator
+	index := make(map[string]interface{}, len(entries))
+
+	for _, e := range entries {
+		index[e.key] = e.value
+	}
+
+	for key, field := range schema {
+		if field.isReserved() || key == wildcardKey || field.KeyPattern != nil {
+			continue
+		}
+
+		if _, ok := index[field.Key]; ok {
+			continue
+		}
+
+		if strings.Contains(field.Key, ".") {
+			flat := strings.Replace(field.Key, ".", sep, -1)
+
+			if value, ok := index[flat]; ok {
+				entries = append(entries, entry{key: field.Key, value: value})
+				index[field.Key] = value
+			}
+
+			continue
+		}
+
+		if field.Value == nil {
+			continue
+		}
+
+		prefix := field.Key + sep
+		group := map[string]interface{}{}
+
+		for _, e := range entries {
+			if strings.HasPrefix(e.key, prefix) {
+				group[strings.TrimPrefix(e.key, prefix)] = e.value
+			}
+		}
+
+		if len(group) > 0 {
+			entries = append(entries, entry{key: field.Key, value: group})
+			index[field.Key] = group
+		}
+	}
+
+	return entries
+}
@@ -0,0 +1,43 @@
+package mantau
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespacedKeys(t *testing.T) {
+	m := New()
+	m.SetOpt(&Options{Hook: "json", NamespaceSeparator: "_"})
+
+	rows := []map[string]interface{}{
+		{"u_name": "John doe", "u_email": "johndoe@example.com", "o_id": 1, "o_total": 10.5},
+		{"u_name": "Jane doe", "u_email": "janedoe@example.com", "o_id": 2, "o_total": 7},
+	}
+
+	result, err := m.Transform(rows, Schema{
+		"username": Field{Key: "u.name"},
+		"order": Field{
+			Key: "o",
+			Value: Schema{
+				"id":    Field{Key: "id"},
+				"total": Field{Key: "total"},
+			},
+		},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, []Result{
+		{"username": "John doe", "order": Result{"id": 1, "total": 10.5}},
+		{"username": "Jane doe", "order": Result{"id": 2, "total": 7}},
+	}, result, "The result do not match")
+
+	m.SetOpt(&Options{Hook: "json"})
+
+	result, err = m.Transform(rows[0], Schema{
+		"username": Field{Key: "u.name"},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{}, result, "Namespaces should be disabled without a separator")
+}
@@ -0,0 +1,87 @@
+package mantau
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy derives the key of a struct field without a tag from it's Go field name, see Options.Naming
+type NamingStrategy interface {
+	Name(field string) string
+}
+
+// NamingFunc is a function implementing NamingStrategy
+type NamingFunc func(field string) string
+
+// Name will call the function
+func (f NamingFunc) Name(field string) string {
+	return f(field)
+}
+
+var (
+	// SnakeCase will name fields in snake case e.g. "UserID" becomes "user_id"
+	SnakeCase NamingStrategy = NamingFunc(snakeCase)
+
+	// LowerCamelCase will name fields in lower camel case e.g. "UserID" becomes "userID"
+	LowerCamelCase NamingStrategy = NamingFunc(lowerCamelCase)
+)
+
+// snakeCase will convert a Go field name into snake case, acronyms are kept together e.g. "HTTPServer" becomes "http_server"
+func snakeCase(field string) string {
+	runes := []rune(field)
+	var b strings.Builder
+
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
+
+// lowerCamelCase will lower the leading upper case letters of a Go field name, except the first letter
+// of the next word e.g. "HTTPServer" becomes "httpServer"
+func lowerCamelCase(field string) string {
+	runes := []rune(field)
+
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+
+		runes[i] = unicode.ToLower(runes[i])
+	}
+
+	return string(runes)
+}
+
+// structFieldName will return the tag of a struct field or, when it doesn't have a tag, the name derived
+// by Options.Naming. Unexported fields without a tag and fields tagged "-" are skipped with an empty name,
+// like encoding/json does
+func (m *mantau) structFieldName(t reflect.Type, i int) (string, error) {
+	field := t.Field(i)
+	tag, err := m.tagLookup(t, i)
+
+	if tag == "-" {
+		return "", nil
+	}
+
+	if err == nil || m.opt.Naming == nil {
+		return tag, err
+	}
+
+	if field.PkgPath != "" {
+		return "", nil
+	}
+
+	return m.opt.Naming.Name(field.Name), nil
+}
@@ -0,0 +1,55 @@
+package mantau
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type UntaggedUser struct {
+	UserID     int
+	FullName   string
+	HTTPServer string
+	Email      string `json:"contact"`
+	secret     string
+}
+
+func TestNamingStrategies(t *testing.T) {
+	cases := map[string][2]string{
+		"UserID":     {"user_id", "userID"},
+		"FullName":   {"full_name", "fullName"},
+		"HTTPServer": {"http_server", "httpServer"},
+		"ID":         {"id", "id"},
+		"Address2":   {"address2", "address2"},
+	}
+
+	for field, want := range cases {
+		assert.Equal(t, want[0], SnakeCase.Name(field), "The snake case name do not match")
+		assert.Equal(t, want[1], LowerCamelCase.Name(field), "The lower camel case name do not match")
+	}
+}
+
+func TestNaming(t *testing.T) {
+	src := UntaggedUser{UserID: 1, FullName: "John doe", HTTPServer: "nginx", Email: "john@doe.com", secret: "x"}
+	schema := Schema{
+		"id":     Field{Key: "user_id"},
+		"name":   Field{Key: "full_name"},
+		"server": Field{Key: "http_server"},
+		"email":  Field{Key: "contact"},
+	}
+
+	_, err := New().Transform(src, schema)
+
+	assert.Error(t, err, "Untagged fields should return an error without a naming strategy")
+
+	result, err := New().With(WithNaming(SnakeCase)).Transform(src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"id": 1, "name": "John doe", "server": "nginx", "email": "john@doe.com"}, result, "The result do not match")
+
+	custom := NamingFunc(func(field string) string { return "x_" + field })
+	result, err = New().With(WithNaming(custom)).Transform(src, Schema{"name": Field{Key: "x_FullName"}})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John doe"}, result, "The result do not match")
+}
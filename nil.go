@@ -0,0 +1,23 @@
+package mantau
+
+import "strings"
+
+// null is emitted for a nil nested object with NilNull. It's a typed nil,
+// so it's kept in the result and encoded as null
+var null = Result(nil)
+
+// keepsNil will check if the nil value of a field is emitted as null. It applies to fields
+// with a nested schema and to dotted keys, where the value is nil when an object in the path is nil
+func (m *mantau) keepsNil(field Field, schema Schema) bool {
+	if schema == nil && field.Value == nil && !strings.Contains(field.Key, ".") {
+		return false
+	}
+
+	policy := field.NilNested
+
+	if policy == "" {
+		policy = m.opt.NilNested
+	}
+
+	return policy == NilNull
+}
@@ -0,0 +1,104 @@
+package mantau
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type NilAddress struct {
+	Code string `json:"code"`
+}
+
+type NilUser struct {
+	Name    string      `json:"name"`
+	Address *NilAddress `json:"address"`
+}
+
+func TestNilNested(t *testing.T) {
+	schema := Schema{
+		"name":    Field{Key: "name"},
+		"address": Field{Key: "address", Value: Schema{"code": Field{Key: "code"}}},
+	}
+
+	result, err := New().Transform(NilUser{Name: "John"}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John"}, result, "A nil nested object should be omitted by default")
+
+	result, err = New().With(WithNilNested(NilNull)).Transform(NilUser{Name: "John"}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John", "address": null}, result, "A nil nested object should be null")
+
+	schema["address"] = Field{Key: "address", Value: schema["address"].Value, NilNested: NilOmit}
+	result, err = New().With(WithNilNested(NilNull)).Transform(NilUser{Name: "John"}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John"}, result, "The field policy should override the option")
+}
+
+func TestNilNestedPath(t *testing.T) {
+	src := map[string]interface{}{
+		"name":    "John",
+		"address": nil,
+	}
+
+	schema := Schema{
+		"name":  Field{Key: "name"},
+		"code":  Field{Key: "address.code"},
+		"other": Field{Key: "other.code"},
+	}
+
+	result, err := New().Transform(src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John"}, result, "The result do not match")
+
+	result, err = New().With(WithNilNested(NilNull)).Transform(src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John", "code": null}, result, "A nil object in the path should be null, a missing one omitted")
+}
+
+func TestKeepNil(t *testing.T) {
+	isActive := false
+
+	type Account struct {
+		Name     string       `json:"name"`
+		Email    *string      `json:"email"`
+		IsActive *bool        `json:"is_active"`
+		Address  *UserAddress `json:"address"`
+		Tags     []string     `json:"tags"`
+	}
+
+	schema := Schema{
+		"name":      Field{Key: "name"},
+		"email":     Field{Key: "email"},
+		"nickname":  Field{Key: "nickname"},
+		"is_active": Field{Key: "is_active"},
+		"tags":      Field{Key: "tags", OmitZero: true},
+		"address": Field{Key: "address", Value: Schema{
+			"postal_code": Field{Key: "postal_code"},
+		}},
+	}
+
+	src := Account{Name: "John", IsActive: &isActive, Address: &UserAddress{}}
+
+	result, err := New().Transform(src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John", "is_active": false}, result, "Nil and zero values should be omitted by default")
+
+	result, err = New().With(WithKeepNil(true)).Transform(src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John", "email": nil, "is_active": false, "address": nil}, result,
+		"Nil values should be kept, except for OmitZero fields")
+
+	result, err = New().With(WithKeepNil(true), WithKeepZero(true)).Transform(src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John", "email": nil, "is_active": false, "address": Result{"postal_code": ""}}, result,
+		"A pointer to a zero struct should be transformed")
+}
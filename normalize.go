@@ -0,0 +1,58 @@
+package mantau
+
+import (
+	"fmt"
+	"strings"
+)
+
+// normalize will normalize a string value, the value is returned as it is when it's not a string
+func (m *mantau) normalize(value interface{}, normalizer Normalizer) (interface{}, error) {
+	s, ok := value.(string)
+
+	if !ok {
+		return value, nil
+	}
+
+	switch normalizer {
+	case NormalizeEmail:
+		return strings.ToLower(strings.TrimSpace(s)), nil
+	case NormalizePhone:
+		return normalizePhone(s, m.opt.DefaultCountryCode)
+	}
+
+	return nil, fmt.Errorf("Unknown normalizer %q", normalizer)
+}
+
+// normalizePhone will format a phone number in E.164 e.g. "+15551234567". A national number starting with 0
+// uses the default country code, an international number can start with "+" or "00"
+func normalizePhone(phone, countryCode string) (string, error) {
+	s := strings.TrimSpace(phone)
+	international := strings.HasPrefix(s, "+")
+
+	var digits strings.Builder
+
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+
+	number := digits.String()
+
+	switch {
+	case international:
+	case strings.HasPrefix(number, "00"):
+		number = number[2:]
+	case countryCode != "":
+		number = strings.TrimPrefix(countryCode, "+") + strings.TrimPrefix(number, "0")
+	default:
+		return "", fmt.Errorf("Cannot normalize %q without a country code", phone)
+	}
+
+	// E.164 numbers have at most 15 digits
+	if len(number) < 8 || len(number) > 15 {
+		return "", fmt.Errorf("Invalid phone number %q", phone)
+	}
+
+	return "+" + number, nil
+}
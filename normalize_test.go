@@ -0,0 +1,45 @@
+package mantau
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Contact struct {
+	Email string `json:"email,normalize=email"`
+	Phone string `json:"phone"`
+}
+
+func TestNormalizePhone(t *testing.T) {
+	cases := map[string]string{
+		"+1 (555) 123-4567": "+15551234567",
+		"0812-3456-789":     "+628123456789",
+		"0062 812 3456 789": "+628123456789",
+	}
+
+	for phone, want := range cases {
+		got, err := normalizePhone(phone, "62")
+
+		assert.NoError(t, err, "Should not return any error")
+		assert.Equal(t, want, got, "The phone number do not match")
+	}
+
+	_, err := normalizePhone("0812-3456-789", "")
+
+	assert.Error(t, err, "National numbers without a country code should return an error")
+
+	_, err = normalizePhone("+1 555", "")
+
+	assert.Error(t, err, "Short numbers should return an error")
+}
+
+func TestNormalize(t *testing.T) {
+	result, err := New().With(WithDefaultCountryCode("62")).Transform(Contact{Email: "  John@Doe.COM ", Phone: "0812-3456-789"}, Schema{
+		"email": Field{Key: "email"},
+		"phone": Field{Key: "phone", Normalize: NormalizePhone, Mask: "last4"},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"email": "john@doe.com", "phone": "*********6789"}, result, "The result do not match")
+}
@@ -0,0 +1,257 @@
+package mantau
+
+import (
+	"context"
+	"sync"
+)
+
+// Option will modify a copy of the instance options, see mantau.With
+type Option func(*Options)
+
+// WithHook will set the struct tag used to match struct fields
+func WithHook(hook string) Option {
+	return func(opt *Options) {
+		opt.Hook = hook
+	}
+}
+
+// WithNamespaceSeparator will set the separator of namespaced map keys
+func WithNamespaceSeparator(sep string) Option {
+	return func(opt *Options) {
+		opt.NamespaceSeparator = sep
+	}
+}
+
+// WithDefaultCountryCode will set the calling code of national phone numbers normalized with NormalizePhone
+func WithDefaultCountryCode(code string) Option {
+	return func(opt *Options) {
+		opt.DefaultCountryCode = code
+	}
+}
+
+// WithTimeKeyLayout will set the layout of time.Time map keys
+func WithTimeKeyLayout(layout string) Option {
+	return func(opt *Options) {
+		opt.TimeKeyLayout = layout
+	}
+}
+
+// WithOnDeprecated will set the function called when a deprecated field is emitted
+func WithOnDeprecated(fn func(ctx context.Context, d Deprecation)) Option {
+	return func(opt *Options) {
+		opt.OnDeprecated = fn
+	}
+}
+
+// WithOnError will set the function called with the detailed error of an error body shaped by Respond
+func WithOnError(fn func(ctx context.Context, err error)) Option {
+	return func(opt *Options) {
+		opt.OnError = fn
+	}
+}
+
+// WithNaming will set how the keys of struct fields without a tag are derived
+func WithNaming(naming NamingStrategy) Option {
+	return func(opt *Options) {
+		opt.Naming = naming
+	}
+}
+
+// WithMaxDepth will set the maximum depth of nested values
+func WithMaxDepth(depth int) Option {
+	return func(opt *Options) {
+		opt.MaxDepth = depth
+	}
+}
+
+// WithLargeInts will set how integers above 2^53 - 1 are emitted
+func WithLargeInts(policy LargeIntPolicy) Option {
+	return func(opt *Options) {
+		opt.LargeInts = policy
+	}
+}
+
+// WithEmptyCollections will set how empty collections are emitted
+func WithEmptyCollections(policy EmptyPolicy) Option {
+	return func(opt *Options) {
+		opt.EmptyCollections = policy
+	}
+}
+
+// WithNonFinite will set how NaN and infinite floats are emitted
+func WithNonFinite(policy NonFinitePolicy) Option {
+	return func(opt *Options) {
+		opt.NonFinite = policy
+	}
+}
+
+// WithNilNested will set how nil nested objects are emitted
+func WithNilNested(policy NilPolicy) Option {
+	return func(opt *Options) {
+		opt.NilNested = policy
+	}
+}
+
+// WithCollectErrors will enable or disable collecting field errors into a *MultiError
+func WithCollectErrors(collect bool) Option {
+	return func(opt *Options) {
+		opt.CollectErrors = collect
+	}
+}
+
+// WithMaxErrors will set the maximum number of collected errors before a transformation stops
+func WithMaxErrors(max int) Option {
+	return func(opt *Options) {
+		opt.MaxErrors = max
+	}
+}
+
+// WithMaxResultBytes will set the maximum estimated size of a result and what happens when it's exceeded
+func WithMaxResultBytes(max int, policy LimitPolicy) Option {
+	return func(opt *Options) {
+		opt.MaxResultBytes = max
+		opt.ResultLimit = policy
+	}
+}
+
+// WithMismatch will set the nested schema mismatch policy
+func WithMismatch(policy MismatchPolicy) Option {
+	return func(opt *Options) {
+		opt.Mismatch = policy
+	}
+}
+
+// WithFlags will set the provider of the feature flags used by FlagGate fields
+func WithFlags(flags FlagProvider) Option {
+	return func(opt *Options) {
+		opt.Flags = flags
+	}
+}
+
+// WithClearance will set the clearance of the caller and how fields classified above it are enforced
+func WithClearance(clearance Classification, enforcement EnforcementPolicy) Option {
+	return func(opt *Options) {
+		opt.Clearance = clearance
+		opt.Enforcement = enforcement
+	}
+}
+
+// WithMemoize will enable transforming shared pointers once per transformation
+func WithMemoize(memoize bool) Option {
+	return func(opt *Options) {
+		opt.Memoize = memoize
+	}
+}
+
+// WithVerifySource will enable checking that the transformation doesn't change the source
+func WithVerifySource(verify bool) Option {
+	return func(opt *Options) {
+		opt.VerifySource = verify
+	}
+}
+
+// WithSharedSchemaCache will enable caching the matched schema fields of struct types across instances
+func WithSharedSchemaCache(shared bool) Option {
+	return func(opt *Options) {
+		opt.SharedSchemaCache = shared
+	}
+}
+
+// WithKeepNil will enable emitting the keys of nil values
+func WithKeepNil(keep bool) Option {
+	return func(opt *Options) {
+		opt.KeepNil = keep
+	}
+}
+
+// WithKeepZero will enable transforming pointers to zero values
+func WithKeepZero(keep bool) Option {
+	return func(opt *Options) {
+		opt.KeepZero = keep
+	}
+}
+
+// WithSortMapKeys will enable transforming the keys of map sources in sorted order
+func WithSortMapKeys(sorted bool) Option {
+	return func(opt *Options) {
+		opt.SortMapKeys = sorted
+	}
+}
+
+// WithUnsupported will set the behavior when a source value is a func or a channel
+func WithUnsupported(policy UnsupportedPolicy) Option {
+	return func(opt *Options) {
+		opt.Unsupported = policy
+	}
+}
+
+// WithLocale will set the locale of the dates formatted with Field.Date
+func WithLocale(locale string) Option {
+	return func(opt *Options) {
+		opt.Locale = locale
+	}
+}
+
+// WithTimeFormatter will set the formatter of the dates formatted with Field.Date
+func WithTimeFormatter(formatter TimeFormatter) Option {
+	return func(opt *Options) {
+		opt.TimeFormatter = formatter
+	}
+}
+
+// WithRegistry will set the registry of the schemas used by TransformTenant
+func WithRegistry(registry *Registry) Option {
+	return func(opt *Options) {
+		opt.Registry = registry
+	}
+}
+
+// WithCollisions will set what happens when a field produces an output key that is already in the result
+func WithCollisions(policy CollisionPolicy) Option {
+	return func(opt *Options) {
+		opt.Collisions = policy
+	}
+}
+
+// WithValueCache will set the cache of the Transform results of the fields created with Field.Cache
+func WithValueCache(cache ValueCache) Option {
+	return func(opt *Options) {
+		opt.ValueCache = cache
+	}
+}
+
+// WithSecretScanner will set the scanner of the emitted strings and what happens when it finds a secret
+func WithSecretScanner(scanner SecretScanner, policy SecretPolicy) Option {
+	return func(opt *Options) {
+		opt.SecretScanner = scanner
+		opt.Secrets = policy
+	}
+}
+
+// WithCache will set the cache of the transformed objects
+func WithCache(cache Cache) Option {
+	return func(opt *Options) {
+		opt.Cache = cache
+	}
+}
+
+// With will create a copy of the instance with the given options applied.
+// The copy shares everything else with the instance, so it's cheap to create one per configuration
+// and changing the options of the copy doesn't affect the instance
+func (m *mantau) With(opts ...Option) *mantau {
+	m.mu.RLock()
+	c := *m
+	opt := *m.opt
+	m.mu.RUnlock()
+
+	c.mu = &sync.RWMutex{}
+	c.state = nil
+
+	for _, o := range opts {
+		o(&opt)
+	}
+
+	c.opt = &opt
+
+	return &c
+}
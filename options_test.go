@@ -0,0 +1,34 @@
+package mantau
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWith(t *testing.T) {
+	m := New()
+
+	custom := m.With(WithHook("schema"), WithMaxDepth(5), WithMismatch(MismatchOmit), WithNamespaceSeparator("_"))
+
+	assert.Equal(t, &Options{Hook: "json"}, m.opt, "The original options should not be modified")
+	assert.Equal(t, &Options{
+		Hook:               "schema",
+		MaxDepth:           5,
+		Mismatch:           MismatchOmit,
+		NamespaceSeparator: "_",
+	}, custom.opt, "The options do not match")
+
+	result, err := custom.Transform(CustomTag{ProductName: "Apple"}, Schema{
+		"name": Field{Key: "product_name"},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "Apple"}, result, "The result do not match")
+
+	_, err = m.Transform(CustomTag{ProductName: "Apple"}, Schema{
+		"name": Field{Key: "product_name"},
+	})
+
+	assert.Error(t, err, "The original instance should still use the json hook")
+}
@@ -0,0 +1,126 @@
+package mantau
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Cursor is the position of a page of a nested collection, see TransformPage
+type Cursor struct {
+	// Key is the schema key of the paged collection field
+	Key string `json:"key"`
+
+	// Offset is the index of the first element of the page
+	Offset int `json:"offset"`
+
+	// Limit is the maximum number of elements of the page
+	Limit int `json:"limit"`
+}
+
+// String will encode the cursor into an opaque string, so it can be sent to clients
+func (c Cursor) String() string {
+	encoded, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+// ParseCursor will decode a cursor encoded with Cursor.String
+func ParseCursor(s string) (Cursor, error) {
+	var c Cursor
+
+	decoded, err := base64.RawURLEncoding.DecodeString(s)
+
+	if err != nil {
+		return c, errors.New("Invalid cursor")
+	}
+
+	if err := json.Unmarshal(decoded, &c); err != nil {
+		return c, errors.New("Invalid cursor")
+	}
+
+	return c, nil
+}
+
+// TransformPage works like Transform but only transforms a page of the nested collection of the cursor key,
+// so huge embedded lists can be paged without transforming the previous elements again.
+// It returns the cursor of the next page, or nil on the last page
+func (m *mantau) TransformPage(src interface{}, schema Schema, cursor Cursor) (interface{}, *Cursor, error) {
+	field, ok := schema[cursor.Key]
+
+	if !ok {
+		return nil, nil, fmt.Errorf("Cannot find the %q cursor key in the schema", cursor.Key)
+	}
+
+	if cursor.Offset < 0 || cursor.Limit <= 0 {
+		return nil, nil, errors.New("Invalid cursor")
+	}
+
+	window := &pageWindow{offset: cursor.Offset, limit: cursor.Limit, total: -1}
+	field.page = window
+
+	result, err := m.Transform(src, schema.Override(Schema{cursor.Key: field}))
+
+	if err != nil || window.total < 0 || cursor.Offset+cursor.Limit >= window.total {
+		return result, nil, err
+	}
+
+	next := cursor
+	next.Offset += cursor.Limit
+
+	return result, &next, nil
+}
+
+// pageWindow is the page of a collection field transformed with TransformPage
+type pageWindow struct {
+	offset int
+	limit  int
+
+	// total is the length of the collection, it's set when the field is mapped
+	total int
+}
+
+// paged will check if a field of the schema is paged by TransformPage
+func (s Schema) paged() bool {
+	for _, field := range s {
+		if field.page != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// paginate will return the elements of the page of a collection
+func (m *mantau) paginate(src interface{}, window *pageWindow) interface{} {
+	if isNilValue(src) {
+		return src
+	}
+
+	value := m.getValue(src)
+
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return src
+	}
+
+	window.total = value.Len()
+	start, end := window.offset, window.offset+window.limit
+
+	if start > value.Len() {
+		start = value.Len()
+	}
+
+	if end > value.Len() {
+		end = value.Len()
+	}
+
+	result := reflect.MakeSlice(reflect.SliceOf(value.Type().Elem()), end-start, end-start)
+
+	for i := start; i < end; i++ {
+		result.Index(i - start).Set(value.Index(i))
+	}
+
+	return result.Interface()
+}
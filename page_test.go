@@ -0,0 +1,93 @@
+package mantau
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformPage(t *testing.T) {
+	src := User{
+		Name:        "John doe",
+		Permissions: []Permission{{"Admin", 1}, {"Customer", 2}, {"Guest", 3}},
+	}
+
+	schema := Schema{
+		"username": Field{Key: "name"},
+		"permissions": Field{Key: "permissions", Value: Schema{
+			"code": Field{Key: "permission_code"},
+		}},
+	}
+
+	result, next, err := New().TransformPage(src, schema, Cursor{Key: "permissions", Limit: 2})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"username":    "John doe",
+		"permissions": []Result{{"code": 1}, {"code": 2}},
+	}, result, "The result do not match")
+	assert.Equal(t, &Cursor{Key: "permissions", Offset: 2, Limit: 2}, next, "The next cursor do not match")
+
+	parsed, err := ParseCursor(next.String())
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, *next, parsed, "The parsed cursor do not match")
+
+	result, next, err = New().TransformPage(src, schema, parsed)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, []Result{{"code": 3}}, result.(Result)["permissions"], "The result do not match")
+	assert.Nil(t, next, "The last page should not have a next cursor")
+
+	assert.Len(t, schema["permissions"].Value.(Schema), 1, "The schema should not be modified")
+
+	_, _, err = New().TransformPage(src, schema, Cursor{Key: "missing", Limit: 2})
+
+	assert.Error(t, err, "Unknown cursor key should return an error")
+
+	_, err = ParseCursor("not a cursor")
+
+	assert.Error(t, err, "Invalid cursor should return an error")
+}
+
+type PagedUser struct {
+	Name        string       `json:"name"`
+	Permissions []Permission `json:"permissions"`
+}
+
+func (u PagedUser) CacheKey() string {
+	return "paged:" + u.Name
+}
+
+func TestTransformPageCached(t *testing.T) {
+	src := PagedUser{Name: "John doe", Permissions: []Permission{{"Admin", 1}, {"Customer", 2}, {"Guest", 3}}}
+	schema := Schema{
+		"username": Field{Key: "name"},
+		"permissions": Field{Key: "permissions", Value: Schema{
+			"code": Field{Key: "permission_code"},
+		}},
+	}
+
+	m := New().With(WithCache(mapCache{}))
+	cursor := &Cursor{Key: "permissions", Limit: 1}
+	codes := []interface{}{}
+
+	for cursor != nil {
+		result, next, err := m.TransformPage(src, schema, *cursor)
+
+		assert.NoError(t, err, "Should not return any error")
+
+		for _, permission := range result.(Result)["permissions"].([]Result) {
+			codes = append(codes, permission["code"])
+		}
+
+		cursor = next
+	}
+
+	assert.Equal(t, []interface{}{1, 2, 3}, codes, "Every page should be transformed")
+
+	result, err := m.Transform(src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Len(t, result.(Result)["permissions"], 3, "A page should not be cached as the whole collection")
+}
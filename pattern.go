@@ -0,0 +1,25 @@
+package mantau
+
+// mapPatterns will map a source key of a map with every schema field whose KeyPattern matches it.
+// The output key is the schema key with the capture groups of the pattern expanded e.g. "cpu_$1" or "cpu_${name}"
+func (m *mantau) mapPatterns(result Result, flat *[]flattened, sourceKey string, value, parent interface{}, schema Schema) error {
+	for key, field := range schema {
+		if field.KeyPattern == nil || field.isReserved() || m.gated(field) {
+			continue
+		}
+
+		match := field.KeyPattern.FindStringSubmatchIndex(sourceKey)
+
+		if match == nil {
+			continue
+		}
+
+		output := string(field.KeyPattern.ExpandString(nil, key, sourceKey, match))
+
+		if err := m.mapOne(result, flat, output, sourceKey, field, value, parent, schema); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package mantau
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyPattern(t *testing.T) {
+	data := map[string]interface{}{
+		"host":              "web-1",
+		"metric_cpu_user":   10,
+		"metric_cpu_system": 5,
+		"metric_mem_used":   2048,
+		"other":             "x",
+	}
+
+	schema := Schema{
+		"host":           Field{Key: "host"},
+		"cpu_$1":         Field{KeyPattern: regexp.MustCompile(`^metric_cpu_(\w+)$`)},
+		"memory_${name}": Field{KeyPattern: regexp.MustCompile(`^metric_mem_(?P<name>\w+)$`)},
+		"*":              Field{As: "string"},
+	}
+
+	result, err := New().Transform(data, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"host":        "web-1",
+		"cpu_user":    10,
+		"cpu_system":  5,
+		"memory_used": 2048,
+		"other":       "x",
+	}, result, "The result do not match")
+
+	changed := Schema{"cpu_$1": Field{KeyPattern: regexp.MustCompile(`^metric_cpu_(.+)$`)}}
+
+	assert.NotEqual(t, changed.Hash(), Schema{"cpu_$1": Field{KeyPattern: regexp.MustCompile(`^metric_cpu_(\w+)$`)}}.Hash(), "The hash should change with the pattern")
+	assert.Equal(t, changed.Hash(), Schema{"cpu_$1": Field{KeyPattern: regexp.MustCompile(`^metric_cpu_(.+)$`)}}.Hash(), "The hash should be stable")
+}
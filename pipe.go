@@ -0,0 +1,203 @@
+package mantau
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// ValueOp is a single step of a field pipe, see Field.Pipe
+type ValueOp struct {
+	// Name identifies the op in errors and explanations e.g. "coerce int"
+	Name string
+
+	// Apply will return the changed value or an error
+	Apply func(value interface{}) (interface{}, error)
+
+	// AcceptsNil calls Apply with nil values too, other ops are skipped while the value is nil
+	AcceptsNil bool
+
+	// sensitive hides the values in the coercion errors of the pipe, it's set by MaskValue
+	sensitive bool
+
+	// params are the arguments of a built-in op, they are a part of the schema hash
+	params []interface{}
+}
+
+// PipeError is returned when an op of a Field.Pipe fails,
+// it's wrapped into a *FieldError with the path of the field
+type PipeError struct {
+	// Index is the position of the failing op in the pipe, starting at 1
+	Index int
+
+	// Op is the name of the failing op
+	Op string
+
+	// Err is the error returned by the op
+	Err error
+}
+
+// Error will describe the failing op e.g. `Pipe op 2 (coerce int) failed: Cannot coerce string "many" into int`
+func (e *PipeError) Error() string {
+	return fmt.Sprintf("Pipe op %d (%s) failed: %v", e.Index, e.Op, e.Err)
+}
+
+// Unwrap will return the error of the op
+func (e *PipeError) Unwrap() error {
+	return e.Err
+}
+
+// Pipe will return a copy of the field whose value goes through the ops in the given order, instead of
+// a single Transform callback. The ops run on the source value before the other options of the field,
+// an error of an op is returned as a *PipeError naming it
+func (f Field) Pipe(ops ...ValueOp) Field {
+	f.pipe = append(append([]ValueOp{}, f.pipe...), ops...)
+
+	return f
+}
+
+// Coerce will return an op converting the value into the given type name, like Field.As
+func Coerce(as string) ValueOp {
+	return ValueOp{
+		Name: "coerce " + as,
+		Apply: func(value interface{}) (interface{}, error) {
+			return coerce(value, as)
+		},
+		params: []interface{}{as},
+	}
+}
+
+// Format will return an op formatting the value into a string, times are formatted with the layout
+// and other values with fmt.Sprintf e.g. "%.2f"
+func Format(format string) ValueOp {
+	return ValueOp{
+		Name: fmt.Sprintf("format %q", format),
+		Apply: func(value interface{}) (interface{}, error) {
+			switch t := value.(type) {
+			case time.Time:
+				return t.Format(format), nil
+			case *time.Time:
+				return t.Format(format), nil
+			}
+
+			return fmt.Sprintf(format, value), nil
+		},
+		params: []interface{}{format},
+	}
+}
+
+// MaskValue will return an op hiding the characters of the value, like Field.Mask.
+// Coercion errors of the pipe don't include the value when it's masked
+func MaskValue(spec string) ValueOp {
+	return ValueOp{
+		Name: "mask " + spec,
+		Apply: func(value interface{}) (interface{}, error) {
+			return mask(value, spec)
+		},
+		sensitive: true,
+		params:    []interface{}{spec},
+	}
+}
+
+// Validate will return an op checking the value with the given function, the value is kept when it's valid
+func Validate(check func(value interface{}) error) ValueOp {
+	return ValueOp{
+		Name: "validate",
+		Apply: func(value interface{}) (interface{}, error) {
+			if err := check(value); err != nil {
+				return nil, err
+			}
+
+			return value, nil
+		},
+		params: []interface{}{check},
+	}
+}
+
+// Default will return an op replacing nil and zero values with the given value.
+// It's applied when the source key is missing from the object too
+func Default(value interface{}) ValueOp {
+	return ValueOp{
+		Name: "default",
+		Apply: func(v interface{}) (interface{}, error) {
+			if v == nil || reflect.ValueOf(v).IsZero() {
+				return value, nil
+			}
+
+			return v, nil
+		},
+		AcceptsNil: true,
+		params:     []interface{}{value},
+	}
+}
+
+// runPipe will pass the value through the ops of the field
+func runPipe(field Field, value interface{}) (interface{}, error) {
+	sensitive := false
+
+	for _, op := range field.pipe {
+		sensitive = sensitive || op.sensitive
+	}
+
+	for i, op := range field.pipe {
+		if value == nil && !op.AcceptsNil {
+			continue
+		}
+
+		changed, err := op.Apply(value)
+
+		if err != nil {
+			var coercionErr *CoercionError
+
+			if sensitive && errors.As(err, &coercionErr) {
+				coercionErr.Value = nil
+				coercionErr.Redacted = true
+			}
+
+			return nil, &PipeError{Index: i + 1, Op: op.Name, Err: err}
+		}
+
+		value = changed
+	}
+
+	return value, nil
+}
+
+// fillsNil will check if an op of the pipe of the field is called with nil values e.g. Default
+func (f Field) fillsNil() bool {
+	for _, op := range f.pipe {
+		if op.AcceptsNil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// defaultFields will map the fields whose pipe is called with nil values when their source key is missing
+// from the object, so e.g. Default applies to them. A source key with a nil value is mapped like any other key
+func (m *mantau) defaultFields(result Result, flat *[]flattened, keys []string, src interface{}, schema Schema) error {
+	for key, field := range schema {
+		if !field.fillsNil() || !m.readsKey(field) || hasKey(field.Key, keys) {
+			continue
+		}
+
+		if err := m.mapOne(result, flat, key, field.Key, field, nil, src, schema); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hasKey will check if the object has the source key
+func hasKey(sourceKey string, keys []string) bool {
+	for _, key := range keys {
+		if key == sourceKey {
+			return true
+		}
+	}
+
+	return false
+}
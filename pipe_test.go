@@ -0,0 +1,79 @@
+package mantau
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldPipe(t *testing.T) {
+	positive := Validate(func(value interface{}) error {
+		if value.(int64) <= 0 {
+			return errors.New("Must be positive")
+		}
+
+		return nil
+	})
+
+	schema := Schema{
+		"amount": Field{Key: "amount"}.Pipe(Default("1"), Coerce("int"), positive),
+		"price":  Field{Key: "price"}.Pipe(Format("%.2f")),
+		"day":    Field{Key: "day"}.Pipe(Format("2006-01-02")),
+		"card":   Field{Key: "card"}.Pipe(Coerce("string"), MaskValue("last4")),
+	}
+
+	result, err := New().Transform(map[string]interface{}{
+		"amount": nil,
+		"price":  12.5,
+		"day":    time.Date(2020, 5, 17, 10, 0, 0, 0, time.UTC),
+		"card":   4111111111111111,
+	}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"amount": int64(1), "price": "12.50", "day": "2020-05-17", "card": "************1111"}, result)
+
+	_, err = New().Transform(map[string]interface{}{"amount": "-3"}, Schema{"amount": schema["amount"]})
+
+	var pipeErr *PipeError
+
+	assert.True(t, errors.As(err, &pipeErr), "The error should be a pipe error")
+	assert.Equal(t, 3, pipeErr.Index)
+	assert.Equal(t, "amount: Pipe op 3 (validate) failed: Must be positive", err.Error())
+
+	_, err = New().Transform(map[string]interface{}{"amount": "many"}, Schema{"amount": schema["amount"]})
+
+	assert.True(t, errors.Is(err, ErrCoercion), "The coercion error should be unwrapped")
+	assert.Equal(t, `amount: Pipe op 2 (coerce int) failed: Cannot coerce string "many" into int`, err.Error())
+
+	_, err = New().Transform(map[string]interface{}{"pin": "12a4"}, Schema{"pin": Field{Key: "pin"}.Pipe(MaskValue("all"), Coerce("int"))})
+
+	assert.Equal(t, "pin: Pipe op 2 (coerce int) failed: Cannot coerce string [REDACTED] into int", err.Error(), "The masked value should not leak")
+}
+
+func TestFieldPipeDefaults(t *testing.T) {
+	schema := Schema{
+		"plan":  Field{Key: "plan"}.Pipe(Default("free")),
+		"seats": Field{Key: "seats"}.Pipe(Default(1), Coerce("string")),
+	}
+
+	result, err := New().Transform(map[string]interface{}{"plan": "pro"}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"plan": "pro", "seats": "1"}, result, "Default should apply to a missing key")
+
+	result, err = New().Transform(map[string]interface{}{"plan": nil, "seats": 0}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"plan": "free", "seats": "1"}, result, "Default should apply to nil and zero values")
+}
+
+func TestFieldPipeHash(t *testing.T) {
+	notEmpty := func(value interface{}) error { return nil }
+	positive := func(value interface{}) error { return nil }
+
+	assert.NotEqual(t, Schema{"a": Field{}.Pipe(Default("a"))}.Hash(), Schema{"a": Field{}.Pipe(Default("b"))}.Hash())
+	assert.NotEqual(t, Schema{"a": Field{}.Pipe(Validate(notEmpty))}.Hash(), Schema{"a": Field{}.Pipe(Validate(positive))}.Hash())
+	assert.Equal(t, Schema{"a": Field{}.Pipe(Coerce("int"))}.Hash(), Schema{"a": Field{}.Pipe(Coerce("int"))}.Hash())
+}
@@ -0,0 +1,26 @@
+package mantau
+
+import "fmt"
+
+// TransformProvenance works like Transform but wraps every value of the result with it's provenance,
+// {"value": ..., "source": "permissions[0].permission_code", "type": "int"}, to trace where an unexpected value came from.
+// It's meant for debugging, the result doesn't have the shape of the schema
+func (m *mantau) TransformProvenance(src interface{}, schema Schema) (interface{}, error) {
+	c := m.withState()
+	c.state.provenance = true
+
+	return c.run(src, schema)
+}
+
+// withProvenance will wrap the value with the path and the type of the source value
+func (m *mantau) withProvenance(sourceKey string, src, value interface{}) interface{} {
+	if m.state == nil || !m.state.provenance {
+		return value
+	}
+
+	return Result{
+		"value":  value,
+		"source": joinPath(m.state.sourcePath(), sourceKey),
+		"type":   fmt.Sprintf("%T", src),
+	}
+}
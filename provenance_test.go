@@ -0,0 +1,34 @@
+package mantau
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformProvenance(t *testing.T) {
+	result, err := New().TransformProvenance(User{
+		Name:        "John doe",
+		Permissions: []Permission{{"Admin", 1}},
+	}, Schema{
+		"username": Field{Key: "name"},
+		"permissions": Field{
+			Key: "permissions",
+			Value: Schema{
+				"code": Field{Key: "permission_code"},
+			},
+		},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"username": Result{"value": "John doe", "source": "name", "type": "string"},
+		"permissions": Result{
+			"value": []Result{
+				{"code": Result{"value": 1, "source": "permissions[0].permission_code", "type": "int"}},
+			},
+			"source": "permissions",
+			"type":   "[]mantau.Permission",
+		},
+	}, result, "The result do not match")
+}
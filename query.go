@@ -0,0 +1,106 @@
+package mantau
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// EncodeQuery will encode the result into url.Values. Nested objects are encoded with brackets
+// like "address[code]", collections of objects use their index like "items[0][name]"
+// and collections of primitives are repeated like "tags[]". Nil values and items are skipped
+func (r Result) EncodeQuery() url.Values {
+	values := url.Values{}
+
+	for key, value := range r {
+		encodeQueryValue(values, key, value)
+	}
+
+	return values
+}
+
+// TransformToQuery will transform the data with the given schema and encode the result into url.Values
+func (m *mantau) TransformToQuery(src interface{}, schema Schema) (url.Values, error) {
+	result, err := m.Transform(src, schema)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if result == nil {
+		return url.Values{}, nil
+	}
+
+	r, ok := result.(Result)
+
+	if !ok {
+		return nil, errors.New("Query string can only be encoded from an object")
+	}
+
+	return r.EncodeQuery(), nil
+}
+
+// encodeQueryValue will add a single value to the url.Values under the given key
+func encodeQueryValue(values url.Values, key string, value interface{}) {
+	if isNilValue(value) {
+		return
+	}
+
+	v := reflect.ValueOf(value)
+
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			encodeQueryValue(values, key+"["+fmt.Sprint(k.Interface())+"]", v.MapIndex(k).Interface())
+		}
+
+		return
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+
+		for i := 0; i < v.Len(); i++ {
+			item := v.Index(i).Interface()
+
+			// nil items e.g. a JSON null are skipped like nil values
+			if isNilValue(item) {
+				continue
+			}
+
+			if isContainerType(reflect.TypeOf(item)) {
+				encodeQueryValue(values, key+"["+strconv.Itoa(i)+"]", item)
+				continue
+			}
+
+			encodeQueryValue(values, key+"[]", item)
+		}
+
+		return
+	}
+
+	values.Add(key, queryString(v.Interface()))
+}
+
+// queryString will format a primitive value for a query string
+func queryString(value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case []byte:
+		return string(v)
+	}
+
+	if s, err := coerce(value, "string"); err == nil {
+		return s.(string)
+	}
+
+	return fmt.Sprint(value)
+}
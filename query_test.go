@@ -0,0 +1,69 @@
+package mantau
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeQuery(t *testing.T) {
+	result := Result{
+		"name":    "John doe",
+		"age":     30,
+		"empty":   nil,
+		"tags":    []string{"a", "b"},
+		"address": Result{"code": "809120"},
+		"items":   []Result{{"name": "Apple", "price": 1.5}},
+	}
+
+	assert.Equal(t, url.Values{
+		"name":            {"John doe"},
+		"age":             {"30"},
+		"tags[]":          {"a", "b"},
+		"address[code]":   {"809120"},
+		"items[0][name]":  {"Apple"},
+		"items[0][price]": {"1.5"},
+	}, result.EncodeQuery(), "The result do not match")
+}
+
+func TestTransformToQuery(t *testing.T) {
+	m := New()
+
+	values, err := m.TransformToQuery(User{
+		Name:    "John doe",
+		Address: UserAddress{PostalCode: "809120"},
+	}, Schema{
+		"username": Field{Key: "name"},
+		"address": Field{
+			Key:   "user_address",
+			Value: Schema{"code": Field{Key: "postal_code"}},
+		},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, "address%5Bcode%5D=809120&username=John+doe", values.Encode(), "The result do not match")
+
+	_, err = m.TransformToQuery([]Permission{{"Admin", 0}}, Schema{"name": Field{Key: "permission_name"}})
+
+	assert.Error(t, err, "Collections cannot be encoded as a query string")
+}
+
+func TestEncodeQueryNilItems(t *testing.T) {
+	result := Result{
+		"tags":  []interface{}{"a", nil, "b"},
+		"items": []interface{}{nil, Result{"name": "Apple"}},
+	}
+
+	assert.Equal(t, url.Values{
+		"tags[]":         {"a", "b"},
+		"items[1][name]": {"Apple"},
+	}, result.EncodeQuery(), "Nil items should be skipped")
+
+	values, err := New().TransformToQuery(map[string]interface{}{
+		"tags": []interface{}{nil, "a"},
+	}, Schema{"tags": Field{Key: "tags"}})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, "tags%5B%5D=a", values.Encode(), "The result do not match")
+}
@@ -0,0 +1,78 @@
+package mantau
+
+import (
+	"sort"
+	"sync"
+)
+
+// Registry will store named and versioned schemas so they can be looked up at runtime
+type Registry struct {
+	mu      sync.RWMutex
+	schemas map[string]map[string]Schema
+
+	// overlays are the patches of every tenant by the name of the schema they patch
+	overlays map[string]map[string]Schema
+}
+
+// NewRegistry will create an empty schema registry
+func NewRegistry() *Registry {
+	return &Registry{
+		schemas:  map[string]map[string]Schema{},
+		overlays: map[string]map[string]Schema{},
+	}
+}
+
+// Register will store the schema under the given name and version,
+// registering the same name and version twice will replace the previous schema
+func (r *Registry) Register(name, version string, schema Schema) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.schemas[name]; !ok {
+		r.schemas[name] = map[string]Schema{}
+	}
+
+	r.schemas[name][version] = schema
+}
+
+// Get will find a schema by it's name and version
+func (r *Registry) Get(name, version string) (Schema, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	schema, ok := r.schemas[name][version]
+
+	return schema, ok
+}
+
+// Names will return the sorted names of every registered schema
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.schemas))
+
+	for name := range r.schemas {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Versions will return the sorted versions registered under the given name
+func (r *Registry) Versions(name string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions := make([]string, 0, len(r.schemas[name]))
+
+	for version := range r.schemas[name] {
+		versions = append(versions, version)
+	}
+
+	sort.Strings(versions)
+
+	return versions
+}
@@ -0,0 +1,60 @@
+package mantau
+
+import "sort"
+
+// checkRequired will return a *RequiredFieldError for every required field of the schema whose source value
+// is missing from the object or nil. The fields are checked in the order of their keys, so the same field
+// is reported first every time. When errors are collected, every missing field is reported
+func (m *mantau) checkRequired(keys []string, values []interface{}, schema Schema) error {
+	schemaKeys := make([]string, 0, len(schema))
+
+	for key := range schema {
+		schemaKeys = append(schemaKeys, key)
+	}
+
+	sort.Strings(schemaKeys)
+
+	for _, key := range schemaKeys {
+		field := schema[key]
+
+		if !field.Required || !m.readsKey(field) {
+			continue
+		}
+
+		if m.present(field.Key, keys, values) {
+			continue
+		}
+
+		sourcePath := field.Key
+
+		if m.state != nil {
+			sourcePath = joinPath(m.state.sourcePath(), field.Key)
+		}
+
+		err := m.fieldError(key, &RequiredFieldError{Key: key, SourcePath: sourcePath})
+
+		if m.collect(err) {
+			continue
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// present will check if the object has a value that is not nil under the source key.
+// A lazy value failing to resolve is present, it's error is returned when the field is mapped
+func (m *mantau) present(sourceKey string, keys []string, values []interface{}) bool {
+	for i, key := range keys {
+		if key != sourceKey {
+			continue
+		}
+
+		value, err := resolve(values[i])
+
+		return err != nil || !isNilValue(value)
+	}
+
+	return false
+}
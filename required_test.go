@@ -0,0 +1,70 @@
+package mantau
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequired(t *testing.T) {
+	schema := Schema{
+		"name":  Field{Key: "name", Required: true},
+		"email": Field{Key: "email_address", Required: true},
+		"address": Field{Key: "address", Value: Schema{
+			"city": Field{Key: "city", Required: true},
+		}},
+	}
+
+	result, err := New().Transform(map[string]interface{}{
+		"name":          "John",
+		"email_address": "john@example.com",
+		"address":       map[string]interface{}{"city": "Paris"},
+	}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John", "email": "john@example.com", "address": Result{"city": "Paris"}}, result)
+
+	_, err = New().Transform(map[string]interface{}{
+		"name":          "John",
+		"email_address": nil,
+		"address":       map[string]interface{}{"city": "Paris"},
+	}, schema)
+
+	var requiredErr *RequiredFieldError
+
+	assert.True(t, errors.Is(err, ErrRequiredField), "Should return a required field error")
+	assert.True(t, errors.As(err, &requiredErr), "Should return a *RequiredFieldError")
+	assert.Equal(t, &RequiredFieldError{Key: "email", SourcePath: "email_address"}, requiredErr)
+	assert.EqualError(t, err, `email: Required field "email" is missing from "email_address"`)
+
+	_, err = New().Transform(User{Name: "John"}, Schema{"username": Field{Key: "name"}, "phone": Field{Key: "phone", Required: true}})
+
+	assert.NoError(t, err, "A zero struct field is present")
+
+	for i := 0; i < 10; i++ {
+		_, err = New().Transform(map[string]interface{}{}, schema)
+
+		assert.EqualError(t, err, `email: Required field "email" is missing from "email_address"`, "The first missing field should be reported")
+	}
+}
+
+func TestRequiredNested(t *testing.T) {
+	schema := Schema{
+		"addresses": Field{Key: "addresses", Value: Schema{
+			"city": Field{Key: "city", Required: true},
+			"zip":  Field{Key: "zip", Required: true},
+		}},
+	}
+
+	_, err := New().With(WithCollectErrors(true)).Transform(map[string]interface{}{
+		"addresses": []map[string]interface{}{{"city": "Paris", "zip": "75001"}, {}},
+	}, schema)
+
+	var multi *MultiError
+
+	assert.True(t, errors.As(err, &multi), "Should collect every missing field")
+	assert.Len(t, multi.Errors, 2)
+	assert.Equal(t, "addresses[1].city", multi.Errors[0].Path)
+	assert.Equal(t, &RequiredFieldError{Key: "city", SourcePath: "addresses[1].city"}, multi.Errors[0].Err)
+}
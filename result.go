@@ -0,0 +1,133 @@
+package mantau
+
+import "reflect"
+
+// Equal will compare the result with another result. Numbers are compared by their value
+// regardless of their type, so an int 5 is equal to a float64 5.0
+func (r Result) Equal(other Result) bool {
+	return equalValues(r, other)
+}
+
+// equalValues will compare two transformed values recursively,
+// treating numeric values of different types as equal when their values are the same
+func equalValues(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return isNilValue(a) && isNilValue(b)
+	}
+
+	va := reflect.ValueOf(a)
+	vb := reflect.ValueOf(b)
+
+	if isNumberKind(va.Kind()) && isNumberKind(vb.Kind()) {
+		return equalNumbers(va, vb)
+	}
+
+	switch va.Kind() {
+	case reflect.Map:
+		if vb.Kind() != reflect.Map || va.Type().Key() != vb.Type().Key() || va.Len() != vb.Len() {
+			return false
+		}
+
+		for _, key := range va.MapKeys() {
+			other := vb.MapIndex(key)
+
+			if !other.IsValid() {
+				return false
+			}
+
+			if !equalValues(va.MapIndex(key).Interface(), other.Interface()) {
+				return false
+			}
+		}
+
+		return true
+	case reflect.Slice, reflect.Array:
+		if (vb.Kind() != reflect.Slice && vb.Kind() != reflect.Array) || va.Len() != vb.Len() {
+			return false
+		}
+
+		for i := 0; i < va.Len(); i++ {
+			if !equalValues(va.Index(i).Interface(), vb.Index(i).Interface()) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// isNilValue will check if the given value is nil or a nil pointer, map or slice
+func isNilValue(src interface{}) bool {
+	if src == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(src)
+
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		return value.IsNil()
+	}
+
+	return false
+}
+
+// isNumberKind will check if the given kind is an integer or a float
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+// equalNumbers will compare two numeric values. Integers are compared exactly
+// while anything involving a float is compared as float64
+func equalNumbers(a, b reflect.Value) bool {
+	switch {
+	case isSigned(a.Kind()) && isSigned(b.Kind()):
+		return a.Int() == b.Int()
+	case isUnsigned(a.Kind()) && isUnsigned(b.Kind()):
+		return a.Uint() == b.Uint()
+	case isSigned(a.Kind()) && isUnsigned(b.Kind()):
+		return a.Int() >= 0 && uint64(a.Int()) == b.Uint()
+	case isUnsigned(a.Kind()) && isSigned(b.Kind()):
+		return b.Int() >= 0 && a.Uint() == uint64(b.Int())
+	}
+
+	return toFloat(a) == toFloat(b)
+}
+
+func isSigned(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+
+	return false
+}
+
+func isUnsigned(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+
+	return false
+}
+
+func toFloat(value reflect.Value) float64 {
+	switch {
+	case isSigned(value.Kind()):
+		return float64(value.Int())
+	case isUnsigned(value.Kind()):
+		return float64(value.Uint())
+	}
+
+	return value.Float()
+}
@@ -0,0 +1,39 @@
+package mantau
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultEqual(t *testing.T) {
+	result := Result{
+		"name":  "Apple",
+		"price": 5,
+		"qty":   uint8(2),
+		"tags":  []string{"fruit"},
+		"buyer": Result{"score": 0.5},
+		"items": []Result{{"code": int64(1)}},
+	}
+
+	same := Result{
+		"name":  "Apple",
+		"price": 5.0,
+		"qty":   2,
+		"tags":  []interface{}{"fruit"},
+		"buyer": map[string]interface{}{"score": float32(0.5)},
+		"items": []Result{{"code": 1}},
+	}
+
+	assert.True(t, result.Equal(same), "Numbers with different types should be equal")
+	assert.False(t, result.Equal(Result{"name": "Apple"}), "Missing keys should not be equal")
+	assert.False(t, Result{"price": 5}.Equal(Result{"price": 5.5}), "Different numbers should not be equal")
+	assert.False(t, Result{"price": -1}.Equal(Result{"price": uint(1)}), "Negative numbers should not equal unsigned")
+	assert.True(t, Result{"author": nil}.Equal(Result{"author": (*Author)(nil)}), "Nil values should be equal")
+	assert.False(t, Result{"m": map[string]interface{}{"1": 1}}.Equal(Result{"m": map[int]interface{}{1: 1}}),
+		"Maps with different key types should not be equal")
+	assert.True(t, Result{"m": map[int]interface{}{1: 1}}.Equal(Result{"m": map[int]interface{}{1: 1.0}}),
+		"Maps with non-string keys should be compared by their values")
+	assert.False(t, Result{"m": map[int]interface{}{1: 1}}.Equal(Result{"m": map[int]interface{}{2: 1}}),
+		"Maps with different non-string keys should not be equal")
+}
@@ -0,0 +1,119 @@
+package mantau
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Rounding is the precision of a float field, see Field.Round
+type Rounding struct {
+	// Places is the number of decimal places
+	Places int
+
+	// Mode is how a value half way between two rounded values is rounded, defaults to RoundHalfUp
+	Mode RoundingMode
+}
+
+// Round will round a float field to the given number of decimal places, half away from zero
+func Round(places int) *Rounding {
+	return &Rounding{Places: places, Mode: RoundHalfUp}
+}
+
+// RoundEven will round a float field to the given number of decimal places, half to the nearest even digit
+func RoundEven(places int) *Rounding {
+	return &Rounding{Places: places, Mode: RoundHalfEven}
+}
+
+// round will round a float value, other values are returned as they are.
+// The value is rounded on it's shortest decimal representation, so 1.005 is rounded into 1.01
+func round(value interface{}, r *Rounding) (interface{}, error) {
+	if r.Places < 0 {
+		return nil, fmt.Errorf("Invalid number of decimal places %d", r.Places)
+	}
+
+	if r.Mode != "" && r.Mode != RoundHalfUp && r.Mode != RoundHalfEven {
+		return nil, fmt.Errorf("Unknown rounding mode %q", r.Mode)
+	}
+
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.Float32:
+		rounded, err := roundFloat(v.Float(), 32, r)
+
+		return float32(rounded), err
+	case reflect.Float64:
+		return roundFloat(v.Float(), 64, r)
+	}
+
+	return value, nil
+}
+
+// roundFloat will round the decimal digits of a float
+func roundFloat(f float64, bitSize int, r *Rounding) (float64, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return f, nil
+	}
+
+	s := strconv.FormatFloat(math.Abs(f), 'f', -1, bitSize)
+	integer, fraction := s, ""
+
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		integer, fraction = s[:i], s[i+1:]
+	}
+
+	if len(fraction) <= r.Places {
+		return f, nil
+	}
+
+	digits := []byte(integer + fraction[:r.Places])
+	next, rest := fraction[r.Places], strings.TrimRight(fraction[r.Places+1:], "0")
+
+	up := next > '5' || (next == '5' && (rest != "" || r.Mode != RoundHalfEven || (digits[len(digits)-1]-'0')%2 == 1))
+
+	if up {
+		digits = incrementDigits(digits)
+	}
+
+	point := len(digits) - r.Places
+	rounded, err := strconv.ParseFloat(string(digits[:point])+"."+string(digits[point:])+"0", 64)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if f < 0 {
+		rounded = -rounded
+	}
+
+	return rounded, nil
+}
+
+// incrementDigits will add one to a decimal number of digits
+func incrementDigits(digits []byte) []byte {
+	for i := len(digits) - 1; i >= 0; i-- {
+		if digits[i] < '9' {
+			digits[i]++
+			return digits
+		}
+
+		digits[i] = '0'
+	}
+
+	return append([]byte{'1'}, digits...)
+}
+
+// formatRounded will format a rounded float with exactly the number of decimal places of the rounding
+func formatRounded(value interface{}, r *Rounding) interface{} {
+	switch f := value.(type) {
+	case float32:
+		return strconv.FormatFloat(float64(f), 'f', r.Places, 32)
+	case float64:
+		return strconv.FormatFloat(f, 'f', r.Places, 64)
+	}
+
+	return value
+}
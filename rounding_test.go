@@ -0,0 +1,65 @@
+package mantau
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRound(t *testing.T) {
+	cases := []struct {
+		value    interface{}
+		rounding *Rounding
+		expected interface{}
+	}{
+		{1.005, Round(2), 1.01},
+		{1.004, Round(2), 1.0},
+		{-1.005, Round(2), -1.01},
+		{2.5, Round(0), 3.0},
+		{2.5, RoundEven(0), 2.0},
+		{3.5, RoundEven(0), 4.0},
+		{0.125, RoundEven(2), 0.12},
+		{0.1251, RoundEven(2), 0.13},
+		{9.999, Round(2), 10.0},
+		{float32(1.25), Round(1), float32(1.3)},
+		{1.5, Round(3), 1.5},
+		{"1.555", Round(2), "1.555"},
+	}
+
+	for _, c := range cases {
+		value, err := round(c.value, c.rounding)
+
+		assert.NoError(t, err, "Should not return any error")
+		assert.Equal(t, c.expected, value, "%v should be rounded into %v", c.value, c.expected)
+	}
+
+	_, err := round(1.5, &Rounding{Places: -1})
+	assert.Error(t, err, "Should return an error for negative places")
+}
+
+func TestRoundField(t *testing.T) {
+	type Stats struct {
+		Average float64 `mantau:"average,round=1"`
+		Price   float64 `mantau:"price"`
+	}
+
+	m := New()
+	m.SetOpt(&Options{Hook: "mantau"})
+
+	result, err := m.Transform(Stats{Average: 3.14159, Price: 12.5}, Schema{
+		"average": Field{Key: "average"},
+		"price":   Field{Key: "price", Round: RoundEven(2), As: "string"},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"average": 3.1, "price": "12.50"}, result, "The result do not match")
+}
+
+func TestRoundHash(t *testing.T) {
+	a := Schema{"price": Field{Key: "price", Round: Round(2)}}
+	b := Schema{"price": Field{Key: "price", Round: Round(2)}}
+	c := Schema{"price": Field{Key: "price", Round: RoundEven(2)}}
+
+	assert.Equal(t, a.Hash(), b.Hash(), "The same rounding should have the same hash")
+	assert.NotEqual(t, a.Hash(), c.Hash(), "A different rounding should have a different hash")
+}
@@ -0,0 +1,45 @@
+package mantau
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// defaultSampleBy is the source key hashed to sample an object when Field.SampleBy is empty
+const defaultSampleBy = "id"
+
+// sampled will check if the field is included for the parent object. The value of the SampleBy key
+// is hashed into [0, 1) and compared with the rate, an object without the key is never sampled
+func (m *mantau) sampled(field Field, parent interface{}) (bool, error) {
+	if field.SampleRate <= 0 || field.SampleRate >= 1 {
+		return true, nil
+	}
+
+	by := field.SampleBy
+
+	if by == "" {
+		by = defaultSampleBy
+	}
+
+	value, err := m.sibling(parent, by)
+
+	if err != nil || isNilValue(value) {
+		return false, err
+	}
+
+	h := fnv.New64a()
+	fmt.Fprint(h, value)
+
+	return float64(mix(h.Sum64())>>11)/(1<<53) < field.SampleRate, nil
+}
+
+// mix will spread the bits of a hash, FNV alone is poorly distributed for short sequential keys like ids
+func mix(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+
+	return h
+}
@@ -0,0 +1,63 @@
+package mantau
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampleRate(t *testing.T) {
+	m := New()
+	schema := Schema{
+		"id":    Field{Key: "id"},
+		"trace": Field{Key: "trace", SampleRate: 0.25},
+	}
+
+	src := make([]map[string]interface{}, 1000)
+
+	for i := range src {
+		src[i] = map[string]interface{}{"id": i, "trace": "verbose"}
+	}
+
+	first, err := m.Transform(src, schema)
+	assert.NoError(t, err, "Should not return any error")
+
+	second, err := m.Transform(src, schema)
+	assert.NoError(t, err, "Should not return any error")
+
+	assert.Equal(t, first, second, "Should sample the same objects every time")
+
+	sampled := 0
+
+	for _, result := range first.([]Result) {
+		if _, ok := result["trace"]; ok {
+			sampled++
+		}
+	}
+
+	assert.InDelta(t, 250, sampled, 50, "Should include the field for about a quarter of the objects")
+}
+
+func TestSampleBy(t *testing.T) {
+	m := New()
+	schema := Schema{
+		"trace": Field{Key: "trace", SampleRate: 0.5, SampleBy: "user"},
+		"all":   Field{Key: "trace"},
+	}
+
+	for _, user := range []string{"a", "b", "c", "d", "e", "f"} {
+		result, err := m.Transform(map[string]interface{}{"user": user, "id": 1, "trace": "verbose"}, schema)
+		assert.NoError(t, err, "Should not return any error")
+
+		_, ok := result.(Result)["trace"]
+		sampled, _ := m.sampled(schema["trace"], map[string]interface{}{"user": user})
+
+		assert.Equal(t, sampled, ok, "Should sample by the user key")
+		assert.Equal(t, "verbose", result.(Result)["all"], "Should always include a field without a rate")
+	}
+
+	result, err := m.Transform(map[string]interface{}{"trace": "verbose"}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"all": "verbose"}, result, "Should not sample an object without the key")
+}
@@ -0,0 +1,185 @@
+package mantau
+
+import (
+	"fmt"
+	"strings"
+)
+
+// finalizeKey is the reserved schema key storing the schema post-processor
+const finalizeKey = "$finalize"
+
+// Describe will describe the output shape of the schema. Every output key is described
+// by the source key it reads from and the description of it's nested schema, if any.
+// The dotted keys e.g. "address.city" are described in the nested objects they are emitted in
+func (s Schema) Describe() Result {
+	result := Result{}
+
+	for key, field := range s.outputSchema() {
+		if field.isReserved() || field.drop {
+			continue
+		}
+
+		description := Result{"key": field.Key}
+
+		switch nested := field.Value.(type) {
+		case Schema:
+			description["fields"] = nested.Describe()
+		case map[string]Schema:
+			keyed := Result{}
+
+			for name, schema := range nested {
+				keyed[name] = Result{"fields": schema.Describe()}
+			}
+
+			description["keys"] = keyed
+		}
+
+		result[key] = description
+	}
+
+	return result
+}
+
+// First will create a field emitting the first element of a collection transformed with the given schema
+func First(key string, schema Schema) Field {
+	return Field{Key: key, Value: schema, Pick: FirstElement}
+}
+
+// Last will create a field emitting the last element of a collection transformed with the given schema
+func Last(key string, schema Schema) Field {
+	return Field{Key: key, Value: schema, Pick: LastElement}
+}
+
+// Tombstone will create a field that removes the key from the base schema when used in an override patch
+func Tombstone() Field {
+	return Field{tombstone: true}
+}
+
+// Override will create a new schema from the schema and the given patch.
+// Keys in the patch replace or add entries, a Tombstone removes the entry from the result.
+// Neither the schema nor the patch are modified
+func (s Schema) Override(patch Schema) Schema {
+	result := make(Schema, len(s)+len(patch))
+
+	for key, field := range s {
+		result[key] = field
+	}
+
+	for key, field := range patch {
+		if field.tombstone {
+			delete(result, key)
+			continue
+		}
+
+		result[key] = field
+	}
+
+	return result
+}
+
+// Merge will create a new schema with the entries of both schemas, so large schemas can be composed from
+// reusable fragments e.g. audit or pagination fields. With overwrite the entries of other replace the entries
+// of the schema and a Tombstone removes the entry like Override, otherwise the entries of the schema are kept.
+// Neither schema is modified
+func (s Schema) Merge(other Schema, overwrite bool) Schema {
+	if overwrite {
+		return s.Override(other)
+	}
+
+	result := make(Schema, len(s)+len(other))
+
+	for key, field := range other {
+		if !field.tombstone {
+			result[key] = field
+		}
+	}
+
+	for key, field := range s {
+		result[key] = field
+	}
+
+	return result
+}
+
+// Extend will create a new schema with the entries of the base schema and the schema,
+// the entries of the schema take precedence
+func (s Schema) Extend(base Schema) Schema {
+	return base.Merge(s, true)
+}
+
+// WithFinalize will create a new schema that calls fn with every object transformed by the schema,
+// the returned result replaces the transformed object. It can be used to add computed values
+// or drop empty sections without affecting other schemas
+func (s Schema) WithFinalize(fn func(Result) (Result, error)) Schema {
+	return s.Override(Schema{
+		finalizeKey: Field{finalize: fn},
+	})
+}
+
+// finalizeResult will call the schema post-processor, if any, with the transformed object.
+// A field stored under the reserved key by the schema itself is rejected, it would replace the post-processor
+func (s Schema) finalizeResult(result Result) (Result, error) {
+	field, ok := s[finalizeKey]
+
+	if !ok {
+		return result, nil
+	}
+
+	if field.finalize == nil {
+		return nil, fmt.Errorf("%w: the key %q is reserved", ErrInvalidSchema, finalizeKey)
+	}
+
+	return field.finalize(result)
+}
+
+// isReserved will check if the field is an internal schema entry rather than a mapping
+func (f Field) isReserved() bool {
+	return f.finalize != nil
+}
+
+// isMapping will check if the schema entry maps a key, rather than being the wildcard, an internal entry
+// or a field dropped or removed by a patch
+func isMapping(key string, f Field) bool {
+	return key != wildcardKey && !f.isReserved() && !f.drop && !f.tombstone
+}
+
+// readsKey will check if the field is mapped from the source key matching it's Key, whatever the feature flags
+func (f Field) readsKey() bool {
+	return !f.isReserved() && f.inject == nil && !f.computed() && f.KeyPattern == nil && !f.drop
+}
+
+// readsKey will check if the field is mapped from the source key matching it's Key
+func (m *mantau) readsKey(f Field) bool {
+	return f.readsKey() && !m.gated(f)
+}
+
+// features reports which passes over the schema an object needs besides mapping it's keys,
+// so the objects of a schema that only maps keys don't walk the schema once per pass
+type features struct {
+	required bool
+	defaults bool
+	computed bool
+	injected bool
+	patterns bool
+	wildcard bool
+	dotted   bool
+	paths    bool
+}
+
+// features will walk the schema once to find the passes it's objects need
+func (s Schema) features() features {
+	f := features{}
+
+	for key, field := range s {
+		f.required = f.required || field.Required
+		f.defaults = f.defaults || field.fillsNil()
+		f.computed = f.computed || field.computed()
+		f.injected = f.injected || field.inject != nil
+		f.patterns = f.patterns || field.KeyPattern != nil
+		f.wildcard = f.wildcard || key == wildcardKey
+		f.dotted = f.dotted || strings.Contains(key, ".")
+		f.paths = f.paths || strings.Contains(field.Key, ".")
+	}
+
+	return f
+}
@@ -0,0 +1,304 @@
+package mantau
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaOverride(t *testing.T) {
+	base := Schema{
+		"username":  Field{Key: "name"},
+		"useremail": Field{Key: "email"},
+		"phone":     Field{Key: "phone"},
+	}
+
+	result := base.Override(Schema{
+		"useremail": Field{Key: "email", Mask: "first2"},
+		"active":    Field{Key: "is_active"},
+		"phone":     Tombstone(),
+		"missing":   Tombstone(),
+	})
+
+	assert.Equal(t, Schema{
+		"username":  Field{Key: "name"},
+		"useremail": Field{Key: "email", Mask: "first2"},
+		"active":    Field{Key: "is_active"},
+	}, result, "The result do not match")
+	assert.Len(t, base, 3, "The base schema should not be modified")
+	assert.Equal(t, Field{Key: "email"}, base["useremail"], "The base schema should not be modified")
+}
+
+func TestSchemaMerge(t *testing.T) {
+	audit := Schema{
+		"created_at": Field{Key: "created_at"},
+		"updated_at": Field{Key: "updated_at"},
+	}
+	user := Schema{
+		"name":       Field{Key: "name"},
+		"created_at": Field{Key: "created_at", As: "string"},
+	}
+
+	assert.Equal(t, Schema{
+		"name":       Field{Key: "name"},
+		"created_at": Field{Key: "created_at", As: "string"},
+		"updated_at": Field{Key: "updated_at"},
+	}, user.Merge(audit, false), "The entries of the schema should be kept")
+	assert.Equal(t, Schema{
+		"name":       Field{Key: "name"},
+		"created_at": Field{Key: "created_at"},
+		"updated_at": Field{Key: "updated_at"},
+	}, user.Merge(audit, true), "The entries of the other schema should replace the entries")
+	assert.Equal(t, user.Merge(audit, false), user.Extend(audit), "The extending schema should take precedence")
+	assert.Equal(t, Schema{"name": Field{Key: "name"}}, user.Merge(Schema{"created_at": Tombstone()}, true), "A tombstone should remove the entry")
+	assert.Len(t, user, 2, "The schema should not be modified")
+	assert.Len(t, audit, 2, "The other schema should not be modified")
+}
+
+func TestConditionalNestedSchema(t *testing.T) {
+	m := New()
+
+	schema := Schema{
+		"method": Field{Key: "payment_method"},
+		"details": Field{
+			Key: "payment_details",
+			Value: func(parent interface{}) Schema {
+				if parent.(map[string]interface{})["payment_method"] == "card" {
+					return Schema{"last4": Field{Key: "card_number", Mask: "last4"}}
+				}
+
+				return Schema{"bank": Field{Key: "bank_name"}}
+			},
+		},
+	}
+
+	result, err := m.Transform([]map[string]interface{}{
+		{
+			"payment_method":  "card",
+			"payment_details": map[string]interface{}{"card_number": "4111111111111111", "bank_name": "Bank"},
+		},
+		{
+			"payment_method":  "transfer",
+			"payment_details": map[string]interface{}{"card_number": "4111111111111111", "bank_name": "Bank"},
+		},
+	}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, []Result{
+		{"method": "card", "details": Result{"last4": "************1111"}},
+		{"method": "transfer", "details": Result{"bank": "Bank"}},
+	}, result, "The result do not match")
+}
+
+func TestSchemaFinalize(t *testing.T) {
+	m := New()
+
+	address := Schema{
+		"code": Field{Key: "postal_code"},
+	}.WithFinalize(func(r Result) (Result, error) {
+		r["country"] = "ID"
+		return r, nil
+	})
+
+	schema := Schema{
+		"username": Field{Key: "name"},
+		"address":  Field{Key: "user_address", Value: address},
+	}.WithFinalize(func(r Result) (Result, error) {
+		r["display"] = r["username"].(string) + " (" + r["address"].(Result)["code"].(string) + ")"
+		return r, nil
+	})
+
+	result, err := m.Transform(User{
+		Name:    "John doe",
+		Address: UserAddress{PostalCode: "809120"},
+	}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"username": "John doe",
+		"display":  "John doe (809120)",
+		"address":  Result{"code": "809120", "country": "ID"},
+	}, result, "The result do not match")
+
+	failing := Schema{"username": Field{Key: "name"}}.WithFinalize(func(r Result) (Result, error) {
+		return nil, assert.AnError
+	})
+
+	_, err = m.Transform(map[string]interface{}{"name": "John doe", "": "empty"}, failing)
+
+	assert.Equal(t, assert.AnError, err, "Finalize error should be returned")
+
+	_, err = m.Transform(User{Name: "John doe"}, schema.Override(Schema{"$finalize": Field{Key: "name"}}))
+
+	assert.True(t, errors.Is(err, ErrInvalidSchema), "A field under the reserved key should be rejected")
+
+	err = json.Unmarshal([]byte(`{"$finalize": {"key": "name"}}`), &Schema{})
+
+	assert.EqualError(t, err, `Invalid schema: the key "$finalize" is reserved`)
+}
+
+func TestKeyedNestedSchema(t *testing.T) {
+	m := New()
+
+	schema := Schema{
+		"preferences": Field{
+			Key: "settings",
+			Value: map[string]Schema{
+				"notifications": {"email": Field{Key: "email_enabled"}},
+				"billing":       {"plan": Field{Key: "plan_name"}},
+			},
+		},
+	}
+
+	result, err := m.Transform(map[string]interface{}{
+		"settings": map[string]interface{}{
+			"notifications": map[string]interface{}{"email_enabled": true, "sms_enabled": false},
+			"billing":       map[string]interface{}{"plan_name": "pro", "card": "4111"},
+			"internal":      map[string]interface{}{"flag": true},
+		},
+	}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"preferences": Result{
+			"notifications": Result{"email": true},
+			"billing":       Result{"plan": "pro"},
+		},
+	}, result, "The result do not match")
+
+	assert.Equal(t, Result{
+		"preferences": Result{
+			"key": "settings",
+			"keys": Result{
+				"notifications": Result{"fields": Result{"email": Result{"key": "email_enabled"}}},
+				"billing":       Result{"fields": Result{"plan": Result{"key": "plan_name"}}},
+			},
+		},
+	}, schema.Describe(), "The description do not match")
+
+	_, err = m.Transform(User{Address: UserAddress{PostalCode: "1"}}, Schema{
+		"address": Field{Key: "user_address", Value: map[string]Schema{}},
+	})
+
+	assert.True(t, errors.Is(err, ErrSchemaMismatch), "Keyed schemas on a struct should return mismatch error")
+}
+
+func TestKeyedNestedSchemaMapKeys(t *testing.T) {
+	schema := Schema{
+		"tiers": Field{Key: "tiers", Value: map[string]Schema{
+			"1": {"name": Field{Key: "name"}},
+			"2": {"name": Field{Key: "name"}},
+		}},
+	}
+
+	result, err := New().Transform(map[string]interface{}{
+		"tiers": map[int]map[string]interface{}{
+			1: {"name": "bronze"},
+			2: {"name": "silver"},
+			3: {"name": "gold"},
+		},
+	}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"tiers": Result{"1": Result{"name": "bronze"}, "2": Result{"name": "silver"}},
+	}, result, "Non-string keys should be formatted")
+
+	_, err = New().Transform(map[string]interface{}{
+		"tiers": map[interface{}]interface{}{
+			1:   map[string]interface{}{"name": "bronze"},
+			"1": map[string]interface{}{"name": "silver"},
+		},
+	}, schema)
+
+	assert.True(t, errors.Is(err, ErrDuplicateKey), "Keys formatted into the same key should return an error")
+}
+
+func TestFirstAndLast(t *testing.T) {
+	m := New()
+
+	permission := Schema{"name": Field{Key: "permission_name"}}
+
+	schema := Schema{
+		"primary_permission": First("permissions", permission),
+		"latest_permission":  Last("permissions", permission),
+	}
+
+	result, err := m.Transform(User{
+		Permissions: []Permission{{"Admin", 0}, {"Customer", 1}, {"Seller", 2}},
+	}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"primary_permission": Result{"name": "Admin"},
+		"latest_permission":  Result{"name": "Seller"},
+	}, result, "The result do not match")
+
+	result, err = m.Transform(User{Permissions: []Permission{}}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{}, result, "Empty collections should be omitted")
+}
+
+func TestBreakingChanges(t *testing.T) {
+	v1 := Schema{
+		"username": Field{Key: "name"},
+		"permissions": Field{Key: "permissions", Value: Schema{
+			"code": Field{Key: "permission_code"},
+			"name": Field{Key: "permission_name"},
+		}},
+	}
+
+	v2 := Schema{
+		"email": Field{Key: "email"},
+		"permissions": Field{Key: "permissions", Value: Schema{
+			"code": Field{Key: "permission_code", As: "string"},
+		}},
+	}
+
+	assert.Equal(t, []string{
+		"permissions.code: retyped from value to string",
+		"permissions.name: removed",
+		"username: removed",
+	}, v1.BreakingChanges(v2), "The breaking changes do not match")
+	assert.Empty(t, v1.BreakingChanges(v1.Override(Schema{"email": Field{Key: "email"}})), "Added keys are not breaking changes")
+
+	v3 := Schema{
+		"history":    Field{Key: "history"},
+		"created_at": Field{Key: "created_at"},
+		"id":         Field{Key: "id"},
+	}
+
+	v4 := Schema{
+		"history":    Field{Key: "history", MapAs: MapTimeSeries},
+		"created_at": Field{Key: "created_at", Date: DateLong},
+		"id":         Field{Key: "id", LargeInts: LargeIntString},
+	}
+
+	assert.Equal(t, []string{
+		"created_at: retyped from value to string",
+		"history: retyped from value to list",
+		"id: retyped from value to value or string",
+	}, v3.BreakingChanges(v4), "Options changing the emitted type should be breaking changes")
+
+	v5 := Schema{
+		"address.city": Field{Key: "city"},
+		"address.code": Field{Key: "code"},
+	}
+
+	v6 := Schema{
+		"address": Field{Key: "address", Value: Schema{
+			"city": Field{Key: "town"},
+			"code": Field{Key: "code", As: "int"},
+		}},
+	}
+
+	assert.Equal(t, []string{
+		"address.code: retyped from value to int",
+	}, v5.BreakingChanges(v6), "Dotted keys should be compared with the nested objects they are emitted in")
+	assert.Equal(t, []string{
+		"address.code: retyped from int to value",
+	}, v6.BreakingChanges(v5), "Nested objects should be compared with the dotted keys emitted in them")
+}
@@ -0,0 +1,320 @@
+package mantau
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+// SecretScanner inspects the emitted strings for secrets, see Options.SecretScanner
+type SecretScanner interface {
+	// Scan will return the name of the rule matching the value, if any
+	Scan(value string) (string, bool)
+}
+
+// SecretRule is a named pattern of a secret e.g. an AWS access key
+type SecretRule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// DefaultSecretRules match common credentials: cloud provider keys, API tokens, private keys and JWTs
+var DefaultSecretRules = []SecretRule{
+	{Name: "aws_access_key", Pattern: regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{Name: "github_token", Pattern: regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}`)},
+	{Name: "slack_token", Pattern: regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{Name: "stripe_key", Pattern: regexp.MustCompile(`\b[rs]k_live_[A-Za-z0-9]{16,}`)},
+	{Name: "google_api_key", Pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`)},
+	{Name: "private_key", Pattern: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	{Name: "jwt", Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+}
+
+// ruleScanner is a SecretScanner matching a list of rules
+type ruleScanner []SecretRule
+
+// NewSecretScanner will create a scanner matching the given rules, or DefaultSecretRules when there are none
+func NewSecretScanner(rules ...SecretRule) SecretScanner {
+	if len(rules) == 0 {
+		rules = DefaultSecretRules
+	}
+
+	return ruleScanner(rules)
+}
+
+// Scan will return the name of the first rule matching the value
+func (s ruleScanner) Scan(value string) (string, bool) {
+	for _, rule := range s {
+		if rule.Pattern.MatchString(value) {
+			return rule.Name, true
+		}
+	}
+
+	return "", false
+}
+
+// scanSecrets will scan the strings of a value assigned into a result, walking collections, maps and their keys,
+// pointers and the exported fields of structs, so named string types and raw values are scanned too. A secret is replaced
+// by Redacted with SecretRedact, the containers holding it are copied so the source is not modified.
+// Otherwise an ErrSecretDetected error is returned without the value
+func (m *mantau) scanSecrets(value interface{}) (interface{}, error) {
+	if m.opt.SecretScanner == nil || value == nil {
+		return value, nil
+	}
+
+	scanned, changed, err := m.scanValue(reflect.ValueOf(value), map[uintptr]bool{})
+
+	if err != nil || !changed {
+		return value, err
+	}
+
+	return scanned.Interface(), nil
+}
+
+// scannedObject will scan the keys of a transformed object and record that it was scanned, every value is
+// scanned when it's assigned, so the objects holding it don't scan it again. The object returned by the schema
+// post-processor is scanned again as a whole, it may have added or replaced any value
+func (m *mantau) scannedObject(result Result, schema Schema) (Result, error) {
+	if m.state == nil || result == nil {
+		return result, nil
+	}
+
+	var (
+		scanned interface{} = result
+		err     error
+	)
+
+	if _, ok := schema[finalizeKey]; ok {
+		m.state.rescan = true
+		scanned, err = m.scanSecrets(result)
+		m.state.rescan = false
+	} else {
+		scanned, err = m.scanKeys(result)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	result = scanned.(Result)
+
+	if m.state.scanned == nil {
+		m.state.scanned = map[uintptr]Result{}
+	}
+
+	m.state.scanned[reflect.ValueOf(result).Pointer()] = result
+
+	return result, nil
+}
+
+// scanValue will scan a value, the returned value is only set when a secret was redacted.
+// The pointers, maps and slices being scanned are tracked, so cyclic values are not scanned forever.
+// The transformed objects already scanned are skipped
+func (m *mantau) scanValue(v reflect.Value, visited map[uintptr]bool) (reflect.Value, bool, error) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if v.IsNil() || visited[v.Pointer()] {
+			return v, false, nil
+		}
+
+		if v.Kind() == reflect.Map && m.state != nil && !m.state.rescan {
+			if _, ok := m.state.scanned[v.Pointer()]; ok {
+				return v, false, nil
+			}
+		}
+
+		visited[v.Pointer()] = true
+		defer delete(visited, v.Pointer())
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		rule, found := m.opt.SecretScanner.Scan(v.String())
+
+		if !found {
+			return v, false, nil
+		}
+
+		if m.opt.Secrets == SecretRedact {
+			return reflect.ValueOf(Redacted).Convert(v.Type()), true, nil
+		}
+
+		return v, false, fmt.Errorf("%w: the value matches the %s rule", ErrSecretDetected, rule)
+	case reflect.Interface:
+		if v.IsNil() {
+			return v, false, nil
+		}
+
+		elem, changed, err := m.scanValue(v.Elem(), visited)
+
+		if err != nil || !changed {
+			return v, false, err
+		}
+
+		scanned := reflect.New(v.Type()).Elem()
+		scanned.Set(elem)
+
+		return scanned, true, nil
+	case reflect.Ptr:
+		elem, changed, err := m.scanValue(v.Elem(), visited)
+
+		if err != nil || !changed {
+			return v, false, err
+		}
+
+		scanned := reflect.New(v.Type().Elem())
+		scanned.Elem().Set(elem)
+
+		return scanned, true, nil
+	case reflect.Slice, reflect.Array:
+		var scanned reflect.Value
+
+		for i := 0; i < v.Len(); i++ {
+			elem, changed, err := m.scanValue(v.Index(i), visited)
+
+			if err != nil {
+				return v, false, err
+			}
+
+			if !changed {
+				continue
+			}
+
+			if !scanned.IsValid() {
+				scanned = copyCollection(v)
+			}
+
+			scanned.Index(i).Set(elem)
+		}
+
+		return scanned, scanned.IsValid(), nil
+	case reflect.Map:
+		var scanned reflect.Value
+		iter := v.MapRange()
+
+		for iter.Next() {
+			key, keyChanged, err := m.scanKey(iter.Key())
+
+			if err != nil {
+				return v, false, err
+			}
+
+			elem, changed, err := m.scanValue(iter.Value(), visited)
+
+			if err != nil {
+				return v, false, err
+			}
+
+			if !changed && !keyChanged {
+				continue
+			}
+
+			if !scanned.IsValid() {
+				scanned = reflect.MakeMapWithSize(v.Type(), v.Len())
+
+				for _, key := range v.MapKeys() {
+					scanned.SetMapIndex(key, v.MapIndex(key))
+				}
+			}
+
+			if keyChanged {
+				scanned.SetMapIndex(iter.Key(), reflect.Value{})
+			}
+
+			scanned.SetMapIndex(key, elem)
+		}
+
+		return scanned, scanned.IsValid(), nil
+	case reflect.Struct:
+		var scanned reflect.Value
+
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+
+			elem, changed, err := m.scanValue(v.Field(i), visited)
+
+			if err != nil {
+				return v, false, err
+			}
+
+			if !changed {
+				continue
+			}
+
+			if !scanned.IsValid() {
+				scanned = reflect.New(v.Type()).Elem()
+				scanned.Set(v)
+			}
+
+			scanned.Field(i).Set(elem)
+		}
+
+		return scanned, scanned.IsValid(), nil
+	}
+
+	return v, false, nil
+}
+
+// scanKey will scan the formatted key of a map, e.g. a secret turned into a key by MapInvert. A string key is
+// replaced by Redacted with SecretRedact, other keys cannot be replaced and return an ErrSecretDetected error
+func (m *mantau) scanKey(key reflect.Value) (reflect.Value, bool, error) {
+	rule, found := m.opt.SecretScanner.Scan(formatMapKey(key, m.opt.TimeKeyLayout))
+
+	if !found {
+		return key, false, nil
+	}
+
+	if m.opt.Secrets == SecretRedact && key.Kind() == reflect.String {
+		return reflect.ValueOf(Redacted).Convert(key.Type()), true, nil
+	}
+
+	return key, false, fmt.Errorf("%w: a key matches the %s rule", ErrSecretDetected, rule)
+}
+
+// scanKeys will scan the keys of a transformed object, the object is copied when a key is redacted
+func (m *mantau) scanKeys(result Result) (Result, error) {
+	scanned, copied := result, false
+
+	for key, value := range result {
+		redacted, changed, err := m.scanKey(reflect.ValueOf(key))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !changed {
+			continue
+		}
+
+		if !copied {
+			scanned = make(Result, len(result))
+
+			for k, v := range result {
+				scanned[k] = v
+			}
+
+			copied = true
+		}
+
+		delete(scanned, key)
+		scanned[redacted.String()] = value
+	}
+
+	return scanned, nil
+}
+
+// copyCollection will copy a slice or an array into a new value of the same type
+func copyCollection(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Array {
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+
+		return c
+	}
+
+	c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+	reflect.Copy(c, v)
+
+	return c
+}
@@ -0,0 +1,311 @@
+package mantau
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// state stores the progress of a single transformation. A mantau instance is copied
+// for every call that needs a state, so the state is never shared between calls
+type state struct {
+	// frames are the nested fields and collection elements entered, the paths are only built from them
+	// when they are needed e.g. by an error
+	frames []frame
+
+	// segment is the depth the current goroutine started transforming at, see onNewStack
+	segment int
+
+	coverage *coverage
+
+	// trace records every mapped field with TransformTrace and Explain
+	trace *Trace
+
+	// errors are the field errors collected with Options.CollectErrors
+	errors []*FieldError
+
+	// ctx is the context passed to TransformCtx
+	ctx context.Context
+
+	// memo stores the transformed pointers with Options.Memoize
+	memo map[memoKey]interface{}
+
+	// hashes stores the hash of the cached schemas, by schema pointer
+	hashes map[uintptr]string
+
+	// optionsHash stores the hash of the options of the cache keys, see optionsHash
+	optionsHash *string
+
+	// provenance wraps every value with it's source, see TransformProvenance
+	provenance bool
+
+	// size is the estimated size of the result with Options.MaxResultBytes
+	size int
+
+	// truncated reports that the result exceeded Options.MaxResultBytes with LimitTruncate
+	truncated bool
+
+	// scanned stores the objects whose values were scanned with Options.SecretScanner, by map pointer.
+	// The objects are stored so their pointers are not reused by other maps
+	scanned map[uintptr]Result
+
+	// rescan reports that the scanned objects are scanned again, see scannedObject
+	rescan bool
+
+	// included stores the shared entities of TransformIncluded
+	included *included
+
+	// deprecations stores the deprecated fields emitted with TransformDeprecations, by path
+	deprecations map[string]string
+}
+
+// frame is a nested field or a collection element entered by the transformation
+type frame struct {
+	key       string
+	sourceKey string
+
+	// index is the index of a collection element, or -1 for a field
+	index int
+}
+
+// coverage records which schema and source keys were visited and used
+type coverage struct {
+	schemaSeen map[string]bool
+	schemaUsed map[string]bool
+	sourceSeen map[string]bool
+	sourceUsed map[string]bool
+}
+
+// stateful is an instance copy together with it's state, so withState allocates them at once.
+// The frames of shallow values are stored in the array without growing the slice
+type stateful struct {
+	m      mantau
+	s      state
+	frames [8]frame
+}
+
+// withState will create a copy of the instance with a new transformation state
+func (m *mantau) withState() *mantau {
+	c := &stateful{}
+
+	m.mu.RLock()
+	c.m = *m
+	m.mu.RUnlock()
+
+	c.s.frames = c.frames[:0]
+	c.m.state = &c.s
+
+	return &c.m
+}
+
+// path will return the output path of the value being transformed e.g. "permissions[0].code"
+func (s *state) path() string {
+	return s.join(func(f frame) string { return f.key })
+}
+
+// sourcePath will return the source path of the value being transformed e.g. "permissions[0].permission_code"
+func (s *state) sourcePath() string {
+	return s.join(func(f frame) string { return f.sourceKey })
+}
+
+// join will build a path from the keys of the frames
+func (s *state) join(key func(frame) string) string {
+	path := ""
+
+	for _, f := range s.frames {
+		if f.index >= 0 {
+			path += "[" + strconv.Itoa(f.index) + "]"
+		} else {
+			path = joinPath(path, key(f))
+		}
+	}
+
+	return path
+}
+
+// joinPath will append a key to a dotted path
+func joinPath(base, key string) string {
+	if base == "" {
+		return key
+	}
+
+	return base + "." + key
+}
+
+// pathLess will order two paths, the collection indexes are compared as numbers so "items[2]"
+// comes before "items[10]"
+func pathLess(a, b string) bool {
+	i, j := 0, 0
+
+	for i < len(a) && j < len(b) {
+		if a[i] == '[' && b[j] == '[' {
+			endA, endB := digitsEnd(a, i+1), digitsEnd(b, j+1)
+
+			if endA > i+1 && endB > j+1 {
+				x, y := a[i+1:endA], b[j+1:endB]
+
+				if len(x) != len(y) {
+					return len(x) < len(y)
+				}
+
+				if x != y {
+					return x < y
+				}
+
+				i, j = endA, endB
+				continue
+			}
+		}
+
+		if a[i] != b[j] {
+			return a[i] < b[j]
+		}
+
+		i++
+		j++
+	}
+
+	return len(a)-i < len(b)-j
+}
+
+// digitsEnd will return the position after the digits of the path starting at i
+func digitsEnd(path string, i int) int {
+	for i < len(path) && path[i] >= '0' && path[i] <= '9' {
+		i++
+	}
+
+	return i
+}
+
+// stripIndexes will remove the collection indexes from a path, so "permissions[0].code"
+// becomes "permissions.code"
+func stripIndexes(path string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(path); i++ {
+		if path[i] == '[' {
+			if end := strings.IndexByte(path[i:], ']'); end >= 0 {
+				i += end
+				continue
+			}
+		}
+
+		b.WriteByte(path[i])
+	}
+
+	return strings.TrimPrefix(b.String(), ".")
+}
+
+// enter will move the state into a nested field, the previous position is restored with leave
+func (m *mantau) enter(key, sourceKey string) {
+	if m.state != nil {
+		m.state.frames = append(m.state.frames, frame{key: key, sourceKey: sourceKey, index: -1})
+	}
+}
+
+// enterIndex will move the state into an element of a collection, the previous position is restored with leave.
+// An element is a level of depth like a nested field, so nested collections are limited by the maximum depth too
+func (m *mantau) enterIndex(i int) {
+	if m.state != nil {
+		m.state.frames = append(m.state.frames, frame{index: i})
+	}
+}
+
+// leave will restore the position of the state before the last nested field or collection element entered
+func (m *mantau) leave() {
+	if m.state != nil {
+		m.state.frames = m.state.frames[:len(m.state.frames)-1]
+	}
+}
+
+// checkDepth will return an error when the current field is nested deeper than the maximum depth
+func (m *mantau) checkDepth() error {
+	if m.state == nil {
+		return nil
+	}
+
+	max := m.opt.MaxDepth
+
+	if max <= 0 {
+		max = DefaultMaxDepth
+	}
+
+	if len(m.state.frames) > max {
+		return fmt.Errorf("%w: %d levels at %q", ErrMaxDepth, max, m.state.path())
+	}
+
+	return nil
+}
+
+// stackSegment is the number of levels of nested values transformed on the stack of a goroutine
+const stackSegment = 64
+
+// stackFull will check if the current goroutine transformed stackSegment levels of nested values
+func (m *mantau) stackFull() bool {
+	return m.state != nil && len(m.state.frames)-m.state.segment >= stackSegment
+}
+
+// onNewStack will call fn on a new goroutine and wait for it, so the levels of a deeply nested value are split
+// across goroutine stacks instead of growing a single stack until the runtime aborts the process.
+// The state is only used by one goroutine at a time, a panic of fn is raised again on the calling goroutine
+func (m *mantau) onNewStack(fn func() (interface{}, error)) (interface{}, error) {
+	var (
+		value     interface{}
+		err       error
+		recovered interface{}
+		done      = make(chan struct{})
+	)
+
+	segment := m.state.segment
+	m.state.segment = len(m.state.frames)
+
+	go func() {
+		defer close(done)
+		defer func() { recovered = recover() }()
+
+		value, err = fn()
+	}()
+
+	<-done
+	m.state.segment = segment
+
+	if recovered != nil {
+		panic(recovered)
+	}
+
+	return value, err
+}
+
+// visitObject will record the schema keys and the source keys of an object being transformed
+func (m *mantau) visitObject(schema Schema, sourceKeys []string) {
+	if m.state == nil || m.state.coverage == nil {
+		return
+	}
+
+	c := m.state.coverage
+
+	for key, field := range schema {
+		if key != wildcardKey && m.readsKey(field) {
+			c.schemaSeen[stripIndexes(joinPath(m.state.path(), key))] = true
+		}
+	}
+
+	for _, key := range sourceKeys {
+		if key == "" {
+			continue
+		}
+
+		c.sourceSeen[stripIndexes(joinPath(m.state.sourcePath(), key))] = true
+	}
+}
+
+// use will record a schema key matching a source key
+func (m *mantau) use(key, sourceKey string) {
+	if m.state == nil || m.state.coverage == nil {
+		return
+	}
+
+	m.state.coverage.schemaUsed[stripIndexes(joinPath(m.state.path(), key))] = true
+	m.state.coverage.sourceUsed[stripIndexes(joinPath(m.state.sourcePath(), sourceKey))] = true
+}
@@ -0,0 +1,113 @@
+package mantau
+
+import (
+	"errors"
+	"runtime/debug"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Node struct {
+	Name string `json:"name"`
+	Next *Node  `json:"next"`
+}
+
+func TestMaxDepth(t *testing.T) {
+	schema := Schema{
+		"name": Field{Key: "name"},
+	}
+	schema["next"] = Field{Key: "next", Value: schema}
+
+	cyclic := &Node{Name: "first"}
+	cyclic.Next = &Node{Name: "second", Next: cyclic}
+
+	m := New()
+
+	_, err := m.Transform(*cyclic, schema)
+
+	assert.True(t, errors.Is(err, ErrMaxDepth), "Cyclic data should return max depth error")
+
+	m.SetOpt(&Options{Hook: "json", MaxDepth: 2})
+
+	result, err := m.Transform(Node{Name: "a", Next: &Node{Name: "b", Next: &Node{Name: "c"}}}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"name": "a",
+		"next": Result{"name": "b", "next": Result{"name": "c"}},
+	}, result, "The result do not match")
+
+	_, err = m.Transform(Node{Name: "a", Next: &Node{Name: "b", Next: &Node{Name: "c", Next: &Node{Name: "d"}}}}, schema)
+
+	assert.True(t, errors.Is(err, ErrMaxDepth), "Data deeper than the maximum depth should return error")
+	assert.Contains(t, err.Error(), `"next.next.next"`, "The error should describe the path")
+}
+
+func TestPathLess(t *testing.T) {
+	paths := []string{"items[10].qty", "items", "items[2].name", "items[2]", "items[1].qty", "items.qty", "id"}
+
+	sort.Slice(paths, func(i, j int) bool {
+		return pathLess(paths[i], paths[j])
+	})
+
+	assert.Equal(t, []string{"id", "items", "items.qty", "items[1].qty", "items[2]", "items[2].name", "items[10].qty"}, paths)
+}
+
+func TestMaxDepthCollections(t *testing.T) {
+	var nested interface{} = "leaf"
+
+	for i := 0; i < 5000; i++ {
+		nested = []interface{}{nested}
+	}
+
+	_, err := New().TransformAny(nested, Schema{})
+
+	assert.True(t, errors.Is(err, ErrMaxDepth), "Deeply nested collections should return max depth error")
+
+	cyclic := []interface{}{"a", nil}
+	cyclic[1] = cyclic
+
+	_, err = New().TransformAny(cyclic, Schema{})
+
+	assert.True(t, errors.Is(err, ErrMaxDepth), "Self referencing collections should return max depth error")
+
+	m := New().With(WithMaxDepth(2))
+	result, err := m.TransformAny([]interface{}{[]interface{}{[]interface{}{1}}}, Schema{})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, []interface{}{[]interface{}{[]interface{}{1}}}, result, "The result do not match")
+
+	_, err = m.TransformAny([]interface{}{[]interface{}{[]interface{}{[]interface{}{1}}}}, Schema{})
+
+	assert.True(t, errors.Is(err, ErrMaxDepth), "Collections deeper than the maximum depth should return error")
+}
+
+func TestMaxDepthStack(t *testing.T) {
+	// a single goroutine stack cannot hold the recursion of thousands of levels
+	defer debug.SetMaxStack(debug.SetMaxStack(8 << 20))
+
+	head := &Node{Name: "0"}
+	node := head
+
+	for i := 1; i < 20000; i++ {
+		node.Next = &Node{Name: "next"}
+		node = node.Next
+	}
+
+	schema := Schema{"name": Field{Key: "name"}}
+	schema["next"] = Field{Key: "next", Value: schema}
+
+	result, err := New().With(WithMaxDepth(50000)).Transform(*head, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+
+	levels := 0
+
+	for object, ok := result.(Result); ok; object, ok = object["next"].(Result) {
+		levels++
+	}
+
+	assert.Equal(t, 20000, levels, "Every level should be transformed")
+}
@@ -0,0 +1,52 @@
+package mantau
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// TransformStream will decode a JSON array from the reader one element at a time.
+// For every element, choose is called to pick the schema based on the decoded value (e.g. it's type field)
+// and fn is called with the transformed element. Elements are skipped when choose returns nil
+func (m *mantau) TransformStream(r io.Reader, choose func(element interface{}) Schema, fn func(result interface{}) error) error {
+	decoder := json.NewDecoder(r)
+
+	token, err := decoder.Token()
+
+	if err != nil {
+		return err
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return errors.New("Stream should be a JSON array")
+	}
+
+	for decoder.More() {
+		var element interface{}
+
+		if err := decoder.Decode(&element); err != nil {
+			return err
+		}
+
+		schema := choose(element)
+
+		if schema == nil {
+			continue
+		}
+
+		result, err := m.Transform(element, schema)
+
+		if err != nil {
+			return err
+		}
+
+		if err := fn(result); err != nil {
+			return err
+		}
+	}
+
+	_, err = decoder.Token()
+
+	return err
+}
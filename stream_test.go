@@ -0,0 +1,54 @@
+package mantau
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformStream(t *testing.T) {
+	m := New()
+
+	input := `[
+		{"type": "click", "target": "button", "x": 10},
+		{"type": "view", "page": "/home", "referrer": null},
+		{"type": "unknown"}
+	]`
+
+	schemas := map[string]Schema{
+		"click": {"kind": Field{Key: "type"}, "element": Field{Key: "target"}},
+		"view":  {"kind": Field{Key: "type"}, "path": Field{Key: "page"}, "from": Field{Key: "referrer"}},
+	}
+
+	results := []interface{}{}
+
+	err := m.TransformStream(strings.NewReader(input), func(element interface{}) Schema {
+		return schemas[element.(map[string]interface{})["type"].(string)]
+	}, func(result interface{}) error {
+		results = append(results, result)
+		return nil
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, []interface{}{
+		Result{"kind": "click", "element": "button"},
+		Result{"kind": "view", "path": "/home"},
+	}, results, "The result do not match")
+
+	err = m.TransformStream(strings.NewReader(`{"type": "click"}`), func(interface{}) Schema {
+		return nil
+	}, func(interface{}) error {
+		return nil
+	})
+
+	assert.Error(t, err, "Non array input should return error")
+
+	err = m.TransformStream(strings.NewReader(`[{"type": "click"}, {`), func(interface{}) Schema {
+		return schemas["click"]
+	}, func(interface{}) error {
+		return assert.AnError
+	})
+
+	assert.Equal(t, assert.AnError, err, "Callback error should stop the stream")
+}
@@ -0,0 +1,63 @@
+package mantau
+
+import (
+	"reflect"
+	"sync"
+)
+
+// tagsKey identifies the tags of a struct type read with a hook, so instances using
+// different hooks e.g. "json" and "db" don't share their cached tags
+type tagsKey struct {
+	t    reflect.Type
+	hook string
+}
+
+// tagSet stores the hook tags of a struct type and their parsed names and options.
+// The names of fields without the tag or tagged "-" are empty
+type tagSet struct {
+	tags    []string
+	names   []string
+	options []Field
+
+	// tagged reports that every field has the tag, so the names don't depend on Options.Naming
+	tagged bool
+}
+
+// tagsCache stores the tags of every struct type by tagsKey, it's shared by every instance
+var tagsCache sync.Map
+
+// structTags will return the hook tag of every field of a struct type, empty for fields without the tag.
+// The tags are read once per struct type and hook
+func structTags(t reflect.Type, hook string) []string {
+	return loadTags(t, hook).tags
+}
+
+// loadTags will return the tags of a struct type, they are read and parsed once per struct type and hook
+func loadTags(t reflect.Type, hook string) *tagSet {
+	key := tagsKey{t: t, hook: hook}
+
+	if set, ok := tagsCache.Load(key); ok {
+		return set.(*tagSet)
+	}
+
+	set := &tagSet{
+		tags:    make([]string, t.NumField()),
+		names:   make([]string, t.NumField()),
+		options: make([]Field, t.NumField()),
+		tagged:  true,
+	}
+
+	for i := range set.tags {
+		tag := t.Field(i).Tag.Get(hook)
+		set.tags[i] = tag
+		set.tagged = set.tagged && tag != ""
+
+		if tag != "" && tag != "-" {
+			set.names[i], set.options[i] = parseTag(tag)
+		}
+	}
+
+	tagsCache.Store(key, set)
+
+	return set
+}
@@ -0,0 +1,36 @@
+package mantau
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type HookedUser struct {
+	Name  string `json:"name" db:"full_name"`
+	Email string `json:"email"`
+}
+
+func TestStructTagsByHook(t *testing.T) {
+	typ := reflect.TypeOf(HookedUser{})
+
+	assert.Equal(t, []string{"name", "email"}, structTags(typ, "json"))
+	assert.Equal(t, []string{"full_name", ""}, structTags(typ, "db"))
+	assert.Equal(t, []string{"name", "email"}, structTags(typ, "json"), "The cached tags of a hook should not be replaced by another hook")
+
+	jsonResult, err := New().Transform(HookedUser{Name: "John", Email: "john@example.com"}, Schema{
+		"name": Field{Key: "name"},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John"}, jsonResult)
+
+	dbResult, err := New().With(WithHook("db"), WithNaming(SnakeCase)).Transform(HookedUser{Name: "John", Email: "john@example.com"}, Schema{
+		"name":  Field{Key: "full_name"},
+		"email": Field{Key: "email"},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John", "email": "john@example.com"}, dbResult)
+}
@@ -0,0 +1,42 @@
+package mantau
+
+import (
+	htmltemplate "html/template"
+	"io"
+	"text/template"
+)
+
+// RenderTemplate will transform the source and execute the text/template with the result as it's data,
+// so emails and reports can be generated with the same schemas used for APIs
+func (m *mantau) RenderTemplate(src interface{}, schema Schema, tmpl string, w io.Writer) error {
+	t, err := template.New("mantau").Parse(tmpl)
+
+	if err != nil {
+		return err
+	}
+
+	result, err := m.Transform(src, schema)
+
+	if err != nil {
+		return err
+	}
+
+	return t.Execute(w, result)
+}
+
+// RenderHTMLTemplate works like RenderTemplate but executes a html/template, which escapes the values
+func (m *mantau) RenderHTMLTemplate(src interface{}, schema Schema, tmpl string, w io.Writer) error {
+	t, err := htmltemplate.New("mantau").Parse(tmpl)
+
+	if err != nil {
+		return err
+	}
+
+	result, err := m.Transform(src, schema)
+
+	if err != nil {
+		return err
+	}
+
+	return t.Execute(w, result)
+}
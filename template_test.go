@@ -0,0 +1,43 @@
+package mantau
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	user := User{
+		Name:        "<John doe>",
+		Permissions: []Permission{{"Admin", 1}, {"Customer", 2}},
+	}
+
+	schema := Schema{
+		"username": Field{Key: "name"},
+		"permissions": Field{
+			Key:   "permissions",
+			Value: Schema{"name": Field{Key: "permission_name"}},
+		},
+	}
+
+	tmpl := `Hi {{.username}}:{{range .permissions}} {{.name}}{{end}}`
+
+	var text strings.Builder
+
+	err := New().RenderTemplate(user, schema, tmpl, &text)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, "Hi <John doe>: Admin Customer", text.String(), "The output do not match")
+
+	var html strings.Builder
+
+	err = New().RenderHTMLTemplate(user, schema, tmpl, &html)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, "Hi &lt;John doe&gt;: Admin Customer", html.String(), "The output should be escaped")
+
+	err = New().RenderTemplate(user, schema, "{{.username", &text)
+
+	assert.Error(t, err, "Invalid template should return an error")
+}
@@ -0,0 +1,125 @@
+package mantau
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// RegisterOverlay will store the patch of a tenant over every version of the named schema, see Schema.Override.
+// Registering an overlay for the same tenant and name twice will replace the previous patch
+func (r *Registry) RegisterOverlay(tenant, name string, patch Schema) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.overlays[tenant]; !ok {
+		r.overlays[tenant] = map[string]Schema{}
+	}
+
+	r.overlays[tenant][name] = patch
+}
+
+// Tenant will find a schema by it's name and version with the overlay of the tenant applied,
+// the schema is returned as it is when the tenant doesn't have an overlay
+func (r *Registry) Tenant(tenant, name, version string) (Schema, bool) {
+	schema, ok := r.Get(name, version)
+
+	if !ok {
+		return nil, false
+	}
+
+	r.mu.RLock()
+	patch, ok := r.overlays[tenant][name]
+	r.mu.RUnlock()
+
+	if !ok {
+		return schema, true
+	}
+
+	return schema.Override(patch), true
+}
+
+// Latest will return the latest version registered under the given name, versions are compared
+// with their numbers as numbers so "v10" is later than "v2"
+func (r *Registry) Latest(name string) (string, bool) {
+	versions := r.Versions(name)
+
+	if len(versions) == 0 {
+		return "", false
+	}
+
+	latest := versions[0]
+
+	for _, version := range versions[1:] {
+		if compareVersions(version, latest) > 0 {
+			latest = version
+		}
+	}
+
+	return latest, true
+}
+
+// TransformTenant will transform the source with the latest version of the named schema from Options.Registry,
+// with the overlay of the tenant applied. Tenants customize the shape of the response with an overlay
+// instead of branching in the code
+func (m *mantau) TransformTenant(tenant string, src interface{}, name string) (interface{}, error) {
+	if m.opt.Registry == nil {
+		return nil, fmt.Errorf("Cannot transform the tenant %q without a registry", tenant)
+	}
+
+	version, ok := m.opt.Registry.Latest(name)
+
+	if !ok {
+		return nil, fmt.Errorf("Cannot find the schema %q", name)
+	}
+
+	schema, _ := m.opt.Registry.Tenant(tenant, name, version)
+
+	return m.Transform(src, schema)
+}
+
+// compareVersions will compare two versions, the runs of digits are compared as numbers
+// and the other characters one by one
+func compareVersions(a, b string) int {
+	for a != "" && b != "" {
+		da, db := digits(a), digits(b)
+
+		if da > 0 && db > 0 {
+			na, _ := strconv.Atoi(a[:da])
+			nb, _ := strconv.Atoi(b[:db])
+
+			if na != nb {
+				if na < nb {
+					return -1
+				}
+
+				return 1
+			}
+
+			a, b = a[da:], b[db:]
+			continue
+		}
+
+		if a[0] != b[0] {
+			if a[0] < b[0] {
+				return -1
+			}
+
+			return 1
+		}
+
+		a, b = a[1:], b[1:]
+	}
+
+	return len(a) - len(b)
+}
+
+// digits will return the length of the run of digits at the start of the string
+func digits(s string) int {
+	n := 0
+
+	for n < len(s) && s[n] >= '0' && s[n] <= '9' {
+		n++
+	}
+
+	return n
+}
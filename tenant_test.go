@@ -0,0 +1,58 @@
+package mantau
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformTenant(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("user", "v2", Schema{
+		"name":  Field{Key: "name"},
+		"email": Field{Key: "email"},
+		"phone": Field{Key: "phone"},
+	})
+	registry.Register("user", "v10", Schema{
+		"username": Field{Key: "name"},
+		"email":    Field{Key: "email"},
+		"phone":    Field{Key: "phone"},
+	})
+	registry.RegisterOverlay("acme", "user", Schema{
+		"email": Field{Key: "email", Mask: "first2"},
+		"phone": Tombstone(),
+	})
+
+	user := User{Name: "John doe", Email: "john@example.com", Phone: "555"}
+	m := New().With(WithRegistry(registry))
+
+	result, err := m.TransformTenant("acme", user, "user")
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"username": "John doe", "email": "jo**************"}, result, "The overlay should be applied to the latest version")
+
+	result, err = m.TransformTenant("globex", user, "user")
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"username": "John doe", "email": "john@example.com", "phone": "555"}, result, "A tenant without an overlay should use the base schema")
+
+	_, err = m.TransformTenant("acme", user, "order")
+
+	assert.EqualError(t, err, `Cannot find the schema "order"`)
+
+	_, err = New().TransformTenant("acme", user, "user")
+
+	assert.EqualError(t, err, `Cannot transform the tenant "acme" without a registry`)
+
+	schema, ok := registry.Tenant("acme", "user", "v2")
+
+	assert.True(t, ok, "The schema should be found")
+	assert.NotContains(t, schema, "phone", "The overlay should be applied to every version")
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.True(t, compareVersions("v10", "v2") > 0)
+	assert.True(t, compareVersions("v1.2", "v1.10") < 0)
+	assert.True(t, compareVersions("v2-beta", "v2") > 0)
+	assert.Equal(t, 0, compareVersions("2020-01", "2020-01"))
+}
@@ -0,0 +1,128 @@
+package mantau
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Trace describes how every field of a transformation was produced. It can be encoded as JSON
+// and attached to a bug report to replay exactly how a payload was built
+type Trace struct {
+	Entries []TraceEntry `json:"entries"`
+}
+
+// TraceEntry describes a single mapped field
+type TraceEntry struct {
+	// Source is the path of the source value e.g. "permissions[0].permission_code", empty for computed fields
+	Source string `json:"source"`
+
+	// Output is the path of the output key e.g. "user_permissions[0].code"
+	Output string `json:"output"`
+
+	// Kind is the kind of the source value
+	Kind Kind `json:"kind"`
+
+	// Type is the Go type of the source value
+	Type string `json:"type"`
+
+	// Outcome is what happened to the field
+	Outcome Outcome `json:"outcome,omitempty"`
+
+	// Transformers are the field options applied to the emitted value in order e.g. "normalize email" or "mask last4"
+	Transformers []string `json:"transformers,omitempty"`
+
+	// Coercion describes the coercion of the emitted value e.g. "float64 to string"
+	Coercion string `json:"coercion,omitempty"`
+}
+
+// TransformTrace works like Transform but also returns a trace of every mapped field ordered by it's output path,
+// including the fields left out of the result with their outcome
+func (m *mantau) TransformTrace(src interface{}, schema Schema) (interface{}, *Trace, error) {
+	c := m.withState()
+	c.state.trace = &Trace{Entries: []TraceEntry{}}
+
+	result, err := c.run(src, schema)
+
+	if err != nil {
+		return result, nil, err
+	}
+
+	entries := c.state.trace.Entries
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return pathLess(entries[i].Output, entries[j].Output)
+	})
+
+	return result, c.state.trace, nil
+}
+
+// record will add a mapped field and it's outcome to the trace
+func (m *mantau) record(key, sourceKey string, field Field, source interface{}, outcome Outcome) {
+	if m.state == nil || m.state.trace == nil {
+		return
+	}
+
+	e := TraceEntry{
+		Output:  joinPath(m.state.path(), key),
+		Kind:    m.getKind(source),
+		Type:    fmt.Sprintf("%T", source),
+		Outcome: outcome,
+	}
+
+	if sourceKey != "" {
+		e.Source = joinPath(m.state.sourcePath(), sourceKey)
+	}
+
+	if outcome == OutcomeEmitted {
+		e.Transformers = transformers(field)
+
+		if field.As != "" {
+			e.Coercion = fmt.Sprintf("%T to %s", source, field.As)
+		}
+	}
+
+	m.state.trace.Entries = append(m.state.trace.Entries, e)
+}
+
+// transformers will describe the options of the field changing it's value, in the order they are applied
+func transformers(field Field) []string {
+	var steps []string
+
+	if field.computed() {
+		steps = append(steps, "compute")
+	}
+
+	for _, op := range field.pipe {
+		steps = append(steps, "pipe "+op.Name)
+	}
+
+	if field.Transform != nil {
+		steps = append(steps, "transform")
+	}
+
+	if field.Convert != nil {
+		steps = append(steps, fmt.Sprintf("convert %s to %s", field.Convert.From, field.Convert.To))
+	}
+
+	if field.Date != "" {
+		steps = append(steps, fmt.Sprintf("date %s", field.Date))
+	}
+
+	if field.Normalize != "" {
+		steps = append(steps, fmt.Sprintf("normalize %s", field.Normalize))
+	}
+
+	if field.Round != nil {
+		steps = append(steps, fmt.Sprintf("round %d", field.Round.Places))
+	}
+
+	if field.Mask != "" {
+		steps = append(steps, fmt.Sprintf("mask %s", field.Mask))
+	}
+
+	if field.Truncate != nil {
+		steps = append(steps, fmt.Sprintf("truncate %d", field.Truncate.Length))
+	}
+
+	return steps
+}
@@ -0,0 +1,37 @@
+package mantau
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformTrace(t *testing.T) {
+	m := New()
+
+	result, trace, err := m.TransformTrace(User{
+		Name:        "John doe",
+		Permissions: []Permission{{"Admin", 0}, {"Seller", 2}},
+	}, Schema{
+		"username": Field{Key: "name"},
+		"user_permissions": Field{
+			Key:   "permissions",
+			Value: Schema{"code": Field{Key: "permission_code"}},
+		},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.NotNil(t, result, "The result should not be a nil value")
+	assert.Equal(t, []TraceEntry{
+		{Source: "permissions", Output: "user_permissions", Kind: Slice, Type: "[]mantau.Permission", Outcome: OutcomeEmitted},
+		{Source: "permissions[0].permission_code", Output: "user_permissions[0].code", Kind: Other, Type: "int", Outcome: OutcomeEmitted},
+		{Source: "permissions[1].permission_code", Output: "user_permissions[1].code", Kind: Other, Type: "int", Outcome: OutcomeEmitted},
+		{Source: "name", Output: "username", Kind: Other, Type: "string", Outcome: OutcomeEmitted},
+	}, trace.Entries, "The trace do not match")
+
+	encoded, err := json.Marshal(trace)
+
+	assert.NoError(t, err, "Trace should be encoded as JSON")
+	assert.Contains(t, string(encoded), `{"source":"name","output":"username","kind":"other","type":"string","outcome":"emitted"}`)
+}
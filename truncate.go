@@ -0,0 +1,44 @@
+package mantau
+
+import "unicode/utf8"
+
+// Truncation is the maximum length of a text field, see Field.Truncate
+type Truncation struct {
+	// Length is the maximum number of characters of the text, including the ellipsis
+	Length int
+
+	// Ellipsis is appended to a truncated text e.g. "..."
+	Ellipsis string
+}
+
+// Truncate will shorten a text field to n characters, ending with the ellipsis when it's truncated
+func Truncate(n int, ellipsis string) *Truncation {
+	return &Truncation{Length: n, Ellipsis: ellipsis}
+}
+
+// truncateText will shorten a string value on a character boundary, other values are returned as they are.
+// It returns true when the text was longer than the length
+func truncateText(value interface{}, t *Truncation) (interface{}, bool) {
+	text, ok := value.(string)
+
+	if !ok || t.Length < 0 || utf8.RuneCountInString(text) <= t.Length {
+		return value, false
+	}
+
+	ellipsis := t.Ellipsis
+	keep := t.Length - utf8.RuneCountInString(ellipsis)
+
+	// an ellipsis longer than the length would make the text longer, so it's left out
+	if keep < 0 {
+		keep, ellipsis = t.Length, ""
+	}
+
+	end := 0
+
+	for i := 0; i < keep; i++ {
+		_, size := utf8.DecodeRuneInString(text[end:])
+		end += size
+	}
+
+	return text[:end] + ellipsis, true
+}
@@ -0,0 +1,50 @@
+package mantau
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TruncatedPost struct {
+	Title string `mantau:"title,truncate=8"`
+	Body  string `mantau:"body"`
+}
+
+func TestTruncate(t *testing.T) {
+	schema := Schema{
+		"description": Field{Key: "description", Truncate: Truncate(10, "…"), MarkTruncated: true},
+		"summary":     Field{Key: "summary", Truncate: Truncate(10, "..."), MarkTruncated: true},
+		"short":       Field{Key: "short", Truncate: Truncate(2, "...")},
+		"count":       Field{Key: "count", Truncate: Truncate(1, "")},
+	}
+
+	result, err := New().Transform(map[string]interface{}{
+		"description": "Crème brûlée with fresh berries",
+		"summary":     "Short",
+		"short":       "Hello",
+		"count":       12345,
+	}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"description":           "Crème brû…",
+		"description_truncated": true,
+		"summary":               "Short",
+		"short":                 "He",
+		"count":                 12345,
+	}, result, "The result do not match")
+}
+
+func TestTruncateTag(t *testing.T) {
+	m := New()
+	m.SetOpt(&Options{Hook: "mantau"})
+
+	result, err := m.Transform(TruncatedPost{Title: "A very long title", Body: "Body"}, Schema{
+		"title": Field{Key: "title"},
+		"body":  Field{Key: "body"},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"title": "A ver...", "body": "Body"}, result, "The result do not match")
+}
@@ -0,0 +1,96 @@
+package mantau
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Conversion is the unit conversion of a numeric field, see Convert
+type Conversion struct {
+	// From is the unit of the source value e.g. "bytes"
+	From string
+
+	// To is the unit of the transformed value e.g. "MB"
+	To string
+}
+
+// Convert will convert a numeric field from a unit into another, the conversion must be registered
+// with RegisterConversion. The converted value is a float64, it can be rounded with Field.Round
+func Convert(from, to string) *Conversion {
+	return &Conversion{From: from, To: to}
+}
+
+// unitKey is the registry key of a conversion
+type unitKey struct {
+	from, to string
+}
+
+var (
+	unitsMu sync.RWMutex
+	units   = map[unitKey]func(float64) float64{}
+)
+
+func init() {
+	scale := func(factor float64) func(float64) float64 {
+		return func(v float64) float64 {
+			return v * factor
+		}
+	}
+
+	// a power of ten divides to the nearest float where it's inverse doesn't, 1999 * 0.01 is 19.990000000000002
+	divide := func(divisor float64) func(float64) float64 {
+		return func(v float64) float64 {
+			return v / divisor
+		}
+	}
+
+	RegisterConversion("bytes", "KB", divide(1e3))
+	RegisterConversion("bytes", "MB", divide(1e6))
+	RegisterConversion("bytes", "GB", divide(1e9))
+	RegisterConversion("bytes", "KiB", divide(1<<10))
+	RegisterConversion("bytes", "MiB", divide(1<<20))
+	RegisterConversion("bytes", "GiB", divide(1<<30))
+	RegisterConversion("meters", "feet", scale(1/0.3048))
+	RegisterConversion("feet", "meters", scale(0.3048))
+	RegisterConversion("kilometers", "miles", scale(1/1.609344))
+	RegisterConversion("miles", "kilometers", scale(1.609344))
+	RegisterConversion("kilograms", "pounds", scale(1/0.45359237))
+	RegisterConversion("pounds", "kilograms", scale(0.45359237))
+	RegisterConversion("cents", "dollars", divide(100))
+	RegisterConversion("dollars", "cents", scale(100))
+	RegisterConversion("celsius", "fahrenheit", func(v float64) float64 {
+		return v*9/5 + 32
+	})
+	RegisterConversion("fahrenheit", "celsius", func(v float64) float64 {
+		return (v - 32) * 5 / 9
+	})
+}
+
+// RegisterConversion will register the conversion of a value from a unit into another,
+// replacing the previous conversion, if any
+func RegisterConversion(from, to string, fn func(float64) float64) {
+	unitsMu.Lock()
+	defer unitsMu.Unlock()
+
+	units[unitKey{from, to}] = fn
+}
+
+// convert will convert a numeric value with the registered conversion, other values return an error
+func convert(value interface{}, c *Conversion) (interface{}, error) {
+	unitsMu.RLock()
+	fn, ok := units[unitKey{c.From, c.To}]
+	unitsMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("Unknown unit conversion from %q into %q", c.From, c.To)
+	}
+
+	v := reflect.ValueOf(value)
+
+	if !isNumberKind(v.Kind()) {
+		return nil, fmt.Errorf("Cannot convert %T from %s into %s", value, c.From, c.To)
+	}
+
+	return fn(toFloat(v)), nil
+}
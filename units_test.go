@@ -0,0 +1,64 @@
+package mantau
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	schema := Schema{
+		"size":        Field{Key: "size", Convert: Convert("bytes", "MiB"), Round: Round(1)},
+		"altitude":    Field{Key: "altitude", Convert: Convert("meters", "feet"), Round: Round(0)},
+		"price":       Field{Key: "price", Convert: Convert("cents", "dollars"), Round: Round(2), As: "string"},
+		"temperature": Field{Key: "temperature", Convert: Convert("celsius", "fahrenheit")},
+	}
+
+	result, err := New().Transform(map[string]interface{}{
+		"size":        uint64(5 << 20),
+		"altitude":    1000,
+		"price":       1250,
+		"temperature": -40.0,
+	}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"size":        5.0,
+		"altitude":    3281.0,
+		"price":       "12.50",
+		"temperature": -40.0,
+	}, result, "The result do not match")
+
+	result, err = New().Transform(map[string]interface{}{"price": 1999, "size": 1500}, Schema{
+		"price": Field{Key: "price", Convert: Convert("cents", "dollars")},
+		"size":  Field{Key: "size", Convert: Convert("bytes", "KB")},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"price": 19.99, "size": 1.5}, result, "Decimal scales should not add rounding errors")
+}
+
+func TestRegisterConversion(t *testing.T) {
+	RegisterConversion("hours", "minutes", func(v float64) float64 {
+		return v * 60
+	})
+
+	result, err := New().Transform(map[string]interface{}{"duration": 1.5}, Schema{
+		"duration": Field{Key: "duration", Convert: Convert("hours", "minutes")},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"duration": 90.0}, result)
+
+	_, err = New().Transform(map[string]interface{}{"duration": 1.5}, Schema{
+		"duration": Field{Key: "duration", Convert: Convert("hours", "days")},
+	})
+
+	assert.EqualError(t, err, `duration: Unknown unit conversion from "hours" into "days"`)
+
+	_, err = New().Transform(map[string]interface{}{"duration": "long"}, Schema{
+		"duration": Field{Key: "duration", Convert: Convert("hours", "minutes")},
+	})
+
+	assert.EqualError(t, err, `duration: Cannot convert string from hours into minutes`)
+}
@@ -0,0 +1,256 @@
+package mantau
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sourceField is a struct field read by a schema, by it's tag name
+type sourceField struct {
+	name string
+	typ  reflect.Type
+
+	// options are the field options declared by the tag
+	options Field
+}
+
+// ValidateSchema will check the schema against the type of the sample before any transformation, e.g. in a test.
+// It reports struct fields without a tag, schema keys that never match a field of the type, nested schemas
+// on scalar fields and objects without a nested schema, and output keys produced twice by flattened objects,
+// wildcards or key patterns. Nested schemas are checked against the type of their field, the fields of maps
+// and interfaces are only known at runtime so they are not checked. Every problem is a *FieldError matching
+// ErrInvalidSchema ordered by path, nil is returned when the schema is valid
+func (m *mantau) ValidateSchema(schema Schema, sample interface{}) []error {
+	errs := m.validateSchema("", schema, reflect.TypeOf(sample), nil)
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		return pathLess(errs[i].(*FieldError).Path, errs[j].(*FieldError).Path)
+	})
+
+	return errs
+}
+
+// validateSchema will append the problems of the schema with the given type of the source object
+func (m *mantau) validateSchema(path string, schema Schema, t reflect.Type, errs []error) []error {
+	t = objectType(t)
+
+	if t == nil || t.Kind() != reflect.Struct || t == reflect.TypeOf(time.Time{}) {
+		return errs
+	}
+
+	invalid := func(key, format string, args ...interface{}) {
+		p := path
+
+		if key != "" {
+			p = joinPath(path, key)
+		}
+
+		if p == "" {
+			p = "$"
+		}
+
+		errs = append(errs, &FieldError{Path: p, Err: fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidSchema}, args...)...)})
+	}
+
+	fields, err := m.sourceFields(t)
+
+	if err != nil {
+		invalid("", "%v", err)
+	}
+
+	outputs := map[string]string{}
+
+	output := func(key, origin string, allowed bool) {
+		if other, ok := outputs[key]; ok && !allowed {
+			invalid(key, "the output key is produced by %s and %s", other, origin)
+			return
+		}
+
+		outputs[key] = origin
+	}
+
+	keys := make([]string, 0, len(schema))
+
+	for key := range schema {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field := schema[key]
+
+		if key == finalizeKey && !field.isReserved() {
+			invalid(key, "the key %q is reserved", key)
+			continue
+		}
+
+		if !isMapping(key, field) || field.inject != nil || field.computed() {
+			if field.inject != nil || field.computed() {
+				output(key, fmt.Sprintf("%q", key), false)
+			}
+
+			continue
+		}
+
+		if field.KeyPattern != nil {
+			matched := false
+
+			for _, f := range fields {
+				match := field.KeyPattern.FindStringSubmatchIndex(f.name)
+
+				if match == nil {
+					continue
+				}
+
+				matched = true
+				output(string(field.KeyPattern.ExpandString(nil, key, f.name, match)), fmt.Sprintf("the pattern %q", key), m.opt.Collisions != "")
+			}
+
+			if !matched {
+				invalid(key, "the pattern %q doesn't match any field of %s", field.KeyPattern, t)
+			}
+
+			continue
+		}
+
+		source, ok := m.sourceField(fields, field.Key)
+
+		if !ok {
+			invalid(key, "the key %q doesn't match any field of %s", field.Key, t)
+			continue
+		}
+
+		field = field.withTag(source.options)
+		typ := source.typ
+
+		nested, isSchema := field.Value.(Schema)
+
+		if !field.Flatten {
+			output(key, fmt.Sprintf("%q", key), false)
+		} else if isSchema {
+			policy := field.Collisions
+
+			if policy == "" {
+				policy = m.opt.Collisions
+			}
+
+			for nestedKey, nestedField := range nested {
+				if nestedKey != wildcardKey && !nestedField.isReserved() && !nestedField.drop && !strings.Contains(nestedKey, ".") {
+					output(nestedKey, fmt.Sprintf("the flattened %q", key), policy != "")
+				}
+			}
+		}
+
+		// an unknown type is a map or an interface, it's only known at runtime
+		if typ == nil || typ.Kind() == reflect.Interface || m.opt.Mismatch != "" && m.opt.Mismatch != MismatchError {
+			continue
+		}
+
+		container := isContainerType(typ)
+
+		switch {
+		case field.Value != nil && field.Pluck == "" && !container:
+			invalid(key, "has a nested schema but the field %q is %s", field.Key, typ)
+		case field.Value == nil && container && field.MapAs == "" && field.Pluck == "" && field.money == "":
+			invalid(key, "has no nested schema but the field %q is %s", field.Key, typ)
+		case isSchema && field.Pluck == "" && field.MapAs == "":
+			errs = m.validateSchema(joinPath(path, key), nested, typ, errs)
+		}
+	}
+
+	// a dotted output key builds a nested object, it cannot be nested into a value that is not an object
+	for _, key := range keys {
+		if i := strings.Index(key, "."); i > 0 {
+			if field, ok := schema[key[:i]]; ok && field.Value == nil && field.inject == nil && !field.drop && !field.tombstone {
+				invalid(key, "the output key is nested into the value of %q", key[:i])
+			}
+		}
+	}
+
+	if _, ok := schema[wildcardKey]; ok {
+		for _, f := range fields {
+			if !schema.drops(f.name) && !schema.matches(f.name) {
+				output(f.name, "the wildcard", m.opt.Collisions != "")
+			}
+		}
+	}
+
+	return errs
+}
+
+// sourceFields will return the fields of a struct type read by schemas, the first field without a tag
+// returns an error like it does when the type is transformed
+func (m *mantau) sourceFields(t reflect.Type) ([]sourceField, error) {
+	fields := make([]sourceField, 0, t.NumField())
+	var missing error
+
+	for i := 0; i < t.NumField(); i++ {
+		tag, err := m.structFieldName(t, i)
+
+		if err != nil {
+			if missing == nil {
+				missing = fmt.Errorf("the field %s of %s doesn't have a %q tag", t.Field(i).Name, t, m.opt.Hook)
+			}
+
+			continue
+		}
+
+		if name, options := parseTag(tag); name != "" && t.Field(i).PkgPath == "" {
+			fields = append(fields, sourceField{name: name, typ: t.Field(i).Type, options: options})
+		}
+	}
+
+	return fields, missing
+}
+
+// sourceField will find the struct field read by a key, dotted keys are resolved through the nested struct fields.
+// The type of the field is nil when the key goes through a map or an interface, it's only known at runtime
+func (m *mantau) sourceField(fields []sourceField, key string) (sourceField, bool) {
+	segments := strings.Split(key, ".")
+
+	for i, segment := range segments {
+		var found *sourceField
+
+		for j := range fields {
+			if fields[j].name == segment {
+				found = &fields[j]
+				break
+			}
+		}
+
+		if found == nil {
+			return sourceField{}, false
+		}
+
+		if i == len(segments)-1 {
+			return *found, true
+		}
+
+		t := objectType(found.typ)
+
+		if t == nil || t.Kind() != reflect.Struct {
+			return sourceField{}, true
+		}
+
+		fields, _ = m.sourceFields(t)
+	}
+
+	return sourceField{}, false
+}
+
+// objectType will return the type of the objects of a source type, through pointers and collections
+func objectType(t reflect.Type) reflect.Type {
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+
+	return t
+}
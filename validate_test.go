@@ -0,0 +1,87 @@
+package mantau
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSchema(t *testing.T) {
+	m := New()
+
+	errs := m.ValidateSchema(Schema{
+		"username": Field{Key: "name"},
+		"email":    Field{Key: "emial"},
+		"phone":    Field{Key: "phone", Value: Schema{"number": Field{Key: "number"}}},
+		"address":  Field{Key: "user_address"},
+		"user_permissions": Field{
+			Key:   "permissions",
+			Value: Schema{"code": Field{Key: "code"}},
+		},
+		"code":     Field{Key: "user_address.postal_code"},
+		"location": Field{Key: "user_address", Value: Schema{"code": Field{Key: "postal_code"}}, Flatten: true},
+		"products": Field{Key: "products", Value: Schema{"sku": Field{Key: "sku"}}},
+	}, User{})
+
+	messages := make([]string, len(errs))
+
+	for i, err := range errs {
+		assert.True(t, errors.Is(err, ErrInvalidSchema), "The error should match ErrInvalidSchema")
+		messages[i] = err.Error()
+	}
+
+	assert.Equal(t, []string{
+		`address: Invalid schema: has no nested schema but the field "user_address" is mantau.UserAddress`,
+		`code: Invalid schema: the output key is produced by "code" and the flattened "location"`,
+		`email: Invalid schema: the key "emial" doesn't match any field of mantau.User`,
+		`phone: Invalid schema: has a nested schema but the field "phone" is string`,
+		`user_permissions.code: Invalid schema: the key "code" doesn't match any field of mantau.Permission`,
+	}, messages, "The problems do not match")
+
+	assert.Nil(t, m.ValidateSchema(Schema{
+		"username": Field{Key: "name"},
+		"user_permissions": Field{
+			Key:   "permissions",
+			Value: Schema{"code": Field{Key: "permission_code"}},
+		},
+		"address.code": Field{Key: "user_address.postal_code"},
+	}, &User{}), "A valid schema should not return any problem")
+
+	errs = m.ValidateSchema(Schema{"$finalize": Field{Key: "name"}}, User{})
+
+	assert.Len(t, errs, 1, "The reserved key should be reported")
+	assert.EqualError(t, errs[0], `$finalize: Invalid schema: the key "$finalize" is reserved`)
+}
+
+func TestValidateSchemaOutputs(t *testing.T) {
+	m := New()
+
+	errs := m.ValidateSchema(Schema{
+		"name": Field{Key: "email"},
+		"$1":   Field{KeyPattern: regexp.MustCompile(`^(is_active|name)$`)},
+	}, User{})
+
+	assert.Len(t, errs, 1, "The duplicated output key should be reported")
+	assert.EqualError(t, errs[0], `name: Invalid schema: the output key is produced by the pattern "$1" and "name"`)
+
+	errs = m.ValidateSchema(Schema{"phone": Field{Key: "email"}}.Wildcard(), User{})
+
+	assert.Len(t, errs, 1, "The duplicated output key should be reported")
+	assert.EqualError(t, errs[0], `phone: Invalid schema: the output key is produced by "phone" and the wildcard`)
+	assert.Nil(t, m.With(WithCollisions(CollisionOverride)).ValidateSchema(Schema{"phone": Field{Key: "email"}}.Wildcard(), User{}))
+
+	errs = m.ValidateSchema(Schema{
+		"name":    Field{Key: "name"},
+		"address": Field{Key: "address", Value: Schema{"name": Field{Key: "address"}}},
+	}, SquashedUser{})
+
+	assert.Len(t, errs, 1, "The squash tag option should be used")
+	assert.EqualError(t, errs[0], `name: Invalid schema: the output key is produced by the flattened "address" and "name"`)
+
+	errs = m.ValidateSchema(Schema{"name": Field{Key: "full_name"}}, UntaggedUser{})
+
+	assert.EqualError(t, errs[0], `$: Invalid schema: the field UserID of mantau.UntaggedUser doesn't have a "json" tag`)
+	assert.Nil(t, m.With(WithNaming(SnakeCase)).ValidateSchema(Schema{"name": Field{Key: "full_name"}}, UntaggedUser{}))
+}
@@ -0,0 +1,50 @@
+package mantau
+
+import "sort"
+
+// Coverage describes which parts of the schema and the source were used by a transformation.
+// Nested keys are described with a dotted path, collection elements share the same path
+type Coverage struct {
+	// UnmatchedKeys are the schema keys that didn't match any source value
+	UnmatchedKeys []string
+
+	// IgnoredKeys are the source keys that weren't used by the schema
+	IgnoredKeys []string
+}
+
+// TransformVerbose works like Transform but also returns the coverage of the schema and the source
+func (m *mantau) TransformVerbose(src interface{}, schema Schema) (interface{}, *Coverage, error) {
+	c := m.withState()
+	c.state.coverage = &coverage{
+		schemaSeen: map[string]bool{},
+		schemaUsed: map[string]bool{},
+		sourceSeen: map[string]bool{},
+		sourceUsed: map[string]bool{},
+	}
+
+	result, err := c.run(src, schema)
+
+	if err != nil {
+		return result, nil, err
+	}
+
+	return result, &Coverage{
+		UnmatchedKeys: difference(c.state.coverage.schemaSeen, c.state.coverage.schemaUsed),
+		IgnoredKeys:   difference(c.state.coverage.sourceSeen, c.state.coverage.sourceUsed),
+	}, nil
+}
+
+// difference will return the sorted keys which are seen but never used
+func difference(seen, used map[string]bool) []string {
+	keys := []string{}
+
+	for key := range seen {
+		if !used[key] {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,47 @@
+package mantau
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformVerbose(t *testing.T) {
+	m := New()
+
+	result, coverage, err := m.TransformVerbose([]User{
+		{
+			Name:        "John doe",
+			Address:     UserAddress{PostalCode: "809120"},
+			Permissions: []Permission{{"Admin", 0}},
+		},
+	}, Schema{
+		"username": Field{Key: "name"},
+		"nickname": Field{Key: "nick_name"},
+		"address": Field{
+			Key: "user_address",
+			Value: Schema{
+				"code": Field{Key: "postal_code"},
+				"city": Field{Key: "city"},
+			},
+		},
+		"user_permissions": Field{
+			Key: "permissions",
+			Value: Schema{
+				"name": Field{Key: "permission_name"},
+			},
+		},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Len(t, result, 1, "The result do not match")
+	assert.Equal(t, []string{"address.city", "nickname"}, coverage.UnmatchedKeys, "Unmatched keys do not match")
+	assert.Equal(t, []string{
+		"email",
+		"is_active",
+		"permissions.permission_code",
+		"phone",
+		"products",
+		"user_address.address",
+	}, coverage.IgnoredKeys, "Ignored keys do not match")
+}
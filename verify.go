@@ -0,0 +1,151 @@
+package mantau
+
+import (
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"reflect"
+	"sort"
+)
+
+// lazyValueType and batcherType are the types whose internal state changes when the source is transformed
+var (
+	lazyValueType = reflect.TypeOf(&LazyValue{})
+	batcherType   = reflect.TypeOf(&Batcher{})
+)
+
+// sourceHash will hash the source deeply, following pointers, maps, slices and unexported fields,
+// so a change anywhere in the source changes the hash
+func sourceHash(src interface{}) uint64 {
+	h := fnv.New64a()
+	hashDeep(h, reflect.ValueOf(src), map[visit]bool{})
+
+	return h.Sum64()
+}
+
+// visit identifies a pointer, map or slice already hashed. Slices sharing their array are told apart
+// by their type and length
+type visit struct {
+	pointer uintptr
+	typ     reflect.Type
+	len     int
+}
+
+// seen will check if a pointer, map or slice was already hashed and mark it as hashed otherwise
+func seen(v reflect.Value, visited map[visit]bool) bool {
+	key := visit{pointer: v.Pointer(), typ: v.Type()}
+
+	if v.Kind() == reflect.Slice {
+		key.len = v.Len()
+	}
+
+	if visited[key] {
+		return true
+	}
+
+	visited[key] = true
+
+	return false
+}
+
+// hashDeep will write a value and everything it references into the hash.
+// Pointers, maps and slices already visited are written once, so cyclic sources terminate
+func hashDeep(h hash.Hash64, v reflect.Value, visited map[visit]bool) {
+	if !v.IsValid() {
+		fmt.Fprint(h, "nil;")
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			fmt.Fprint(h, "nil;")
+			return
+		}
+
+		if v.Kind() == reflect.Ptr {
+			// lazy values and batchers are resolved by the transformation, they are hashed by their identity
+			if v.Type() == lazyValueType || v.Type() == batcherType {
+				fmt.Fprintf(h, "%s:%x;", v.Type(), v.Pointer())
+				return
+			}
+
+			if seen(v, visited) {
+				fmt.Fprintf(h, "ref:%x;", v.Pointer())
+				return
+			}
+		}
+
+		hashDeep(h, v.Elem(), visited)
+	case reflect.Struct:
+		fmt.Fprintf(h, "%s{", v.Type())
+
+		for i := 0; i < v.NumField(); i++ {
+			hashDeep(h, v.Field(i), visited)
+		}
+
+		fmt.Fprint(h, "}")
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Len() > 0 && seen(v, visited) {
+			fmt.Fprintf(h, "ref:%x:%d;", v.Pointer(), v.Len())
+			return
+		}
+
+		fmt.Fprintf(h, "%s[%d:", v.Type(), v.Len())
+
+		for i := 0; i < v.Len(); i++ {
+			hashDeep(h, v.Index(i), visited)
+		}
+
+		fmt.Fprint(h, "]")
+	case reflect.Map:
+		if !v.IsNil() && seen(v, visited) {
+			fmt.Fprintf(h, "ref:%x;", v.Pointer())
+			return
+		}
+
+		keys := v.MapKeys()
+
+		// map keys are sorted by their formatted value, the iteration order of a map is random
+		formatted := make([]string, len(keys))
+
+		for i, key := range keys {
+			formatted[i] = fmt.Sprintf("%#v", key)
+		}
+
+		sort.Sort(byFormatted{keys, formatted})
+
+		fmt.Fprintf(h, "%s{%d:", v.Type(), v.Len())
+
+		for i, key := range keys {
+			fmt.Fprint(h, formatted[i], ":")
+			hashDeep(h, v.MapIndex(key), visited)
+		}
+
+		fmt.Fprint(h, "}")
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		fmt.Fprintf(h, "%x;", v.Pointer())
+	default:
+		// the value is formatted without Interface, so unexported fields can be read
+		fmt.Fprintf(h, "%v;", v)
+	}
+}
+
+// byFormatted sorts map keys by their formatted value
+type byFormatted struct {
+	keys      []reflect.Value
+	formatted []string
+}
+
+func (b byFormatted) Len() int {
+	return len(b.keys)
+}
+
+func (b byFormatted) Less(i, j int) bool {
+	return b.formatted[i] < b.formatted[j]
+}
+
+func (b byFormatted) Swap(i, j int) {
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+	b.formatted[i], b.formatted[j] = b.formatted[j], b.formatted[i]
+}
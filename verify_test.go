@@ -0,0 +1,112 @@
+package mantau
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type verifiedUser struct {
+	Name string            `json:"name"`
+	Tags []string          `json:"tags"`
+	Meta map[string]string `json:"meta"`
+}
+
+func TestVerifySource(t *testing.T) {
+	user := verifiedUser{Name: "John", Tags: []string{"a", "b"}, Meta: map[string]string{"plan": "pro"}}
+
+	m := New().With(WithVerifySource(true))
+
+	schema := Schema{
+		"name": Field{Key: "name"},
+		"tags": Field{Key: "tags", Transform: func(value interface{}) (interface{}, error) {
+			return len(value.([]string)), nil
+		}},
+	}
+
+	result, err := m.Transform(user, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John", "tags": 2}, result)
+
+	_, err = m.Transform(user, Schema{
+		"tags": Field{Key: "tags", Transform: func(value interface{}) (interface{}, error) {
+			tags := value.([]string)
+			tags[0] = "z"
+
+			return tags, nil
+		}},
+	})
+
+	assert.True(t, errors.Is(err, ErrSourceMutated), "Should detect the mutated slice")
+
+	_, err = m.Transform(user, Schema{
+		"meta": Field{Key: "meta", Value: Schema{"*": Field{}}, Transform: func(value interface{}) (interface{}, error) {
+			user.Meta["plan"] = "free"
+			return value, nil
+		}},
+	})
+
+	assert.True(t, errors.Is(err, ErrSourceMutated), "Should detect the mutated map")
+
+	batcher := NewBatcher(func(keys []interface{}) (map[interface{}]interface{}, error) {
+		return map[interface{}]interface{}{1: "John"}, nil
+	})
+
+	src := map[string]interface{}{
+		"name":   batcher.Load(1),
+		"visits": Lazy(func() (interface{}, error) { return 3, nil }),
+	}
+
+	result, err = m.Transform(src, Schema{"name": Field{Key: "name"}, "visits": Field{Key: "visits"}})
+
+	assert.NoError(t, err, "Resolving lazy values should not mutate the source")
+	assert.Equal(t, Result{"name": "John", "visits": 3}, result)
+}
+
+func TestSourceHash(t *testing.T) {
+	a := map[string]interface{}{"a": 1, "b": []int{1, 2}, "c": map[int]string{1: "x", 2: "y"}}
+	b := map[string]interface{}{"c": map[int]string{2: "y", 1: "x"}, "b": []int{1, 2}, "a": 1}
+
+	assert.Equal(t, sourceHash(a), sourceHash(b), "Should not depend on the map order")
+
+	b["b"].([]int)[1] = 3
+
+	assert.NotEqual(t, sourceHash(a), sourceHash(b), "Should change with a nested value")
+
+	type node struct {
+		value int
+		next  *node
+	}
+
+	cyclic := &node{value: 1}
+	cyclic.next = cyclic
+	before := sourceHash(cyclic)
+	cyclic.value = 2
+
+	assert.NotEqual(t, before, sourceHash(cyclic), "Should hash cyclic and unexported values")
+}
+
+func TestSourceHashCyclicCollections(t *testing.T) {
+	cyclicMap := map[string]interface{}{"name": "John"}
+	cyclicMap["self"] = cyclicMap
+
+	cyclicSlice := []interface{}{"John", nil}
+	cyclicSlice[1] = cyclicSlice
+
+	before := sourceHash(cyclicMap)
+	cyclicMap["name"] = "Jane"
+
+	assert.NotEqual(t, before, sourceHash(cyclicMap), "Should hash maps containing themselves")
+
+	before = sourceHash(cyclicSlice)
+	cyclicSlice[0] = "Jane"
+
+	assert.NotEqual(t, before, sourceHash(cyclicSlice), "Should hash slices containing themselves")
+
+	result, err := New().With(WithVerifySource(true)).Transform(cyclicMap, Schema{"name": Field{Key: "name"}})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "Jane"}, result, "The result do not match")
+}
@@ -0,0 +1,100 @@
+package mantau
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebhookSignatureHeader is the header carrying the signature of a webhook
+const WebhookSignatureHeader = "X-Webhook-Signature"
+
+// WebhookClockSkew is how far in the future a webhook timestamp is accepted, to allow for the clock
+// difference between the sender and the receiver
+const WebhookClockSkew = time.Minute
+
+// ErrInvalidSignature is returned when a webhook signature doesn't match the body
+var ErrInvalidSignature = errors.New("Invalid webhook signature")
+
+// Webhook is a signed webhook envelope
+type Webhook struct {
+	// Body is the encoded {"payload": ..., "timestamp": ...} envelope
+	Body []byte
+
+	// Timestamp is the unix time the webhook was signed at
+	Timestamp int64
+
+	// Signature is the value of the WebhookSignatureHeader header, "t=<timestamp>,v1=<signature>".
+	// The signature is the hex encoded HMAC-SHA256 of "<timestamp>.<body>"
+	Signature string
+}
+
+// SignWebhook will wrap a transformed result into a webhook envelope signed with the secret
+func SignWebhook(secret []byte, payload interface{}, at time.Time) (*Webhook, error) {
+	timestamp := at.Unix()
+	body, err := json.Marshal(map[string]interface{}{
+		"payload":   payload,
+		"timestamp": timestamp,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Webhook{
+		Body:      body,
+		Timestamp: timestamp,
+		Signature: "t=" + strconv.FormatInt(timestamp, 10) + ",v1=" + webhookSignature(secret, timestamp, body),
+	}, nil
+}
+
+// VerifyWebhook will check the signature header value of a webhook body. The header must carry the timestamp,
+// timestamps further in the future than WebhookClockSkew and signatures older than the tolerance are rejected.
+// A zero tolerance accepts any past timestamp
+func VerifyWebhook(secret, body []byte, signature string, tolerance time.Duration) error {
+	var timestamp int64
+	var sig string
+	signed := false
+
+	for _, part := range strings.Split(signature, ",") {
+		switch {
+		case strings.HasPrefix(part, "t="):
+			t, err := strconv.ParseInt(strings.TrimPrefix(part, "t="), 10, 64)
+
+			if err != nil {
+				return ErrInvalidSignature
+			}
+
+			timestamp = t
+			signed = true
+		case strings.HasPrefix(part, "v1="):
+			sig = strings.TrimPrefix(part, "v1=")
+		}
+	}
+
+	if !signed || sig == "" || !hmac.Equal([]byte(sig), []byte(webhookSignature(secret, timestamp, body))) {
+		return ErrInvalidSignature
+	}
+
+	age := time.Since(time.Unix(timestamp, 0))
+
+	if age < -WebhookClockSkew || (tolerance > 0 && age > tolerance) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// webhookSignature will sign the timestamp and the body
+func webhookSignature(secret []byte, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,40 @@
+package mantau
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignWebhook(t *testing.T) {
+	secret := []byte("secret")
+	at := time.Unix(1700000000, 0)
+
+	webhook, err := SignWebhook(secret, Result{"username": "John doe"}, at)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.JSONEq(t, `{"payload": {"username": "John doe"}, "timestamp": 1700000000}`, string(webhook.Body))
+	assert.Equal(t, int64(1700000000), webhook.Timestamp)
+	assert.Regexp(t, `^t=1700000000,v1=[0-9a-f]{64}$`, webhook.Signature)
+
+	assert.NoError(t, VerifyWebhook(secret, webhook.Body, webhook.Signature, 0), "The signature should be valid")
+	assert.Equal(t, ErrInvalidSignature, VerifyWebhook([]byte("other"), webhook.Body, webhook.Signature, 0))
+	assert.Equal(t, ErrInvalidSignature, VerifyWebhook(secret, []byte("{}"), webhook.Signature, 0))
+	assert.Equal(t, ErrInvalidSignature, VerifyWebhook(secret, webhook.Body, webhook.Signature, time.Minute), "An old signature should be rejected")
+
+	unsigned := strings.TrimPrefix(webhook.Signature, "t=1700000000,")
+
+	assert.Equal(t, ErrInvalidSignature, VerifyWebhook(secret, webhook.Body, unsigned, 0), "A signature without timestamp should be rejected")
+
+	future, err := SignWebhook(secret, Result{"username": "John doe"}, time.Now().Add(time.Hour))
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, ErrInvalidSignature, VerifyWebhook(secret, future.Body, future.Signature, 0), "A future signature should be rejected")
+
+	skewed, err := SignWebhook(secret, Result{"username": "John doe"}, time.Now().Add(WebhookClockSkew/2))
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.NoError(t, VerifyWebhook(secret, skewed.Body, skewed.Signature, time.Minute), "The clock skew should be allowed")
+}
@@ -0,0 +1,117 @@
+package mantau
+
+import "strings"
+
+// wildcardKey is the schema key of the field used for every map key without a matching schema field
+const wildcardKey = "*"
+
+// Wildcard will return a copy of the schema passing through every source key without a matching field,
+// so only the keys that need renaming or options have to be declared
+func (s Schema) Wildcard() Schema {
+	schema := make(Schema, len(s)+1)
+
+	for key, field := range s {
+		schema[key] = field
+	}
+
+	schema[wildcardKey] = Field{}
+
+	return schema
+}
+
+// mapWildcard will map a source key of a map or a struct without a matching schema field with the wildcard field,
+// if any. The output key is the source key. A wildcard field without a nested schema passes through nested objects
+// with their own keys
+func (m *mantau) mapWildcard(result Result, flat *[]flattened, key string, value, parent interface{}, schema Schema) error {
+	field, ok := schema[wildcardKey]
+
+	if !ok || field.isReserved() || schema.drops(key) || schema.matches(key) {
+		return nil
+	}
+
+	if field.Value == nil && m.checkMismatch(field, value) != nil {
+		field.Value = passthrough
+	}
+
+	return m.mapOne(result, flat, key, key, field, value, parent, schema)
+}
+
+// passthrough is the nested schema of objects passed through by a wildcard field
+var passthrough = Schema{wildcardKey: Field{}}
+
+// matches will check if a schema field reads from the source key, either by it's key or it's pattern
+func (s Schema) matches(sourceKey string) bool {
+	for key, field := range s {
+		if key == wildcardKey || field.isReserved() || field.inject != nil || field.computed() || field.drop {
+			continue
+		}
+
+		if field.KeyPattern != nil {
+			if field.KeyPattern.MatchString(sourceKey) {
+				return true
+			}
+
+			continue
+		}
+
+		if field.Key == sourceKey {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Drop will create a field that drops the source key named like the schema key, so it's not emitted
+// by the wildcard field or a KeyPattern field while every other key flows through
+func Drop() Field {
+	return Field{drop: true}
+}
+
+// drops will check if the source key is dropped by the schema
+func (s Schema) drops(sourceKey string) bool {
+	return s[sourceKey].drop
+}
+
+// TransformExcept will transform the source passing through every key except the given keys, e.g. "password".
+// Dotted keys like "address.street" exclude a key of a nested object
+func (m *mantau) TransformExcept(src interface{}, keys ...string) (interface{}, error) {
+	return m.Transform(src, Except(keys...))
+}
+
+// Except will create a schema passing through every source key except the given keys, see TransformExcept
+func Except(keys ...string) Schema {
+	schema := Schema{wildcardKey: Field{}}
+
+	for _, key := range keys {
+		schema.except(key)
+	}
+
+	return schema
+}
+
+// except will drop the key from the pass through schema, a dotted key adds the nested schemas of it's path
+func (s Schema) except(key string) {
+	i := strings.Index(key, ".")
+
+	if i < 0 {
+		s[key] = Drop()
+		return
+	}
+
+	name, rest := key[:i], key[i+1:]
+	field, ok := s[name]
+
+	if ok && field.drop {
+		return
+	}
+
+	nested, _ := field.Value.(Schema)
+
+	if nested == nil {
+		nested = Schema{wildcardKey: Field{}}
+		s[name] = Field{Key: name, Value: nested}
+	}
+
+	nested.except(rest)
+}
@@ -0,0 +1,145 @@
+package mantau
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWildcard(t *testing.T) {
+	data := map[string]interface{}{
+		"currency": "USD",
+		"SKU-1":    map[string]interface{}{"product_name": "Apple", "product_qty": 2},
+		"SKU-2":    map[string]interface{}{"product_name": "Orange", "product_qty": 1},
+	}
+
+	result, err := New().Transform(data, Schema{
+		"currency": Field{Key: "currency"},
+		"*": Field{Value: Schema{
+			"name": Field{Key: "product_name"},
+		}},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"currency": "USD",
+		"SKU-1":    Result{"name": "Apple"},
+		"SKU-2":    Result{"name": "Orange"},
+	}, result, "The result do not match")
+
+	_, coverage, err := New().TransformVerbose(data, Schema{
+		"currency": Field{Key: "currency"},
+		"*":        Field{Value: Schema{"name": Field{Key: "product_name"}}},
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.NotContains(t, coverage.UnmatchedKeys, "*", "The wildcard should not be reported as unmatched")
+}
+
+func TestDrop(t *testing.T) {
+	data := map[string]interface{}{
+		"name":          "John doe",
+		"email":         "john@doe.com",
+		"password":      "secret",
+		"metric_secret": 1,
+		"metric_public": 2,
+	}
+
+	result, err := New().Transform(data, Schema{
+		"*":             Field{},
+		"$1":            Field{KeyPattern: regexp.MustCompile(`^metric_(\w+)$`)},
+		"password":      Drop(),
+		"metric_secret": Drop(),
+	})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"name":   "John doe",
+		"email":  "john@doe.com",
+		"public": 2,
+	}, result, "Dropped keys should not be emitted")
+}
+
+func TestWildcardStruct(t *testing.T) {
+	isActive := true
+	src := User{
+		Name:        "John doe",
+		Email:       "john@doe.com",
+		IsActive:    &isActive,
+		Address:     UserAddress{PostalCode: "123", Address: "Main street"},
+		Permissions: []Permission{{PermissionName: "read", PermissionCode: 1}},
+	}
+
+	result, err := New().Transform(src, Schema{
+		"username": Field{Key: "name"},
+		"phone":    Drop(),
+	}.Wildcard())
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"username":     "John doe",
+		"email":        "john@doe.com",
+		"is_active":    true,
+		"user_address": Result{"postal_code": "123", "address": "Main street"},
+		"permissions":  []Result{{"permission_name": "read", "permission_code": 1}},
+		"products":     []Result{},
+	}, result, "The unmapped keys should be passed through")
+}
+
+func TestWildcardHiddenFields(t *testing.T) {
+	type Credentials struct {
+		Login    string `json:"login"`
+		Password string `json:"-"`
+	}
+
+	type Account struct {
+		ID          int         `json:"id"`
+		Credentials Credentials `json:"credentials"`
+	}
+
+	src := Account{ID: 1, Credentials: Credentials{Login: "john", Password: "pw"}}
+
+	result, err := New().Transform(src.Credentials, Schema{}.Wildcard())
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"login": "john"}, result, "Fields tagged \"-\" should not be emitted")
+
+	result, err = New().Transform(src, Schema{"id": Field{Key: "id"}, "password": Field{Key: "credentials.-"}})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"id": 1}, result, "Fields tagged \"-\" should not be read by dotted keys")
+}
+
+func TestTransformExcept(t *testing.T) {
+	src := map[string]interface{}{
+		"name":     "John doe",
+		"password": "secret",
+		"ssn":      "123-45-6789",
+		"address":  map[string]interface{}{"city": "Paris", "street": "Main street"},
+	}
+
+	result, err := New().TransformExcept(src, "password", "ssn", "address.street")
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{
+		"name":    "John doe",
+		"address": Result{"city": "Paris"},
+	}, result, "The excluded keys should not be emitted")
+
+	result, err = New().TransformExcept(User{Name: "John doe", Email: "john@doe.com"}, "phone", "is_active", "user_address", "permissions", "products")
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"name": "John doe", "email": "john@doe.com"}, result, "Should exclude struct fields")
+
+	type Credentials struct {
+		Login    string `json:"login"`
+		Password string `json:"-"`
+		Token    string `json:"token"`
+	}
+
+	result, err = New().TransformExcept(Credentials{Login: "john", Password: "pw", Token: "abc"}, "token")
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, Result{"login": "john"}, result, "Fields tagged \"-\" should not be emitted")
+}
@@ -0,0 +1,269 @@
+package mantau
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"time"
+	"unicode"
+)
+
+// XMLEncoder encodes results as XML, the placement of every key is read from Field.XML of the schema
+// used to transform the result. Keys without a field in the schema are written as child elements
+type XMLEncoder struct {
+	// Root is the name of the root element, defaults to "result"
+	Root string
+
+	// Item is the name of the elements of a collection result, defaults to "item"
+	Item string
+
+	// Schema is the schema of the result
+	Schema Schema
+}
+
+// ContentType will return "application/xml"
+func (e XMLEncoder) ContentType() string {
+	return "application/xml"
+}
+
+// Encode will write the result as an XML document
+func (e XMLEncoder) Encode(w io.Writer, v interface{}) error {
+	root := e.Root
+
+	if root == "" {
+		root = "result"
+	}
+
+	enc := xml.NewEncoder(w)
+
+	if err := encodeXMLRoot(enc, root, e.Item, v, e.Schema); err != nil {
+		return err
+	}
+
+	return enc.Flush()
+}
+
+// encodeXMLRoot will write the root element, a collection result is written as a list of item elements
+func encodeXMLRoot(enc *xml.Encoder, root, item string, v interface{}, schema Schema) error {
+	if !isXMLList(v) {
+		return encodeXMLElement(enc, root, v, schema)
+	}
+
+	if item == "" {
+		item = "item"
+	}
+
+	return encodeXMLList(enc, root, item, v, schema)
+}
+
+// encodeXMLList will write the elements of a collection wrapped into a single element
+func encodeXMLList(enc *xml.Encoder, name, item string, v interface{}, schema Schema) error {
+	start, err := xmlStart(name)
+
+	if err != nil {
+		return err
+	}
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	list := reflect.ValueOf(v)
+
+	for i := 0; i < list.Len(); i++ {
+		if err := encodeXMLElement(enc, item, list.Index(i).Interface(), schema); err != nil {
+			return err
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// encodeXMLElement will write a single element, objects are written with their attributes and child elements
+func encodeXMLElement(enc *xml.Encoder, name string, v interface{}, schema Schema) error {
+	start, err := xmlStart(name)
+
+	if err != nil {
+		return err
+	}
+
+	object, isObject := xmlObject(v)
+
+	if !isObject {
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+
+		if v != nil {
+			if err := enc.EncodeToken(xml.CharData(xmlText(v))); err != nil {
+				return err
+			}
+		}
+
+		return enc.EncodeToken(start.End())
+	}
+
+	keys := make([]string, 0, len(object))
+	schema = schema.outputSchema()
+
+	for key := range object {
+		keys = append(keys, key)
+	}
+
+	// the keys are sorted so the same result is always written the same way
+	sort.Strings(keys)
+
+	children := make([]string, 0, len(keys))
+
+	for _, key := range keys {
+		field := schema[key]
+		value := object[key]
+
+		if !field.XML.Attr {
+			children = append(children, key)
+			continue
+		}
+
+		if value == nil {
+			continue
+		}
+
+		if _, ok := xmlObject(value); ok || isXMLList(value) {
+			return fmt.Errorf("Cannot write the key %q as an XML attribute", key)
+		}
+
+		attr := xmlName(key, field)
+
+		if !isXMLName(attr) {
+			return fmt.Errorf("Cannot write the key %q, %q is not a valid XML name", key, attr)
+		}
+
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: attr}, Value: xmlText(value)})
+	}
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for _, key := range children {
+		field := schema[key]
+		value := object[key]
+		nested, _ := field.Value.(Schema)
+		child := xmlName(key, field)
+
+		if !isXMLList(value) {
+			if err := encodeXMLElement(enc, child, value, nested); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if field.XML.Item != "" {
+			if err := encodeXMLList(enc, child, field.XML.Item, value, nested); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		list := reflect.ValueOf(value)
+
+		for i := 0; i < list.Len(); i++ {
+			if err := encodeXMLElement(enc, child, list.Index(i).Interface(), nested); err != nil {
+				return err
+			}
+		}
+	}
+
+	return enc.EncodeToken(start.End())
+}
+
+// xmlStart will create the start of an element, the name must be a valid XML name
+func xmlStart(name string) (xml.StartElement, error) {
+	if !isXMLName(name) {
+		return xml.StartElement{}, fmt.Errorf("Cannot write the element %q, it's not a valid XML name", name)
+	}
+
+	return xml.StartElement{Name: xml.Name{Local: name}}, nil
+}
+
+// isXMLName will check if the name is an XML name without a namespace prefix, an NCName.
+// It starts with a letter or an underscore followed by letters, digits, underscores, hyphens and dots
+func isXMLName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i, r := range name {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.' || unicode.In(r, unicode.Mn, unicode.Mc)):
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// xmlObject will return the keys and values of a nested object, maps keyed by strings of any value type are objects
+func xmlObject(v interface{}) (map[string]interface{}, bool) {
+	switch object := v.(type) {
+	case Result:
+		return object, true
+	case map[string]interface{}:
+		return object, true
+	case nil:
+		return nil, false
+	}
+
+	value := reflect.ValueOf(v)
+
+	if value.Kind() != reflect.Map || value.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+
+	object := make(map[string]interface{}, value.Len())
+	iter := value.MapRange()
+
+	for iter.Next() {
+		object[iter.Key().String()] = iter.Value().Interface()
+	}
+
+	return object, true
+}
+
+// isXMLList will check if the value is a collection, byte slices are written as text
+func isXMLList(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+
+	t := reflect.TypeOf(v)
+
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() != reflect.Uint8
+}
+
+// xmlName will return the element or attribute name of a key
+func xmlName(key string, field Field) string {
+	if field.XML.Name != "" {
+		return field.XML.Name
+	}
+
+	return key
+}
+
+// xmlText will format a value as the text of an element or attribute
+func xmlText(v interface{}) string {
+	switch value := v.(type) {
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	case []byte:
+		return string(value)
+	}
+
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,93 @@
+package mantau
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXMLEncoder(t *testing.T) {
+	schema := Schema{
+		"id":   Field{Key: "id", XML: XMLPlacement{Attr: true}},
+		"name": Field{Key: "name", XML: XMLPlacement{Name: "full_name"}},
+		"tags": Field{Key: "tags", XML: XMLPlacement{Item: "tag"}},
+		"phones": Field{Key: "phones", Value: Schema{
+			"kind":   Field{Key: "kind", XML: XMLPlacement{Attr: true}},
+			"number": Field{Key: "number"},
+		}},
+	}
+
+	src := map[string]interface{}{
+		"id":   1,
+		"name": "John & Jane",
+		"tags": []string{"a", "b"},
+		"phones": []map[string]interface{}{
+			{"kind": "home", "number": "123"},
+			{"kind": "work", "number": "456"},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	err := New().TransformTo(buf, XMLEncoder{Root: "user", Schema: schema}, src, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, `<user id="1"><full_name>John &amp; Jane</full_name>`+
+		`<phones kind="home"><number>123</number></phones><phones kind="work"><number>456</number></phones>`+
+		`<tags><tag>a</tag><tag>b</tag></tags></user>`, buf.String())
+}
+
+func TestXMLEncoderCollection(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := XMLEncoder{Root: "users", Item: "user"}.Encode(buf, []Result{{"name": "John"}, {"name": nil}})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, `<users><user><name>John</name></user><user><name></name></user></users>`, buf.String())
+}
+
+func TestXMLEncoderAttributeObject(t *testing.T) {
+	schema := Schema{"address": Field{Key: "address", XML: XMLPlacement{Attr: true}}}
+
+	err := XMLEncoder{Schema: schema}.Encode(&bytes.Buffer{}, Result{"address": Result{"city": "Paris"}})
+
+	assert.EqualError(t, err, `Cannot write the key "address" as an XML attribute`)
+}
+
+func TestXMLEncoderNames(t *testing.T) {
+	err := XMLEncoder{}.Encode(&bytes.Buffer{}, Result{"first name": "John"})
+
+	assert.EqualError(t, err, `Cannot write the element "first name", it's not a valid XML name`)
+
+	err = XMLEncoder{}.Encode(&bytes.Buffer{}, Result{"1x": "John"})
+
+	assert.EqualError(t, err, `Cannot write the element "1x", it's not a valid XML name`)
+
+	schema := Schema{"id": Field{Key: "id", XML: XMLPlacement{Name: "user:id", Attr: true}}}
+	err = XMLEncoder{Schema: schema}.Encode(&bytes.Buffer{}, Result{"id": 1})
+
+	assert.EqualError(t, err, `Cannot write the key "id", "user:id" is not a valid XML name`)
+
+	buf := &bytes.Buffer{}
+	err = XMLEncoder{}.Encode(buf, Result{"labels": map[string]string{"env": "prod", "tier": "web"}})
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, `<result><labels><env>prod</env><tier>web</tier></labels></result>`, buf.String(), "Typed maps should be written as objects")
+}
+
+func TestXMLEncoderDottedKeys(t *testing.T) {
+	schema := Schema{
+		"name":         Field{Key: "name"},
+		"address.code": Field{Key: "code", XML: XMLPlacement{Attr: true}},
+		"address.city": Field{Key: "city", XML: XMLPlacement{Name: "town"}},
+	}
+
+	buf := &bytes.Buffer{}
+	err := New().TransformTo(buf, XMLEncoder{Root: "user", Schema: schema}, map[string]interface{}{
+		"name": "John",
+		"code": "123",
+		"city": "Paris",
+	}, schema)
+
+	assert.NoError(t, err, "Should not return any error")
+	assert.Equal(t, `<user><address code="123"><town>Paris</town></address><name>John</name></user>`, buf.String())
+}